@@ -0,0 +1,254 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of the OpenAPI schema object the spec uses.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks a decoded JSON value against schema.
+func (d *Document) Validate(schema *Schema, value interface{}) error {
+	return d.validate(schema, value, "")
+}
+
+func (d *Document) resolve(schema *Schema) (*Schema, error) {
+	for schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		resolved, ok := d.Components.Schemas[name]
+		if !ok {
+			return nil, &ValidationError{Message: "unknown schema " + name}
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, path string) error {
+	schema, err := d.resolve(schema)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return &ValidationError{path, "must not be null"}
+	}
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		return &ValidationError{path, fmt.Sprintf("must be one of %v", schema.Enum)}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return &ValidationError{path, "must be an object"}
+		}
+		return d.validateObject(schema, object, path)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return &ValidationError{path, "must be an array"}
+		}
+		if schema.Items != nil {
+			for i, item := range array {
+				if err := d.validate(schema.Items, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return &ValidationError{path, "must be a string"}
+		}
+		return validateString(schema, s, path)
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return &ValidationError{path, "must be a " + schema.Type}
+		}
+		if schema.Type == "integer" && n != math.Trunc(n) {
+			return &ValidationError{path, "must be an integer"}
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			return &ValidationError{path, fmt.Sprintf("must be at least %v", *schema.Minimum)}
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			return &ValidationError{path, fmt.Sprintf("must be at most %v", *schema.Maximum)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{path, "must be a boolean"}
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, path string) error {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return &ValidationError{joinPath(path, name), "is required"}
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				return &ValidationError{joinPath(path, name), "is not allowed"}
+			}
+			continue
+		}
+		if err := d.validate(property, object[name], joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateString(schema *Schema, s string, path string) error {
+	length := len([]rune(s))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return &ValidationError{path, fmt.Sprintf("must be at least %d characters", *schema.MinLength)}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return &ValidationError{path, fmt.Sprintf("must be at most %d characters", *schema.MaxLength)}
+	}
+	if schema.Pattern != "" {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return &ValidationError{path, "has an invalid pattern"}
+		}
+		if !pattern.MatchString(s) {
+			return &ValidationError{path, "must match " + schema.Pattern}
+		}
+	}
+	if schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			return &ValidationError{path, "must be an RFC 3339 timestamp"}
+		}
+	}
+	return nil
+}
+
+// ValidateParameter checks a raw path, query or header value by converting it
+// to the type of schema first.
+func (d *Document) ValidateParameter(schema *Schema, raw string, name string) error {
+	schema, err := d.resolve(schema)
+	if err != nil {
+		return err
+	}
+
+	var value interface{} = raw
+	switch schema.Type {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return &ValidationError{name, "must be a " + schema.Type}
+		}
+		value = n
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return &ValidationError{name, "must be a boolean"}
+		}
+		value = b
+	}
+	return d.validate(schema, value, name)
+}
+
+// coerceText converts the strings of text formats to the numbers and booleans
+// schema expects. Strings that do not convert are left for validate to report.
+func (d *Document) coerceText(schema *Schema, value interface{}) interface{} {
+	schema, err := d.resolve(schema)
+	if err != nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		switch schema.Type {
+		case "integer", "number":
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				return n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+	case map[string]interface{}:
+		for name, item := range v {
+			if property, ok := schema.Properties[name]; ok {
+				v[name] = d.coerceText(property, item)
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil {
+			for i, item := range v {
+				v[i] = d.coerceText(schema.Items, item)
+			}
+		}
+	}
+	return value
+}
+
+// containsValue compares deeply, as enum values can be arrays and objects,
+// which are not comparable with ==.
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"Data-Category/helper"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Spec is the OpenAPI document of the REST API as served at /api/openapi.json.
+//
+//go:embed APIspec.json
+var Spec []byte
+
+type Document struct {
+	Servers    []Server            `json:"servers"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Server struct {
+	Url string `json:"url"`
+}
+
+// PathItem holds the operations of a path by lower case method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Parameters  []Parameter          `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Responses map[string]*Response `json:"responses"`
+}
+
+// Route is an operation of the document matched against a request.
+type Route struct {
+	Method     string
+	Template   string
+	Operation  *Operation
+	PathParams map[string]string
+}
+
+var pathItemMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*p = PathItem{}
+	for _, method := range pathItemMethods {
+		if field, ok := fields[method]; ok {
+			operation := &Operation{}
+			if err := json.Unmarshal(field, operation); err != nil {
+				return err
+			}
+			(*p)[method] = operation
+		}
+	}
+	return nil
+}
+
+func NewDocument() *Document {
+	document, err := ParseDocument(Spec)
+	if err != nil {
+		panic(err)
+	}
+	return document
+}
+
+func ParseDocument(data []byte) (*Document, error) {
+	document := &Document{}
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// BasePath is the path of the first server, which the paths of the document
+// are relative to.
+func (d *Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(d.Servers[0].Url)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// FindRoute finds the operation for a request path. When several templates
+// match, the one with the most literal segments wins, as in the router.
+func (d *Document) FindRoute(method string, path string) (Route, bool) {
+	basePath := d.BasePath()
+	if !strings.HasPrefix(path, basePath+"/") {
+		return Route{}, false
+	}
+	segments := splitPath(strings.TrimPrefix(path, basePath))
+
+	route, best := Route{}, -1
+	for template, pathItem := range d.Paths {
+		operation, ok := pathItem[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+		params, literals, ok := matchTemplate(splitPath(template), segments)
+		if ok && literals > best {
+			route = Route{Method: method, Template: template, Operation: operation, PathParams: params}
+			best = literals
+		}
+	}
+	return route, best >= 0
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func matchTemplate(template []string, segments []string) (map[string]string, int, bool) {
+	if len(template) != len(segments) {
+		return nil, 0, false
+	}
+	params, literals := map[string]string{}, 0
+	for i, segment := range template {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = segments[i]
+		} else if segment == segments[i] {
+			literals++
+		} else {
+			return nil, 0, false
+		}
+	}
+	return params, literals, true
+}
+
+// ValidateRequest checks the parameters and body of a request against the
+// route. body is the already read request body.
+func (d *Document) ValidateRequest(route Route, r *http.Request, body []byte) error {
+	for _, parameter := range route.Operation.Parameters {
+		var raw string
+		var present bool
+		switch parameter.In {
+		case "path":
+			raw, present = route.PathParams[parameter.Name]
+		case "query":
+			values, ok := r.URL.Query()[parameter.Name]
+			if ok && len(values) > 0 {
+				raw, present = values[0], true
+			}
+		case "header":
+			raw = r.Header.Get(parameter.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+		if !present {
+			if parameter.Required {
+				return &ValidationError{parameter.Name, "is required"}
+			}
+			continue
+		}
+		if parameter.Schema != nil {
+			if err := d.ValidateParameter(parameter.Schema, raw, parameter.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	requestBody := route.Operation.RequestBody
+	if requestBody == nil {
+		return nil
+	}
+	if len(body) == 0 {
+		if requestBody.Required {
+			return &ValidationError{Message: "request body is required"}
+		}
+		return nil
+	}
+	contentType := mediaTypeOf(r.Header.Get("Content-Type"))
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	mediaType, ok := requestBody.Content[contentType]
+	if !ok {
+		return d.validateCodecBody(r, contentType, requestBody, body)
+	}
+	return d.validateBody(contentType, mediaType.Schema, body)
+}
+
+// validateCodecBody checks a body in a media type of the codec registry
+// against the JSON schema of the operation, as handlers decode every codec
+// into the same request. The strings of text formats are converted to the
+// types of the schema first, like the codecs do for the request.
+func (d *Document) validateCodecBody(r *http.Request, contentType string, requestBody *RequestBody, body []byte) error {
+	codec := helper.RequestCodec(r)
+	mediaType, ok := requestBody.Content["application/json"]
+	if !ok || codec.ContentType() != contentType {
+		return &ValidationError{Message: "content type " + contentType + " is not supported"}
+	}
+	if mediaType.Schema == nil {
+		return nil
+	}
+	var value interface{}
+	if err := codec.Decode(bytes.NewReader(body), &value); err != nil {
+		return &ValidationError{Message: "body is not valid " + contentType}
+	}
+	return d.Validate(mediaType.Schema, d.coerceText(mediaType.Schema, value))
+}
+
+// ResolveResponse returns the documented response for a status code, falling
+// back to the default response.
+func (d *Document) ResolveResponse(operation *Operation, status int) (*Response, error) {
+	response, ok := operation.Responses[fmt.Sprint(status)]
+	if !ok {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return nil, fmt.Errorf("status %d is not documented", status)
+	}
+	if response.Ref != "" {
+		name := strings.TrimPrefix(response.Ref, "#/components/responses/")
+		response, ok = d.Components.Responses[name]
+		if !ok {
+			return nil, errors.New("unknown response " + name)
+		}
+	}
+	return response, nil
+}
+
+// ValidateResponse checks a response body against the schema documented for
+// the route, status code and content type.
+func (d *Document) ValidateResponse(route Route, status int, contentType string, body []byte) error {
+	response, err := d.ResolveResponse(route.Operation, status)
+	if err != nil {
+		return err
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+	mediaType, ok := response.Content[mediaTypeOf(contentType)]
+	if !ok {
+		return fmt.Errorf("content type %q is not documented for status %d", contentType, status)
+	}
+	return d.validateBody(mediaTypeOf(contentType), mediaType.Schema, body)
+}
+
+func (d *Document) validateBody(contentType string, schema *Schema, body []byte) error {
+	if schema == nil {
+		return nil
+	}
+	if contentType != "application/json" {
+		return d.Validate(schema, string(body))
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return &ValidationError{Message: "body is not valid JSON"}
+	}
+	return d.Validate(schema, value)
+}
+
+func mediaTypeOf(contentType string) string {
+	return strings.TrimSpace(strings.ToLower(strings.Split(contentType, ";")[0]))
+}
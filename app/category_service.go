@@ -0,0 +1,10 @@
+package app
+
+import "Data-Category/service"
+
+// NewCategoryServiceCached puts the cache in front of the database backed
+// CategoryService. Wire binds CategoryService to the cached service, so the
+// service it decorates is picked here.
+func NewCategoryServiceCached(categoryService *service.CategoryServiceImpl, config service.CategoryCacheConfig) *service.CategoryServiceCached {
+	return service.NewCategoryServiceCached(categoryService, config)
+}
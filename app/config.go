@@ -0,0 +1,167 @@
+package app
+
+import (
+	"Data-Category/event"
+	"Data-Category/helper"
+	"Data-Category/middleware"
+	"Data-Category/model/domain"
+	"Data-Category/service"
+	"crypto/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func NewRateLimitConfig() middleware.RateLimitConfig {
+	return middleware.RateLimitConfig{
+		Read: domain.RateLimitBudget{
+			Limit:  getEnvInt("RATE_LIMIT_READ_LIMIT", 300),
+			Window: getEnvDuration("RATE_LIMIT_READ_WINDOW", time.Minute),
+		},
+		Write: domain.RateLimitBudget{
+			Limit:  getEnvInt("RATE_LIMIT_WRITE_LIMIT", 60),
+			Window: getEnvDuration("RATE_LIMIT_WRITE_WINDOW", time.Minute),
+		},
+		Ip: domain.RateLimitBudget{
+			Limit:  getEnvInt("RATE_LIMIT_IP_LIMIT", 600),
+			Window: getEnvDuration("RATE_LIMIT_IP_WINDOW", time.Minute),
+		},
+	}
+}
+
+func NewCategoryCacheConfig() service.CategoryCacheConfig {
+	return service.CategoryCacheConfig{
+		Enabled:    getEnvBool("CATEGORY_CACHE_ENABLED", true),
+		Size:       getEnvInt("CATEGORY_CACHE_SIZE", 1000),
+		TTL:        getEnvDuration("CATEGORY_CACHE_TTL", 5*time.Minute),
+		MaxLocales: getEnvInt("CATEGORY_CACHE_MAX_LOCALES", 20),
+	}
+}
+
+func NewCategorySuggestConfig() service.CategorySuggestConfig {
+	return service.CategorySuggestConfig{
+		PollInterval: getEnvDuration("CATEGORY_SUGGEST_POLL_INTERVAL", 5*time.Second),
+	}
+}
+
+// NewCategoryDeleteConfig falls back to a random secret, which invalidates
+// the outstanding confirmation tokens on restart and only works with a
+// single instance.
+func NewCategoryDeleteConfig() service.CategoryDeleteConfig {
+	secret := []byte(getEnvString("CATEGORY_DELETE_TOKEN_SECRET", ""))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, err := rand.Read(secret)
+		helper.PanicIfError(err)
+	}
+	return service.CategoryDeleteConfig{
+		TokenSecret: secret,
+		TokenTTL:    getEnvDuration("CATEGORY_DELETE_TOKEN_TTL", 5*time.Minute),
+	}
+}
+
+func NewTenantConfig() service.TenantConfig {
+	return service.TenantConfig{
+		AdminApiKey: getEnvString("ADMIN_API_KEY", "RAHASIA"),
+	}
+}
+
+func NewWebhookConfig() service.WebhookConfig {
+	return service.WebhookConfig{
+		PollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		BatchSize:    getEnvInt("WEBHOOK_BATCH_SIZE", 20),
+		MaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		BaseBackoff:  getEnvDuration("WEBHOOK_BASE_BACKOFF", 10*time.Second),
+		MaxBackoff:   getEnvDuration("WEBHOOK_MAX_BACKOFF", time.Hour),
+		Timeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+		AllowPrivateTargets: getEnvBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+	}
+}
+
+func NewEventBusConfig() event.EventBusConfig {
+	return event.EventBusConfig{
+		Async:      getEnvBool("EVENT_BUS_ASYNC", false),
+		BufferSize: getEnvInt("EVENT_BUS_BUFFER_SIZE", 256),
+		Log:        getEnvBool("EVENT_BUS_LOG", false),
+	}
+}
+
+func NewGrpcConfig() GrpcConfig {
+	return GrpcConfig{
+		Addr: getEnvString("GRPC_ADDR", "localhost:3001"),
+	}
+}
+
+func NewOpenApiConfig() middleware.OpenApiConfig {
+	return middleware.OpenApiConfig{
+		ValidateRequests: getEnvBool("OPENAPI_VALIDATE_REQUESTS", false),
+	}
+}
+
+func NewIdempotencyConfig() middleware.IdempotencyConfig {
+	return middleware.IdempotencyConfig{
+		TTL: getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+	}
+}
+
+func NewCompressionConfig() middleware.CompressionConfig {
+	return middleware.CompressionConfig{
+		MinSize: getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+	}
+}
+
+func NewLocaleConfig() middleware.LocaleConfig {
+	config := middleware.LocaleConfig{}
+	for _, locale := range strings.Split(getEnvString("CATEGORY_LOCALE_FALLBACK", ""), ",") {
+		if locale, ok := helper.CanonicalLocale(strings.TrimSpace(locale)); ok {
+			config.Fallback = append(config.Fallback, locale)
+		}
+	}
+	return config
+}
+
+func getEnvString(key string, fallback string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	return value
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		panic("invalid value for " + key + ": " + value)
+	}
+	return result
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		panic("invalid value for " + key + ": " + value)
+	}
+	return result
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		panic("invalid value for " + key + ": " + value)
+	}
+	return result
+}
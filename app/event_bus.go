@@ -0,0 +1,23 @@
+package app
+
+import (
+	"Data-Category/event"
+	"Data-Category/service"
+)
+
+// NewEventSubscribers lists the subscribers of the EventBus. A new
+// subscriber gets its own provider and is added here.
+func NewEventSubscribers(config event.EventBusConfig, logSubscriber *event.LogSubscriber, suggestIndex *service.CategorySuggestIndex) []event.Subscriber {
+	subscribers := []event.Subscriber{suggestIndex}
+	if config.Log {
+		subscribers = append(subscribers, logSubscriber)
+	}
+	return subscribers
+}
+
+func NewEventBus(config event.EventBusConfig, subscribers []event.Subscriber) event.EventBus {
+	if config.Async {
+		return event.NewAsyncEventBus(subscribers, config.BufferSize)
+	}
+	return event.NewSyncEventBus(subscribers)
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"Data-Category/exception"
+	"Data-Category/middleware"
+	"Data-Category/pb"
+
+	"google.golang.org/grpc"
+)
+
+type GrpcConfig struct {
+	Addr string
+}
+
+func NewGrpcServer(cs pb.CategoryServiceServer, ai *middleware.AuthInterceptor) *grpc.Server {
+	// Authentication runs inside the error handler, so a panic while
+	// authenticating is reported as a status as well.
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(exception.GrpcUnaryErrorHandler, ai.Unary),
+		grpc.ChainStreamInterceptor(exception.GrpcStreamErrorHandler, ai.Stream),
+	)
+	pb.RegisterCategoryServiceServer(s, cs)
+	return s
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"Data-Category/api"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RouteReport is the difference between the routes of the router and the
+// operations documented in APIspec.json, as "METHOD /path" entries.
+type RouteReport struct {
+	Undocumented []string
+	Missing      []string
+}
+
+func (rr RouteReport) Ok() bool {
+	return len(rr.Undocumented) == 0 && len(rr.Missing) == 0
+}
+
+func (rr RouteReport) String() string {
+	if rr.Ok() {
+		return "routes match APIspec.json\n"
+	}
+	var b strings.Builder
+	if len(rr.Undocumented) > 0 {
+		fmt.Fprintf(&b, "%d route(s) not documented in APIspec.json:\n", len(rr.Undocumented))
+		for _, route := range rr.Undocumented {
+			fmt.Fprintf(&b, "  %s\n", route)
+		}
+	}
+	if len(rr.Missing) > 0 {
+		fmt.Fprintf(&b, "%d operation(s) documented in APIspec.json but not routed:\n", len(rr.Missing))
+		for _, route := range rr.Missing {
+			fmt.Fprintf(&b, "  %s\n", route)
+		}
+	}
+	return b.String()
+}
+
+var routeParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// CheckRoutes walks the router and compares methods and path templates with
+// the paths of document. Parameters are compared by position only, so
+// {id} and {categoryId} in the same place are the same route.
+func CheckRoutes(routes chi.Routes, document *api.Document) (RouteReport, error) {
+	routed := map[string]string{}
+	err := chi.Walk(routes, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route = routeParamPattern.ReplaceAllString(route, "{$1}")
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		routed[routeKey(method, route)] = method + " " + route
+		return nil
+	})
+	if err != nil {
+		return RouteReport{}, err
+	}
+
+	documented := map[string]string{}
+	basePath := document.BasePath()
+	for template, pathItem := range document.Paths {
+		for method := range pathItem {
+			method = strings.ToUpper(method)
+			documented[routeKey(method, basePath+template)] = method + " " + basePath + template
+		}
+	}
+
+	report := RouteReport{}
+	for key, route := range routed {
+		if _, ok := documented[key]; !ok {
+			report.Undocumented = append(report.Undocumented, route)
+		}
+	}
+	for key, route := range documented {
+		if _, ok := routed[key]; !ok {
+			report.Missing = append(report.Missing, route)
+		}
+	}
+	sort.Strings(report.Undocumented)
+	sort.Strings(report.Missing)
+	return report, nil
+}
+
+func routeKey(method string, route string) string {
+	return method + " " + routeParamPattern.ReplaceAllString(route, "{}")
+}
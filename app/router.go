@@ -3,24 +3,84 @@ package app
 import (
 	"Data-Category/controller"
 	"Data-Category/exception"
+	"Data-Category/middleware"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func NewRouter(cc controller.CategoryController) *chi.Mux {
+func NewRouter(cc controller.CategoryController, chc controller.CacheController, tc controller.TenantController, wc controller.WebhookController, ic controller.ItemController, gc controller.GraphqlController, oc controller.OpenApiController, cpm *middleware.CompressionMiddleware, cm *middleware.ContentNegotiationMiddleware, im *middleware.IdempotencyMiddleware, rm *middleware.RateLimitMiddleware, om *middleware.OpenApiMiddleware, lm *middleware.LocaleMiddleware) *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(cpm.Wrap)
+	r.Use(cm.Wrap)
 	r.Use(exception.ErrorHandler)
+	r.Use(rm.Wrap)
+	r.Use(om.Wrap)
+	r.Use(lm.Wrap)
+
+	r.Get("/api/openapi.json", oc.Spec)
+
+	r.With(middleware.RequireAdmin).Get("/api/cache/stats", chc.Stats)
+
+	r.Route("/api/tenants", func(r chi.Router) {
+		r.Use(middleware.RequireAdmin)
+
+		r.Get("/", tc.FindAll)
+		r.Post("/", tc.Create)
+		r.Post("/{tenantId}/disable", tc.DisableById)
+	})
+
+	r.Route("/api/webhooks", func(r chi.Router) {
+		r.Get("/", wc.FindAll)
+		r.Post("/", wc.Create)
+
+		r.Route("/{webhookId}", func(r chi.Router) {
+			r.Get("/", wc.FindById)
+			r.Delete("/", wc.DeleteById)
+			r.Get("/deliveries", wc.FindDeliveries)
+		})
+	})
+
+	r.Route("/api/items", func(r chi.Router) {
+		r.Get("/", ic.FindAll)
+		r.With(im.Wrap).Post("/", ic.Create)
+
+		r.Route("/{itemId}", func(r chi.Router) {
+			r.Get("/", ic.FindById)
+			r.Put("/", ic.UpdateById)
+			r.Delete("/", ic.DeleteById)
+		})
+	})
+
+	r.Post("/graphql", gc.Query)
 
 	r.Route("/api/categories", func(r chi.Router) {
 
 		r.Get("/", cc.FindAll)
-		r.Post("/", cc.Create)
+		r.With(im.Wrap).Post("/", cc.Create)
 		r.Delete("/", cc.DeleteAll)
+		r.Get("/by-slug/{slug}", cc.FindBySlug)
+		r.Get("/changes", cc.FindChanges)
+		r.Get("/search", cc.Search)
+		r.Get("/suggest", cc.Suggest)
+		r.Get("/events", cc.Events)
+		r.Put("/children", cc.Reorder)
 
 		r.Route("/{categoryId}", func(r chi.Router) {
 			r.Get("/", cc.FindById)
 			r.Put("/", cc.UpdateById)
 			r.Delete("/", cc.DeleteById)
+			r.With(im.Wrap).Post("/merge", cc.Merge)
+			r.With(im.Wrap).Post("/move", cc.Move)
+			r.Get("/children", cc.FindChildren)
+			r.Put("/children", cc.Reorder)
+			r.Get("/subtree", cc.FindSubtree)
+			r.Get("/aliases", cc.FindAliases)
+			r.With(im.Wrap).Post("/aliases", cc.CreateAlias)
+			r.Delete("/aliases/{aliasId}", cc.DeleteAlias)
+			r.Get("/translations", cc.FindTranslations)
+			r.Put("/translations/{locale}", cc.SetTranslation)
+			r.Delete("/translations/{locale}", cc.DeleteTranslation)
+			r.Get("/items", ic.FindByCategory)
 		})
 	})
 
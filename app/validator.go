@@ -0,0 +1,24 @@
+package app
+
+import (
+	"Data-Category/helper"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+
+	err := validate.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return helper.IsSlug(fl.Field().String())
+	})
+	helper.PanicIfError(err)
+
+	err = validate.RegisterValidation("locale", func(fl validator.FieldLevel) bool {
+		_, ok := helper.CanonicalLocale(fl.Field().String())
+		return ok
+	})
+	helper.PanicIfError(err)
+
+	return validate
+}
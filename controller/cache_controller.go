@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"net/http"
+)
+
+type CacheController interface {
+	Stats(w http.ResponseWriter, r *http.Request)
+}
+
+type CacheControllerImpl struct {
+	CategoryServiceCached *service.CategoryServiceCached
+}
+
+func NewCacheController(categoryServiceCached *service.CategoryServiceCached) *CacheControllerImpl {
+	return &CacheControllerImpl{
+		CategoryServiceCached: categoryServiceCached,
+	}
+}
+
+func (cc *CacheControllerImpl) Stats(w http.ResponseWriter, r *http.Request) {
+	cacheStatsResponse := cc.CategoryServiceCached.Stats()
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   cacheStatsResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
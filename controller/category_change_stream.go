@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+)
+
+// changeReplayPageSize is the page size in which the change feed is replayed.
+const changeReplayPageSize = 1000
+
+// categoryChangeStream is the replay-then-live protocol shared by the Events
+// endpoint and the Watch RPC. Changes are sent in sequence order without
+// gaps: the transactions of a tenant take their sequences one after another,
+// but their changes are published after the commit and can arrive out of
+// order. A change that arrives ahead of its predecessors makes the stream
+// read the missing ones from the change feed, where they are committed
+// already, instead of skipping them.
+type categoryChangeStream struct {
+	CategoryService service.CategoryService
+	// Cursor is the sequence of the last change sent.
+	Cursor int64
+	send   func(change web.CategoryChangeResponse) error
+}
+
+// newCategoryChangeStream starts after the given cursor, or after the latest
+// change when resume is false.
+func newCategoryChangeStream(ctx context.Context, categoryService service.CategoryService, cursor int64, resume bool, send func(change web.CategoryChangeResponse) error) *categoryChangeStream {
+	if !resume {
+		cursor = categoryService.FindLatestChange(ctx).Sequence
+	}
+	return &categoryChangeStream{
+		CategoryService: categoryService,
+		Cursor:          cursor,
+		send:            send,
+	}
+}
+
+// Replay sends the changes after the cursor from the change feed.
+func (cs *categoryChangeStream) Replay(ctx context.Context) error {
+	for {
+		categoryChangesResponse := cs.CategoryService.FindChanges(ctx, web.CategoryChangesRequest{Cursor: cs.Cursor, Limit: changeReplayPageSize})
+		for _, change := range categoryChangesResponse.Changes {
+			if err := cs.send(change); err != nil {
+				return err
+			}
+			cs.Cursor = change.Sequence
+		}
+		if !categoryChangesResponse.HasMore {
+			return nil
+		}
+	}
+}
+
+// Deliver sends a change received from the broker once every change before
+// it has been sent, and skips changes that were sent already.
+func (cs *categoryChangeStream) Deliver(ctx context.Context, change web.CategoryChangeResponse) error {
+	if change.Sequence > cs.Cursor+1 {
+		if err := cs.Replay(ctx); err != nil {
+			return err
+		}
+	}
+	if change.Sequence <= cs.Cursor {
+		return nil
+	}
+	if err := cs.send(change); err != nil {
+		return err
+	}
+	cs.Cursor = change.Sequence
+	return nil
+}
@@ -1,11 +1,21 @@
 package controller
 
 import (
+	"Data-Category/exception"
 	"Data-Category/helper"
 	"Data-Category/model/web"
 	"Data-Category/service"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -16,16 +26,38 @@ type CategoryController interface {
 	DeleteAll(w http.ResponseWriter, r *http.Request)
 	UpdateById(w http.ResponseWriter, r *http.Request)
 	FindById(w http.ResponseWriter, r *http.Request)
+	FindBySlug(w http.ResponseWriter, r *http.Request)
 	DeleteById(w http.ResponseWriter, r *http.Request)
+	Merge(w http.ResponseWriter, r *http.Request)
+	Move(w http.ResponseWriter, r *http.Request)
+	FindChildren(w http.ResponseWriter, r *http.Request)
+	Reorder(w http.ResponseWriter, r *http.Request)
+	FindSubtree(w http.ResponseWriter, r *http.Request)
+	FindAliases(w http.ResponseWriter, r *http.Request)
+	CreateAlias(w http.ResponseWriter, r *http.Request)
+	DeleteAlias(w http.ResponseWriter, r *http.Request)
+	FindTranslations(w http.ResponseWriter, r *http.Request)
+	SetTranslation(w http.ResponseWriter, r *http.Request)
+	DeleteTranslation(w http.ResponseWriter, r *http.Request)
+	FindChanges(w http.ResponseWriter, r *http.Request)
+	Search(w http.ResponseWriter, r *http.Request)
+	Suggest(w http.ResponseWriter, r *http.Request)
+	Events(w http.ResponseWriter, r *http.Request)
 }
 
 type CategoryControllerImpl struct {
-	CategoryService service.CategoryService
+	CategoryService   service.CategoryService
+	HeartbeatInterval time.Duration
+	// CacheControl is sent with FindAll, whose validators come from the
+	// latest change so caches can revalidate without transferring the list.
+	CacheControl string
 }
 
 func NewCategoryController(categoryService service.CategoryService) *CategoryControllerImpl {
 	return &CategoryControllerImpl{
-		CategoryService: categoryService,
+		CategoryService:   categoryService,
+		HeartbeatInterval: 15 * time.Second,
+		CacheControl:      "no-cache",
 	}
 }
 
@@ -45,7 +77,38 @@ func (cc *CategoryControllerImpl) Create(w http.ResponseWriter, r *http.Request)
 }
 
 func (cc *CategoryControllerImpl) FindAll(w http.ResponseWriter, r *http.Request) {
-	categoryResponses := cc.CategoryService.FindAll(r.Context())
+	categoryFindAllRequest := web.CategoryFindAllRequest{}
+	if updatedSince := r.URL.Query().Get("updated_since"); updatedSince != "" {
+		t, err := time.Parse(time.RFC3339Nano, updatedSince)
+		if err != nil {
+			panic(exception.NewBadRequestError("updated_since must be an RFC 3339 timestamp"))
+		}
+		categoryFindAllRequest.UpdatedSince = t
+	}
+	// Attribute filters come as attributes[name]=value.
+	for key, values := range r.URL.Query() {
+		if strings.HasPrefix(key, "attributes[") && strings.HasSuffix(key, "]") {
+			if categoryFindAllRequest.Attributes == nil {
+				categoryFindAllRequest.Attributes = map[string]string{}
+			}
+			categoryFindAllRequest.Attributes[key[len("attributes["):len(key)-1]] = values[0]
+		}
+	}
+
+	latestChange := cc.CategoryService.FindLatestChange(r.Context())
+	etag := categoriesETag(r, latestChange.Sequence)
+	w.Header().Set("Cache-Control", cc.CacheControl)
+	w.Header().Add("Vary", "X-API-Key")
+	w.Header().Set("ETag", etag)
+	if !latestChange.ChangedAt.IsZero() {
+		w.Header().Set("Last-Modified", latestChange.ChangedAt.UTC().Format(http.TimeFormat))
+	}
+	if helper.NotModified(r, etag, latestChange.ChangedAt) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	categoryResponses := cc.CategoryService.FindAll(r.Context(), categoryFindAllRequest)
 
 	webResponse := web.WebResponse{
 		Code:   200,
@@ -56,12 +119,52 @@ func (cc *CategoryControllerImpl) FindAll(w http.ResponseWriter, r *http.Request
 	helper.WriteToResponseBody(w, webResponse)
 }
 
+// categoriesETag identifies the list of the caller's tenant at a sequence of
+// its change feed. The query and the locales are part of it, as they filter
+// the list and translate its names.
+func categoriesETag(r *http.Request, sequence int64) string {
+	etag := strconv.Itoa(helper.TenantIdFromContext(r.Context())) + "-" + strconv.FormatInt(sequence, 10)
+	locales := helper.LocalesFromContext(r.Context())
+	if r.URL.RawQuery != "" || len(locales) > 0 {
+		hash := sha256.Sum256([]byte(r.URL.RawQuery + "\n" + strings.Join(locales, ",")))
+		etag += "-" + hex.EncodeToString(hash[:8])
+	}
+	return `W/"` + etag + `"`
+}
+
 func (cc *CategoryControllerImpl) DeleteAll(w http.ResponseWriter, r *http.Request) {
-	cc.CategoryService.DeleteAll(r.Context())
+	categoryDeleteAllRequest := web.CategoryDeleteAllRequest{
+		Name:              r.URL.Query().Get("name"),
+		ConfirmationToken: r.URL.Query().Get("confirmation_token"),
+	}
+	if tenantId := r.URL.Query().Get("tenant_id"); tenantId != "" {
+		t, err := strconv.Atoi(tenantId)
+		if err != nil {
+			panic(exception.NewBadRequestError("tenant_id must be an integer"))
+		}
+		categoryDeleteAllRequest.TenantId = t
+	}
+	if subtreeId := r.URL.Query().Get("subtree_id"); subtreeId != "" {
+		s, err := strconv.Atoi(subtreeId)
+		if err != nil {
+			panic(exception.NewBadRequestError("subtree_id must be an integer"))
+		}
+		categoryDeleteAllRequest.SubtreeId = s
+	}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		b, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			panic(exception.NewBadRequestError("dry_run must be a boolean"))
+		}
+		categoryDeleteAllRequest.DryRun = b
+	}
+
+	categoryDeleteAllResponse := cc.CategoryService.DeleteAll(r.Context(), categoryDeleteAllRequest)
 
 	webResponse := web.WebResponse{
 		Code:   200,
 		Status: "OK",
+		Data:   categoryDeleteAllResponse,
 	}
 
 	helper.WriteToResponseBody(w, webResponse)
@@ -104,6 +207,36 @@ func (cc *CategoryControllerImpl) FindById(w http.ResponseWriter, r *http.Reques
 	helper.WriteToResponseBody(w, webResponse)
 }
 
+func (cc *CategoryControllerImpl) FindBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	categorySlugResponse := cc.CategoryService.FindBySlug(r.Context(), slug)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categorySlugResponse.Category,
+	}
+
+	if categorySlugResponse.Moved {
+		location := "/api/categories/by-slug/" + categorySlugResponse.Category.Slug
+		w.Header().Set("Location", location)
+		helper.SetContentType(w)
+		w.WriteHeader(http.StatusMovedPermanently)
+		webResponse = web.WebResponse{
+			Code:   http.StatusMovedPermanently,
+			Status: "Moved Permanently",
+			Data: web.CategoryRedirectResponse{
+				Slug:     categorySlugResponse.Category.Slug,
+				Location: location,
+				Category: categorySlugResponse.Category,
+			},
+		}
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
 func (cc *CategoryControllerImpl) DeleteById(w http.ResponseWriter, r *http.Request) {
 	categoryId := chi.URLParam(r, "categoryId")
 	id, err := strconv.Atoi(categoryId)
@@ -118,3 +251,378 @@ func (cc *CategoryControllerImpl) DeleteById(w http.ResponseWriter, r *http.Requ
 
 	helper.WriteToResponseBody(w, webResponse)
 }
+
+func (cc *CategoryControllerImpl) Merge(w http.ResponseWriter, r *http.Request) {
+	categoryMergeRequest := web.CategoryMergeRequest{}
+	helper.ReadFromRequestBody(r, &categoryMergeRequest)
+
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryMergeRequest.TargetId = id
+
+	categoryResponse := cc.CategoryService.Merge(r.Context(), categoryMergeRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) Move(w http.ResponseWriter, r *http.Request) {
+	categoryMoveRequest := web.CategoryMoveRequest{}
+	helper.ReadFromRequestBody(r, &categoryMoveRequest)
+
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryMoveRequest.Id = id
+
+	categoryResponse := cc.CategoryService.Move(r.Context(), categoryMoveRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) FindChildren(w http.ResponseWriter, r *http.Request) {
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryResponses := cc.CategoryService.FindChildren(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) Reorder(w http.ResponseWriter, r *http.Request) {
+	categoryReorderRequest := web.CategoryReorderRequest{}
+	helper.ReadFromRequestBody(r, &categoryReorderRequest)
+
+	// Without a category in the path the root categories are reordered.
+	categoryReorderRequest.ParentId = nil
+	if categoryId := chi.URLParam(r, "categoryId"); categoryId != "" {
+		id, err := strconv.Atoi(categoryId)
+		helper.PanicIfError(err)
+		categoryReorderRequest.ParentId = &id
+	}
+
+	categoryResponses := cc.CategoryService.Reorder(r.Context(), categoryReorderRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) FindSubtree(w http.ResponseWriter, r *http.Request) {
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryResponses := cc.CategoryService.FindSubtree(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) FindAliases(w http.ResponseWriter, r *http.Request) {
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryAliasResponses := cc.CategoryService.FindAliases(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryAliasResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	categoryAliasCreateRequest := web.CategoryAliasCreateRequest{}
+	helper.ReadFromRequestBody(r, &categoryAliasCreateRequest)
+
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryAliasCreateRequest.CategoryId = id
+
+	categoryAliasResponse := cc.CategoryService.CreateAlias(r.Context(), categoryAliasCreateRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryAliasResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := strconv.Atoi(chi.URLParam(r, "categoryId"))
+	helper.PanicIfError(err)
+	aliasId, err := strconv.Atoi(chi.URLParam(r, "aliasId"))
+	helper.PanicIfError(err)
+
+	cc.CategoryService.DeleteAlias(r.Context(), categoryId, aliasId)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) FindTranslations(w http.ResponseWriter, r *http.Request) {
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryTranslationResponses := cc.CategoryService.FindTranslations(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryTranslationResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	categoryTranslationRequest := web.CategoryTranslationRequest{}
+	helper.ReadFromRequestBody(r, &categoryTranslationRequest)
+
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	categoryTranslationRequest.CategoryId = id
+	categoryTranslationRequest.Locale = chi.URLParam(r, "locale")
+
+	categoryTranslationResponse := cc.CategoryService.SetTranslation(r.Context(), categoryTranslationRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryTranslationResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := strconv.Atoi(chi.URLParam(r, "categoryId"))
+	helper.PanicIfError(err)
+
+	cc.CategoryService.DeleteTranslation(r.Context(), categoryId, chi.URLParam(r, "locale"))
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) FindChanges(w http.ResponseWriter, r *http.Request) {
+	categoryChangesRequest := web.CategoryChangesRequest{
+		Limit: 100,
+	}
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		c, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			panic(exception.NewBadRequestError("cursor must be an integer"))
+		}
+		categoryChangesRequest.Cursor = c
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			panic(exception.NewBadRequestError("limit must be an integer"))
+		}
+		categoryChangesRequest.Limit = l
+	}
+
+	categoryChangesResponse := cc.CategoryService.FindChanges(r.Context(), categoryChangesRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryChangesResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) Search(w http.ResponseWriter, r *http.Request) {
+	categorySearchRequest := web.CategorySearchRequest{
+		Query: r.URL.Query().Get("q"),
+		Limit: 20,
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			panic(exception.NewBadRequestError("limit must be an integer"))
+		}
+		categorySearchRequest.Limit = l
+	}
+	if tenantId := r.URL.Query().Get("tenant_id"); tenantId != "" {
+		t, err := strconv.Atoi(tenantId)
+		if err != nil {
+			panic(exception.NewBadRequestError("tenant_id must be an integer"))
+		}
+		categorySearchRequest.TenantId = t
+	}
+	if subtreeId := r.URL.Query().Get("subtree_id"); subtreeId != "" {
+		s, err := strconv.Atoi(subtreeId)
+		if err != nil {
+			panic(exception.NewBadRequestError("subtree_id must be an integer"))
+		}
+		categorySearchRequest.SubtreeId = s
+	}
+
+	categorySearchResponses := cc.CategoryService.Search(r.Context(), categorySearchRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categorySearchResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (cc *CategoryControllerImpl) Suggest(w http.ResponseWriter, r *http.Request) {
+	categorySuggestRequest := web.CategorySuggestRequest{
+		Prefix: r.URL.Query().Get("prefix"),
+		Limit:  10,
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			panic(exception.NewBadRequestError("limit must be an integer"))
+		}
+		categorySuggestRequest.Limit = l
+	}
+
+	categorySuggestResponses := cc.CategoryService.Suggest(r.Context(), categorySuggestRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categorySuggestResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+// Events streams category changes as Server-Sent Events. A client that
+// reconnects with Last-Event-ID first receives the changes it missed from the
+// change feed. Live changes are subscribed to before the replay and skipped
+// when already replayed, so nothing falls in between.
+func (cc *CategoryControllerImpl) Events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		panic(errors.New("streaming is not supported"))
+	}
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.URL.Query().Get("last_event_id")
+	}
+	var cursor int64
+	if lastEventId != "" {
+		c, err := strconv.ParseInt(lastEventId, 10, 64)
+		if err != nil || c < 0 {
+			panic(exception.NewBadRequestError("Last-Event-ID must be a non-negative integer"))
+		}
+		cursor = c
+	}
+
+	changes, unsubscribe := cc.CategoryService.Subscribe(r.Context())
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// The status is sent, so exception.ErrorHandler cannot answer errors
+	// any more. They end the stream with an error event instead, and the
+	// client reconnects with Last-Event-ID.
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			log.Printf("category events: %v", rvr)
+			io.WriteString(w, "event: error\ndata: internal error\n\n")
+			flusher.Flush()
+		}
+	}()
+
+	stream := newCategoryChangeStream(r.Context(), cc.CategoryService, cursor, lastEventId != "", func(change web.CategoryChangeResponse) error {
+		writeEvent(w, change)
+		flusher.Flush()
+		return nil
+	})
+	if lastEventId != "" {
+		helper.PanicIfError(stream.Replay(r.Context()))
+	}
+
+	heartbeat := time.NewTicker(cc.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				// The subscriber fell behind, the client resumes with Last-Event-ID.
+				return
+			}
+			helper.PanicIfError(stream.Deliver(r.Context(), change))
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, change web.CategoryChangeResponse) {
+	data, err := json.Marshal(change)
+	helper.PanicIfError(err)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", change.Sequence, change.Operation, data)
+}
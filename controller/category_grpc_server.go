@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"Data-Category/exception"
+	"Data-Category/model/web"
+	"Data-Category/pb"
+	"Data-Category/service"
+	"context"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// CategoryGrpcServer serves pb.CategoryService on top of the same
+// service.CategoryService as the REST API. Errors are raised as panics like in
+// the HTTP controllers and turned into status codes by
+// exception.GrpcUnaryErrorHandler.
+type CategoryGrpcServer struct {
+	pb.UnimplementedCategoryServiceServer
+	CategoryService service.CategoryService
+}
+
+func NewCategoryGrpcServer(categoryService service.CategoryService) *CategoryGrpcServer {
+	return &CategoryGrpcServer{
+		CategoryService: categoryService,
+	}
+}
+
+func (cs *CategoryGrpcServer) Create(ctx context.Context, request *pb.CreateCategoryRequest) (*pb.Category, error) {
+	categoryResponse := cs.CategoryService.Create(ctx, web.CategoryCreateRequest{
+		Name: request.Name,
+		Slug: request.Slug,
+	})
+	return toPbCategory(categoryResponse), nil
+}
+
+func (cs *CategoryGrpcServer) Get(ctx context.Context, request *pb.GetCategoryRequest) (*pb.Category, error) {
+	categoryResponse := cs.CategoryService.FindById(ctx, int(request.Id))
+	return toPbCategory(categoryResponse), nil
+}
+
+// List pages through the categories ordered by id. The page token is the id
+// of the last category of the previous page.
+func (cs *CategoryGrpcServer) List(ctx context.Context, request *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	pageSize := int(request.PageSize)
+	if pageSize < 0 || pageSize > maxPageSize {
+		panic(exception.NewBadRequestError("page_size must be between 0 and " + strconv.Itoa(maxPageSize)))
+	} else if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	var after int
+	if request.PageToken != "" {
+		a, err := strconv.Atoi(request.PageToken)
+		if err != nil {
+			panic(exception.NewBadRequestError("page_token is invalid"))
+		}
+		after = a
+	}
+
+	// One more than a page tells whether there is a next page.
+	categoryResponses := cs.CategoryService.FindAll(ctx, web.CategoryFindAllRequest{AfterId: after, Limit: pageSize + 1})
+
+	listResponse := &pb.ListCategoriesResponse{}
+	for _, categoryResponse := range categoryResponses {
+		if len(listResponse.Categories) == pageSize {
+			listResponse.NextPageToken = strconv.FormatInt(listResponse.Categories[pageSize-1].Id, 10)
+			break
+		}
+		listResponse.Categories = append(listResponse.Categories, toPbCategory(categoryResponse))
+	}
+	return listResponse, nil
+}
+
+func (cs *CategoryGrpcServer) Update(ctx context.Context, request *pb.UpdateCategoryRequest) (*pb.Category, error) {
+	categoryResponse := cs.CategoryService.UpdateById(ctx, web.CategoryUpdateRequest{
+		Id:   int(request.Id),
+		Name: request.Name,
+		Slug: request.Slug,
+	})
+	return toPbCategory(categoryResponse), nil
+}
+
+func (cs *CategoryGrpcServer) Delete(ctx context.Context, request *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	cs.CategoryService.DeleteById(ctx, int(request.Id))
+	return &pb.DeleteCategoryResponse{}, nil
+}
+
+// DeleteAll takes the same two steps as the REST endpoint: a dry run returns
+// the count and a confirmation token, which the delete has to present.
+func (cs *CategoryGrpcServer) DeleteAll(ctx context.Context, request *pb.DeleteAllCategoriesRequest) (*pb.DeleteAllCategoriesResponse, error) {
+	deleteAllResponse := cs.CategoryService.DeleteAll(ctx, web.CategoryDeleteAllRequest{
+		TenantId:          int(request.TenantId),
+		SubtreeId:         int(request.SubtreeId),
+		Name:              request.Name,
+		DryRun:            request.DryRun,
+		ConfirmationToken: request.ConfirmationToken,
+	})
+
+	response := &pb.DeleteAllCategoriesResponse{
+		DryRun:            deleteAllResponse.DryRun,
+		Count:             int64(deleteAllResponse.Count),
+		ConfirmationToken: deleteAllResponse.ConfirmationToken,
+	}
+	if deleteAllResponse.ExpiresAt != nil {
+		response.ExpiresAt = timestamppb.New(*deleteAllResponse.ExpiresAt)
+	}
+	return response, nil
+}
+
+// Watch follows the same replay-then-live protocol as the Events endpoint,
+// with last_sequence in place of Last-Event-ID.
+func (cs *CategoryGrpcServer) Watch(request *pb.WatchCategoriesRequest, watchStream pb.CategoryService_WatchServer) error {
+	ctx := watchStream.Context()
+	changes, unsubscribe := cs.CategoryService.Subscribe(ctx)
+	defer unsubscribe()
+
+	resume := request.LastSequence != nil
+	stream := newCategoryChangeStream(ctx, cs.CategoryService, request.GetLastSequence(), resume, func(change web.CategoryChangeResponse) error {
+		return watchStream.Send(toPbCategoryChange(change))
+	})
+	if resume {
+		if err := stream.Replay(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				// The subscriber fell behind, the client resumes with last_sequence.
+				return nil
+			}
+			if err := stream.Deliver(ctx, change); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toPbCategory(categoryResponse web.CategoryResponse) *pb.Category {
+	return &pb.Category{
+		Id:        int64(categoryResponse.Id),
+		Name:      categoryResponse.Name,
+		Slug:      categoryResponse.Slug,
+		CreatedAt: timestamppb.New(categoryResponse.CreatedAt),
+		UpdatedAt: timestamppb.New(categoryResponse.UpdatedAt),
+		CreatedBy: categoryResponse.CreatedBy,
+		UpdatedBy: categoryResponse.UpdatedBy,
+	}
+}
+
+func toPbCategoryChange(change web.CategoryChangeResponse) *pb.CategoryChange {
+	categoryChange := &pb.CategoryChange{
+		Sequence:   change.Sequence,
+		Operation:  change.Operation,
+		CategoryId: int64(change.CategoryId),
+		ChangedAt:  timestamppb.New(change.ChangedAt),
+		ChangedBy:  change.ChangedBy,
+	}
+	if change.Category != nil {
+		categoryChange.Category = toPbCategory(*change.Category)
+	}
+	return categoryChange
+}
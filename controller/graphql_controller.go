@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"Data-Category/exception"
+	"Data-Category/graph"
+	"Data-Category/helper"
+	"Data-Category/service"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type GraphqlController interface {
+	Query(w http.ResponseWriter, r *http.Request)
+}
+
+type GraphqlControllerImpl struct {
+	Schema          *graphql.Schema
+	CategoryService service.CategoryService
+	ItemService     service.ItemService
+}
+
+func NewGraphqlController(schema *graphql.Schema, categoryService service.CategoryService, itemService service.ItemService) *GraphqlControllerImpl {
+	return &GraphqlControllerImpl{
+		Schema:          schema,
+		CategoryService: categoryService,
+		ItemService:     itemService,
+	}
+}
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query executes a GraphQL request. Every request gets its own
+// CategoryLoader and RelationLoader, so loaded categories are never shared
+// between callers.
+func (gc *GraphqlControllerImpl) Query(w http.ResponseWriter, r *http.Request) {
+	request := graphqlRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		panic(exception.NewBadRequestError("request body must be a GraphQL request"))
+	}
+
+	ctx := graph.WithCategoryLoader(r.Context(), graph.NewCategoryLoader(gc.CategoryService))
+	ctx = graph.WithRelationLoader(ctx, graph.NewRelationLoader(gc.CategoryService, gc.ItemService))
+	response := gc.Schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+
+	helper.WriteToResponseBody(w, response)
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ItemController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	FindAll(w http.ResponseWriter, r *http.Request)
+	FindById(w http.ResponseWriter, r *http.Request)
+	UpdateById(w http.ResponseWriter, r *http.Request)
+	DeleteById(w http.ResponseWriter, r *http.Request)
+	FindByCategory(w http.ResponseWriter, r *http.Request)
+}
+
+type ItemControllerImpl struct {
+	ItemService service.ItemService
+}
+
+func NewItemController(itemService service.ItemService) *ItemControllerImpl {
+	return &ItemControllerImpl{
+		ItemService: itemService,
+	}
+}
+
+func (ic *ItemControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
+	itemCreateRequest := web.ItemCreateRequest{}
+	helper.ReadFromRequestBody(r, &itemCreateRequest)
+
+	itemResponse := ic.ItemService.Create(r.Context(), itemCreateRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   itemResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ic *ItemControllerImpl) FindAll(w http.ResponseWriter, r *http.Request) {
+	itemResponses := ic.ItemService.FindAll(r.Context())
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   itemResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ic *ItemControllerImpl) FindById(w http.ResponseWriter, r *http.Request) {
+	itemId := chi.URLParam(r, "itemId")
+	id, err := strconv.Atoi(itemId)
+	helper.PanicIfError(err)
+
+	itemResponse := ic.ItemService.FindById(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   itemResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ic *ItemControllerImpl) UpdateById(w http.ResponseWriter, r *http.Request) {
+	itemUpdateRequest := web.ItemUpdateRequest{}
+	helper.ReadFromRequestBody(r, &itemUpdateRequest)
+
+	itemId := chi.URLParam(r, "itemId")
+	id, err := strconv.Atoi(itemId)
+	helper.PanicIfError(err)
+
+	itemUpdateRequest.Id = id
+
+	itemResponse := ic.ItemService.UpdateById(r.Context(), itemUpdateRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   itemResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ic *ItemControllerImpl) DeleteById(w http.ResponseWriter, r *http.Request) {
+	itemId := chi.URLParam(r, "itemId")
+	id, err := strconv.Atoi(itemId)
+	helper.PanicIfError(err)
+
+	ic.ItemService.DeleteById(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ic *ItemControllerImpl) FindByCategory(w http.ResponseWriter, r *http.Request) {
+	categoryId := chi.URLParam(r, "categoryId")
+	id, err := strconv.Atoi(categoryId)
+	helper.PanicIfError(err)
+
+	itemFindByCategoryRequest := web.ItemFindByCategoryRequest{
+		CategoryId: id,
+	}
+	if includeDescendants := r.URL.Query().Get("include_descendants"); includeDescendants != "" {
+		b, err := strconv.ParseBool(includeDescendants)
+		if err != nil {
+			panic(exception.NewBadRequestError("include_descendants must be a boolean"))
+		}
+		itemFindByCategoryRequest.IncludeDescendants = b
+	}
+
+	itemResponses := ic.ItemService.FindByCategory(r.Context(), itemFindByCategoryRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   itemResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
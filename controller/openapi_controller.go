@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"Data-Category/api"
+	"net/http"
+)
+
+type OpenApiController interface {
+	Spec(w http.ResponseWriter, r *http.Request)
+}
+
+type OpenApiControllerImpl struct {
+}
+
+func NewOpenApiController() *OpenApiControllerImpl {
+	return &OpenApiControllerImpl{}
+}
+
+func (oc *OpenApiControllerImpl) Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(api.Spec)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TenantController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	FindAll(w http.ResponseWriter, r *http.Request)
+	DisableById(w http.ResponseWriter, r *http.Request)
+}
+
+type TenantControllerImpl struct {
+	TenantService service.TenantService
+}
+
+func NewTenantController(tenantService service.TenantService) *TenantControllerImpl {
+	return &TenantControllerImpl{
+		TenantService: tenantService,
+	}
+}
+
+func (tc *TenantControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
+	tenantCreateRequest := web.TenantCreateRequest{}
+	helper.ReadFromRequestBody(r, &tenantCreateRequest)
+
+	tenantResponse := tc.TenantService.Create(r.Context(), tenantCreateRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   tenantResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (tc *TenantControllerImpl) FindAll(w http.ResponseWriter, r *http.Request) {
+	tenantResponses := tc.TenantService.FindAll(r.Context())
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   tenantResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (tc *TenantControllerImpl) DisableById(w http.ResponseWriter, r *http.Request) {
+	tenantId := chi.URLParam(r, "tenantId")
+	id, err := strconv.Atoi(tenantId)
+	helper.PanicIfError(err)
+
+	tenantResponse := tc.TenantService.DisableById(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   tenantResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WebhookController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	FindAll(w http.ResponseWriter, r *http.Request)
+	FindById(w http.ResponseWriter, r *http.Request)
+	DeleteById(w http.ResponseWriter, r *http.Request)
+	FindDeliveries(w http.ResponseWriter, r *http.Request)
+}
+
+type WebhookControllerImpl struct {
+	WebhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) *WebhookControllerImpl {
+	return &WebhookControllerImpl{
+		WebhookService: webhookService,
+	}
+}
+
+func (wc *WebhookControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
+	webhookCreateRequest := web.WebhookCreateRequest{}
+	helper.ReadFromRequestBody(r, &webhookCreateRequest)
+
+	webhookResponse := wc.WebhookService.Create(r.Context(), webhookCreateRequest)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   webhookResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (wc *WebhookControllerImpl) FindAll(w http.ResponseWriter, r *http.Request) {
+	webhookResponses := wc.WebhookService.FindAll(r.Context())
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   webhookResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (wc *WebhookControllerImpl) FindById(w http.ResponseWriter, r *http.Request) {
+	webhookId := chi.URLParam(r, "webhookId")
+	id, err := strconv.Atoi(webhookId)
+	helper.PanicIfError(err)
+
+	webhookResponse := wc.WebhookService.FindById(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   webhookResponse,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (wc *WebhookControllerImpl) DeleteById(w http.ResponseWriter, r *http.Request) {
+	webhookId := chi.URLParam(r, "webhookId")
+	id, err := strconv.Atoi(webhookId)
+	helper.PanicIfError(err)
+
+	wc.WebhookService.DeleteById(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
+
+func (wc *WebhookControllerImpl) FindDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookId := chi.URLParam(r, "webhookId")
+	id, err := strconv.Atoi(webhookId)
+	helper.PanicIfError(err)
+
+	deliveryResponses := wc.WebhookService.FindDeliveries(r.Context(), id)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   deliveryResponses,
+	}
+
+	helper.WriteToResponseBody(w, webResponse)
+}
@@ -0,0 +1,75 @@
+package event
+
+import (
+	"Data-Category/model/domain"
+	"time"
+)
+
+// Event is a domain event about the categories of a tenant. Events are only
+// published once the transaction that caused them has committed.
+type Event interface {
+	EventName() string
+}
+
+type CategoryCreated struct {
+	TenantId   int
+	Category   domain.Category
+	OccurredAt time.Time
+	Actor      string
+}
+
+// CategoryRenamed is published when the name or slug of a category changes.
+type CategoryRenamed struct {
+	TenantId   int
+	Category   domain.Category
+	OldName    string
+	OldSlug    string
+	OccurredAt time.Time
+	Actor      string
+}
+
+type CategoryDeleted struct {
+	TenantId   int
+	Category   domain.Category
+	OccurredAt time.Time
+	Actor      string
+}
+
+// CategoryMerged is published when Source was merged into Target and
+// soft-deleted. Source is its last state.
+type CategoryMerged struct {
+	TenantId   int
+	Source     domain.Category
+	Target     domain.Category
+	OccurredAt time.Time
+	Actor      string
+}
+
+// CategoriesPurged is published instead of CategoryDeleted when all
+// categories of a tenant are deleted at once.
+type CategoriesPurged struct {
+	TenantId   int
+	Categories []domain.Category
+	OccurredAt time.Time
+	Actor      string
+}
+
+func (e CategoryCreated) EventName() string {
+	return "category.created"
+}
+
+func (e CategoryRenamed) EventName() string {
+	return "category.renamed"
+}
+
+func (e CategoryDeleted) EventName() string {
+	return "category.deleted"
+}
+
+func (e CategoryMerged) EventName() string {
+	return "category.merged"
+}
+
+func (e CategoriesPurged) EventName() string {
+	return "categories.purged"
+}
@@ -0,0 +1,99 @@
+package event
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Subscriber reacts to published events. It is called for every event and
+// picks the ones it is interested in by type.
+type Subscriber interface {
+	Handle(ctx context.Context, e Event)
+}
+
+// SubscriberFunc adapts a function to a Subscriber.
+type SubscriberFunc func(ctx context.Context, e Event)
+
+func (f SubscriberFunc) Handle(ctx context.Context, e Event) {
+	f(ctx, e)
+}
+
+type EventBus interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// SyncEventBus calls the subscribers in order on the publishing goroutine. A
+// panicking subscriber is logged and does not keep the others from running.
+type SyncEventBus struct {
+	Subscribers []Subscriber
+}
+
+func NewSyncEventBus(subscribers []Subscriber) *SyncEventBus {
+	return &SyncEventBus{
+		Subscribers: subscribers,
+	}
+}
+
+func (eb *SyncEventBus) Publish(ctx context.Context, e Event) {
+	for _, subscriber := range eb.Subscribers {
+		dispatch(ctx, subscriber, e)
+	}
+}
+
+// AsyncEventBus queues events in a buffer of the given size and hands them
+// to the subscribers on a single goroutine, in publishing order. Publish
+// blocks while the buffer is full. Subscribers get a context without the
+// deadline of the publishing request.
+type AsyncEventBus struct {
+	SyncEventBus
+
+	events chan Event
+	once   sync.Once
+	done   chan struct{}
+}
+
+func NewAsyncEventBus(subscribers []Subscriber, size int) *AsyncEventBus {
+	eb := &AsyncEventBus{
+		SyncEventBus: SyncEventBus{Subscribers: subscribers},
+		events:       make(chan Event, size),
+		done:         make(chan struct{}),
+	}
+	go eb.run()
+	return eb
+}
+
+func (eb *AsyncEventBus) Publish(ctx context.Context, e Event) {
+	eb.events <- e
+}
+
+// Close stops accepting events and waits until the queued ones are handled.
+func (eb *AsyncEventBus) Close() {
+	eb.once.Do(func() {
+		close(eb.events)
+	})
+	<-eb.done
+}
+
+func (eb *AsyncEventBus) run() {
+	defer close(eb.done)
+	for e := range eb.events {
+		eb.SyncEventBus.Publish(context.Background(), e)
+	}
+}
+
+func dispatch(ctx context.Context, subscriber Subscriber, e Event) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			log.Printf("event bus: %s: %v", e.EventName(), rvr)
+		}
+	}()
+	subscriber.Handle(ctx, e)
+}
+
+type EventBusConfig struct {
+	Async      bool
+	BufferSize int
+	// Log adds the LogSubscriber to the subscribers.
+	Log bool
+}
@@ -0,0 +1,29 @@
+package event
+
+import (
+	"context"
+	"log"
+)
+
+// LogSubscriber writes a line to the standard logger for every event.
+type LogSubscriber struct {
+}
+
+func NewLogSubscriber() *LogSubscriber {
+	return &LogSubscriber{}
+}
+
+func (ls *LogSubscriber) Handle(ctx context.Context, e Event) {
+	switch e := e.(type) {
+	case CategoryCreated:
+		log.Printf("%s: tenant %d category %d %q by %s", e.EventName(), e.TenantId, e.Category.Id, e.Category.Name, e.Actor)
+	case CategoryRenamed:
+		log.Printf("%s: tenant %d category %d %q to %q by %s", e.EventName(), e.TenantId, e.Category.Id, e.OldName, e.Category.Name, e.Actor)
+	case CategoryMerged:
+		log.Printf("%s: tenant %d category %d %q into %d %q by %s", e.EventName(), e.TenantId, e.Source.Id, e.Source.Name, e.Target.Id, e.Target.Name, e.Actor)
+	case CategoryDeleted:
+		log.Printf("%s: tenant %d category %d %q by %s", e.EventName(), e.TenantId, e.Category.Id, e.Category.Name, e.Actor)
+	case CategoriesPurged:
+		log.Printf("%s: tenant %d %d categories by %s", e.EventName(), e.TenantId, len(e.Categories), e.Actor)
+	}
+}
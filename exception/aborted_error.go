@@ -0,0 +1,14 @@
+package exception
+
+// AbortedError reports a request that lost a race with a concurrent change
+// and may succeed if the client starts over. REST answers it like
+// ConflictError; gRPC uses Aborted.
+type AbortedError struct {
+	Error string
+}
+
+func NewAbortedError(err string) AbortedError {
+	return AbortedError{
+		Error: err,
+	}
+}
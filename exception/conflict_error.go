@@ -0,0 +1,11 @@
+package exception
+
+type ConflictError struct {
+	Error string
+}
+
+func NewConflictError(err string) ConflictError {
+	return ConflictError{
+		Error: err,
+	}
+}
@@ -12,32 +12,9 @@ func ErrorHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if rvr := recover(); rvr != nil && rvr != http.ErrAbortHandler {
-				var webResponse web.WebResponse
-				w.Header().Set("Content-Type", "application/json")
-
-				if exception, ok := rvr.(NotFoundError); ok {
-					w.WriteHeader(http.StatusNotFound)
-					webResponse = web.WebResponse{
-						Code:   http.StatusNotFound,
-						Status: "Not Found",
-						Data:   exception.Error,
-					}
-				} else if exception, ok := rvr.(validator.ValidationErrors); ok {
-					w.WriteHeader(http.StatusBadRequest)
-					webResponse = web.WebResponse{
-						Code:   http.StatusBadRequest,
-						Status: "Bad Request",
-						Data:   exception.Error(),
-					}
-				} else {
-					w.WriteHeader(http.StatusInternalServerError)
-					webResponse = web.WebResponse{
-						Code:   http.StatusInternalServerError,
-						Status: "Internal Server Error",
-						Data:   rvr,
-					}
-				}
-
+				webResponse := ToWebResponse(rvr)
+				helper.SetContentType(w)
+				w.WriteHeader(webResponse.Code)
 				helper.WriteToResponseBody(w, webResponse)
 			}
 		}()
@@ -45,3 +22,63 @@ func ErrorHandler(h http.Handler) http.Handler {
 		h.ServeHTTP(w, r)
 	})
 }
+
+// ToWebResponse maps a recovered panic to the response describing it, so the
+// other APIs can report errors with the same codes as REST.
+func ToWebResponse(rvr interface{}) web.WebResponse {
+	if exception, ok := rvr.(NotFoundError); ok {
+		return web.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "Not Found",
+			Data:   exception.Error,
+		}
+	} else if exception, ok := rvr.(validator.ValidationErrors); ok {
+		return web.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   exception.Error(),
+		}
+	} else if exception, ok := rvr.(BadRequestError); ok {
+		return web.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   exception.Error,
+		}
+	} else if exception, ok := rvr.(UnprocessableEntityError); ok {
+		return web.WebResponse{
+			Code:   http.StatusUnprocessableEntity,
+			Status: "Unprocessable Entity",
+			Data:   exception.Error,
+		}
+	} else if exception, ok := rvr.(ForbiddenError); ok {
+		return web.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "Forbidden",
+			Data:   exception.Error,
+		}
+	} else if exception, ok := rvr.(ConflictError); ok {
+		return web.WebResponse{
+			Code:   http.StatusConflict,
+			Status: "Conflict",
+			Data:   exception.Error,
+		}
+	} else if exception, ok := rvr.(FailedPreconditionError); ok {
+		return web.WebResponse{
+			Code:   http.StatusConflict,
+			Status: "Conflict",
+			Data:   exception.Error,
+		}
+	} else if exception, ok := rvr.(AbortedError); ok {
+		return web.WebResponse{
+			Code:   http.StatusConflict,
+			Status: "Conflict",
+			Data:   exception.Error,
+		}
+	} else {
+		return web.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: "Internal Server Error",
+			Data:   rvr,
+		}
+	}
+}
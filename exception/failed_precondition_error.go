@@ -0,0 +1,15 @@
+package exception
+
+// FailedPreconditionError reports a request that conflicts with the current
+// state of a resource, such as deleting a category that still has children.
+// REST answers it like ConflictError; gRPC uses FailedPrecondition rather
+// than AlreadyExists.
+type FailedPreconditionError struct {
+	Error string
+}
+
+func NewFailedPreconditionError(err string) FailedPreconditionError {
+	return FailedPreconditionError{
+		Error: err,
+	}
+}
@@ -0,0 +1,11 @@
+package exception
+
+type ForbiddenError struct {
+	Error string
+}
+
+func NewForbiddenError(err string) ForbiddenError {
+	return ForbiddenError{
+		Error: err,
+	}
+}
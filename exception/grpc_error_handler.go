@@ -0,0 +1,56 @@
+package exception
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcUnaryErrorHandler is the gRPC counterpart of ErrorHandler: it recovers
+// the panics raised by handlers and services and returns them as a status.
+func GrpcUnaryErrorHandler(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			err = grpcStatus(info.FullMethod, rvr).Err()
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func GrpcStreamErrorHandler(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			err = grpcStatus(info.FullMethod, rvr).Err()
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// grpcStatus maps rvr to its status. Unexpected panics are logged and answered
+// with a generic message, as their text may contain SQL or other internals.
+func grpcStatus(method string, rvr interface{}) *status.Status {
+	if exception, ok := rvr.(NotFoundError); ok {
+		return status.New(codes.NotFound, exception.Error)
+	} else if exception, ok := rvr.(validator.ValidationErrors); ok {
+		return status.New(codes.InvalidArgument, exception.Error())
+	} else if exception, ok := rvr.(BadRequestError); ok {
+		return status.New(codes.InvalidArgument, exception.Error)
+	} else if exception, ok := rvr.(UnprocessableEntityError); ok {
+		return status.New(codes.FailedPrecondition, exception.Error)
+	} else if exception, ok := rvr.(ForbiddenError); ok {
+		return status.New(codes.PermissionDenied, exception.Error)
+	} else if exception, ok := rvr.(ConflictError); ok {
+		return status.New(codes.AlreadyExists, exception.Error)
+	} else if exception, ok := rvr.(FailedPreconditionError); ok {
+		return status.New(codes.FailedPrecondition, exception.Error)
+	} else if exception, ok := rvr.(AbortedError); ok {
+		return status.New(codes.Aborted, exception.Error)
+	} else {
+		log.Printf("grpc: %s: %v", method, rvr)
+		return status.New(codes.Internal, "internal error")
+	}
+}
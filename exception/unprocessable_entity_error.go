@@ -0,0 +1,11 @@
+package exception
+
+type UnprocessableEntityError struct {
+	Error string
+}
+
+func NewUnprocessableEntityError(err string) UnprocessableEntityError {
+	return UnprocessableEntityError{
+		Error: err,
+	}
+}
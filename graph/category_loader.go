@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+	"sync"
+)
+
+type categoryLoaderContextKey struct{}
+
+// CategoryLoader loads categories by id for one request. Resolvers of a list
+// Enqueue the ids their items refer to; the first Load then fetches all of
+// them with a single FindByIds, so nested lookups cost one query per list
+// instead of one per item.
+type CategoryLoader struct {
+	CategoryService service.CategoryService
+
+	mutex   sync.Mutex
+	pending map[int]struct{}
+	loaded  map[int]*web.CategoryResponse
+}
+
+func NewCategoryLoader(categoryService service.CategoryService) *CategoryLoader {
+	return &CategoryLoader{
+		CategoryService: categoryService,
+		pending:         map[int]struct{}{},
+		loaded:          map[int]*web.CategoryResponse{},
+	}
+}
+
+func WithCategoryLoader(ctx context.Context, loader *CategoryLoader) context.Context {
+	return context.WithValue(ctx, categoryLoaderContextKey{}, loader)
+}
+
+func categoryLoaderFromContext(ctx context.Context) *CategoryLoader {
+	return ctx.Value(categoryLoaderContextKey{}).(*CategoryLoader)
+}
+
+// Enqueue marks ids to be fetched with the next Load.
+func (cl *CategoryLoader) Enqueue(categoryIds ...int) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	for _, categoryId := range categoryIds {
+		if _, ok := cl.loaded[categoryId]; !ok {
+			cl.pending[categoryId] = struct{}{}
+		}
+	}
+}
+
+// Load returns the category with the given id and whether it exists. The
+// lock is held while fetching, so concurrent resolvers wait for the batch
+// that already contains their id instead of querying again.
+func (cl *CategoryLoader) Load(ctx context.Context, categoryId int) (web.CategoryResponse, bool) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	if _, ok := cl.loaded[categoryId]; !ok {
+		cl.pending[categoryId] = struct{}{}
+		categoryIds := make([]int, 0, len(cl.pending))
+		for id := range cl.pending {
+			categoryIds = append(categoryIds, id)
+		}
+		cl.pending = map[int]struct{}{}
+
+		categoryResponses := cl.CategoryService.FindByIds(ctx, categoryIds)
+		for _, id := range categoryIds {
+			cl.loaded[id] = nil
+		}
+		for i := range categoryResponses {
+			cl.loaded[categoryResponses[i].Id] = &categoryResponses[i]
+		}
+	}
+
+	if categoryResponse := cl.loaded[categoryId]; categoryResponse != nil {
+		return *categoryResponse, true
+	}
+	return web.CategoryResponse{}, false
+}
@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type categoryResolver struct {
+	category web.CategoryResponse
+}
+
+func (cr *categoryResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(cr.category.Id))
+}
+
+func (cr *categoryResolver) Name() string {
+	return cr.category.Name
+}
+
+func (cr *categoryResolver) Slug() string {
+	return cr.category.Slug
+}
+
+func (cr *categoryResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: cr.category.CreatedAt}
+}
+
+func (cr *categoryResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: cr.category.UpdatedAt}
+}
+
+func (cr *categoryResolver) CreatedBy() string {
+	return cr.category.CreatedBy
+}
+
+func (cr *categoryResolver) UpdatedBy() string {
+	return cr.category.UpdatedBy
+}
+
+// Parent resolves through the request's CategoryLoader, the relations below
+// through its RelationLoader.
+func (cr *categoryResolver) Parent(ctx context.Context) (category *categoryResolver, err error) {
+	defer recoverError(&err)
+
+	if cr.category.ParentId == nil {
+		return nil, nil
+	}
+	categoryResponse, ok := categoryLoaderFromContext(ctx).Load(ctx, *cr.category.ParentId)
+	if !ok {
+		return nil, nil
+	}
+	return &categoryResolver{categoryResponse}, nil
+}
+
+func (cr *categoryResolver) Children(ctx context.Context) (categories []*categoryResolver, err error) {
+	defer recoverError(&err)
+
+	return newCategoryResolvers(ctx, relationLoaderFromContext(ctx).Children(ctx, cr.category.Id)), nil
+}
+
+func (cr *categoryResolver) Aliases(ctx context.Context) (aliases []*categoryAliasResolver, err error) {
+	defer recoverError(&err)
+
+	for _, alias := range relationLoaderFromContext(ctx).Aliases(ctx, cr.category.Id) {
+		aliases = append(aliases, &categoryAliasResolver{alias})
+	}
+	return aliases, nil
+}
+
+func (cr *categoryResolver) Items(ctx context.Context) (items []*itemResolver, err error) {
+	defer recoverError(&err)
+
+	for _, item := range relationLoaderFromContext(ctx).Items(ctx, cr.category.Id) {
+		items = append(items, &itemResolver{item})
+	}
+	return items, nil
+}
+
+// newCategoryResolvers resolves a list of categories and enqueues their
+// parents and relations, so each is loaded for the whole list at once.
+func newCategoryResolvers(ctx context.Context, categoryResponses []web.CategoryResponse) []*categoryResolver {
+	categoryLoader := categoryLoaderFromContext(ctx)
+	relationLoader := relationLoaderFromContext(ctx)
+
+	categories := []*categoryResolver{}
+	for _, categoryResponse := range categoryResponses {
+		if categoryResponse.ParentId != nil {
+			categoryLoader.Enqueue(*categoryResponse.ParentId)
+		}
+		relationLoader.Enqueue(categoryResponse.Id)
+		categories = append(categories, &categoryResolver{categoryResponse})
+	}
+	return categories
+}
+
+type categoryAliasResolver struct {
+	alias web.CategoryAliasResponse
+}
+
+func (ar *categoryAliasResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(ar.alias.Id))
+}
+
+func (ar *categoryAliasResolver) Alias() string {
+	return ar.alias.Alias
+}
+
+func (ar *categoryAliasResolver) Slug() string {
+	return ar.alias.Slug
+}
+
+func (ar *categoryAliasResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: ar.alias.CreatedAt}
+}
+
+func (ar *categoryAliasResolver) CreatedBy() string {
+	return ar.alias.CreatedBy
+}
+
+type itemResolver struct {
+	item web.ItemResponse
+}
+
+func (ir *itemResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(ir.item.Id))
+}
+
+func (ir *itemResolver) Name() string {
+	return ir.item.Name
+}
+
+func (ir *itemResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: ir.item.CreatedAt}
+}
+
+func (ir *itemResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: ir.item.UpdatedAt}
+}
+
+func (ir *itemResolver) CreatedBy() string {
+	return ir.item.CreatedBy
+}
+
+func (ir *itemResolver) UpdatedBy() string {
+	return ir.item.UpdatedBy
+}
+
+type categoryConnectionResolver struct {
+	nodes       []*categoryResolver
+	endCursor   *string
+	hasNextPage bool
+}
+
+func (cr *categoryConnectionResolver) Nodes() []*categoryResolver {
+	return cr.nodes
+}
+
+func (cr *categoryConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{endCursor: cr.endCursor, hasNextPage: cr.hasNextPage}
+}
+
+type categoryChangeResolver struct {
+	change web.CategoryChangeResponse
+}
+
+func (cr *categoryChangeResolver) Sequence() string {
+	return strconv.FormatInt(cr.change.Sequence, 10)
+}
+
+func (cr *categoryChangeResolver) Operation() string {
+	return cr.change.Operation
+}
+
+func (cr *categoryChangeResolver) CategoryID() graphql.ID {
+	return graphql.ID(strconv.Itoa(cr.change.CategoryId))
+}
+
+// Category resolves the current state of the category through the request's
+// CategoryLoader.
+func (cr *categoryChangeResolver) Category(ctx context.Context) (category *categoryResolver, err error) {
+	defer recoverError(&err)
+
+	if cr.change.Operation == domain.CategoryDeleted {
+		return nil, nil
+	}
+	categoryResponse, ok := categoryLoaderFromContext(ctx).Load(ctx, cr.change.CategoryId)
+	if !ok {
+		return nil, nil
+	}
+	return &categoryResolver{categoryResponse}, nil
+}
+
+func (cr *categoryChangeResolver) ChangedAt() graphql.Time {
+	return graphql.Time{Time: cr.change.ChangedAt}
+}
+
+func (cr *categoryChangeResolver) ChangedBy() string {
+	return cr.change.ChangedBy
+}
+
+type categoryChangeConnectionResolver struct {
+	nodes       []*categoryChangeResolver
+	endCursor   *string
+	hasNextPage bool
+}
+
+func (cr *categoryChangeConnectionResolver) Nodes() []*categoryChangeResolver {
+	return cr.nodes
+}
+
+func (cr *categoryChangeConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{endCursor: cr.endCursor, hasNextPage: cr.hasNextPage}
+}
+
+type pageInfoResolver struct {
+	endCursor   *string
+	hasNextPage bool
+}
+
+func (pr *pageInfoResolver) EndCursor() *string {
+	return pr.endCursor
+}
+
+func (pr *pageInfoResolver) HasNextPage() bool {
+	return pr.hasNextPage
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"Data-Category/exception"
+	"Data-Category/model/web"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Error is a GraphQL error carrying the code and status the REST API would
+// answer with in its extensions.
+type Error struct {
+	web.WebResponse
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprint(e.Data)
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   e.Code,
+		"status": e.Status,
+	}
+}
+
+// recoverError turns a panic of the service layer into the error returned by
+// a resolver. Resolvers defer it with their named error result. Unexpected
+// panics are logged and reported with a generic message, as their text may
+// contain SQL or other internals.
+func recoverError(err *error) {
+	if rvr := recover(); rvr != nil {
+		webResponse := exception.ToWebResponse(rvr)
+		if webResponse.Code == http.StatusInternalServerError {
+			log.Printf("graphql: %v", rvr)
+			webResponse.Data = "internal error"
+		}
+		*err = &Error{webResponse}
+	}
+}
@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+	"sync"
+)
+
+type relationLoaderContextKey struct{}
+
+// RelationLoader loads the children, aliases and items of categories for one
+// request. It batches like CategoryLoader: the resolvers of a list Enqueue
+// the ids of its categories, and the first load of a relation fetches it for
+// all of them with a single query.
+type RelationLoader struct {
+	CategoryService service.CategoryService
+	ItemService     service.ItemService
+
+	mutex    sync.Mutex
+	enqueued map[int]struct{}
+	children map[int][]web.CategoryResponse
+	aliases  map[int][]web.CategoryAliasResponse
+	items    map[int][]web.ItemResponse
+}
+
+func NewRelationLoader(categoryService service.CategoryService, itemService service.ItemService) *RelationLoader {
+	return &RelationLoader{
+		CategoryService: categoryService,
+		ItemService:     itemService,
+		enqueued:        map[int]struct{}{},
+		children:        map[int][]web.CategoryResponse{},
+		aliases:         map[int][]web.CategoryAliasResponse{},
+		items:           map[int][]web.ItemResponse{},
+	}
+}
+
+func WithRelationLoader(ctx context.Context, loader *RelationLoader) context.Context {
+	return context.WithValue(ctx, relationLoaderContextKey{}, loader)
+}
+
+func relationLoaderFromContext(ctx context.Context) *RelationLoader {
+	return ctx.Value(relationLoaderContextKey{}).(*RelationLoader)
+}
+
+// Enqueue marks the categories whose relations are fetched together.
+func (rl *RelationLoader) Enqueue(categoryIds ...int) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	for _, categoryId := range categoryIds {
+		rl.enqueued[categoryId] = struct{}{}
+	}
+}
+
+func (rl *RelationLoader) Children(ctx context.Context, categoryId int) []web.CategoryResponse {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if _, ok := rl.children[categoryId]; !ok {
+		categoryIds := rl.batch(categoryId, func(id int) bool {
+			_, ok := rl.children[id]
+			return ok
+		})
+		for _, id := range categoryIds {
+			rl.children[id] = []web.CategoryResponse{}
+		}
+		for _, child := range rl.CategoryService.FindChildrenByIds(ctx, categoryIds) {
+			rl.children[*child.ParentId] = append(rl.children[*child.ParentId], child)
+		}
+	}
+	return rl.children[categoryId]
+}
+
+func (rl *RelationLoader) Aliases(ctx context.Context, categoryId int) []web.CategoryAliasResponse {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if _, ok := rl.aliases[categoryId]; !ok {
+		categoryIds := rl.batch(categoryId, func(id int) bool {
+			_, ok := rl.aliases[id]
+			return ok
+		})
+		for _, id := range categoryIds {
+			rl.aliases[id] = []web.CategoryAliasResponse{}
+		}
+		for _, alias := range rl.CategoryService.FindAliasesByIds(ctx, categoryIds) {
+			rl.aliases[alias.CategoryId] = append(rl.aliases[alias.CategoryId], alias)
+		}
+	}
+	return rl.aliases[categoryId]
+}
+
+func (rl *RelationLoader) Items(ctx context.Context, categoryId int) []web.ItemResponse {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if _, ok := rl.items[categoryId]; !ok {
+		categoryIds := rl.batch(categoryId, func(id int) bool {
+			_, ok := rl.items[id]
+			return ok
+		})
+		items := map[int][]web.ItemResponse{}
+		for _, item := range rl.ItemService.FindByCategoryIds(ctx, categoryIds) {
+			// Items are assigned to other categories as well.
+			for _, id := range item.CategoryIds {
+				items[id] = append(items[id], item)
+			}
+		}
+		for _, id := range categoryIds {
+			rl.items[id] = append([]web.ItemResponse{}, items[id]...)
+		}
+	}
+	return rl.items[categoryId]
+}
+
+// batch returns categoryId with the enqueued ids whose relation is not
+// loaded yet.
+func (rl *RelationLoader) batch(categoryId int, loaded func(categoryId int) bool) []int {
+	rl.enqueued[categoryId] = struct{}{}
+	var categoryIds []int
+	for id := range rl.enqueued {
+		if !loaded(id) {
+			categoryIds = append(categoryIds, id)
+		}
+	}
+	return categoryIds
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"Data-Category/exception"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+const maxPageSize = 1000
+
+// Resolver is the root resolver of the schema. Like the controllers it is a
+// thin layer over service.CategoryService; the panics raised there are
+// turned into errors by recoverError.
+type Resolver struct {
+	CategoryService service.CategoryService
+}
+
+func NewResolver(categoryService service.CategoryService) *Resolver {
+	return &Resolver{
+		CategoryService: categoryService,
+	}
+}
+
+type createCategoryInput struct {
+	Name string
+	Slug *string
+}
+
+type updateCategoryInput struct {
+	Name string
+	Slug *string
+}
+
+func (r *Resolver) Category(ctx context.Context, args struct{ ID graphql.ID }) (category *categoryResolver, err error) {
+	defer recoverError(&err)
+
+	categoryResponse := r.CategoryService.FindById(ctx, parseId(args.ID))
+	return &categoryResolver{categoryResponse}, nil
+}
+
+func (r *Resolver) CategoryBySlug(ctx context.Context, args struct{ Slug string }) (category *categoryResolver, err error) {
+	defer recoverError(&err)
+
+	categorySlugResponse := r.CategoryService.FindBySlug(ctx, args.Slug)
+	return &categoryResolver{categorySlugResponse.Category}, nil
+}
+
+func (r *Resolver) Categories(ctx context.Context, args struct {
+	UpdatedSince *graphql.Time
+	First        int32
+	After        *string
+}) (connection *categoryConnectionResolver, err error) {
+	defer recoverError(&err)
+
+	if args.First < 1 || args.First > maxPageSize {
+		panic(exception.NewBadRequestError("first must be between 1 and " + strconv.Itoa(maxPageSize)))
+	}
+	var after int
+	if args.After != nil {
+		a, err := strconv.Atoi(*args.After)
+		if err != nil {
+			panic(exception.NewBadRequestError("after is invalid"))
+		}
+		after = a
+	}
+	// One more than first tells whether there is a next page.
+	categoryFindAllRequest := web.CategoryFindAllRequest{AfterId: after, Limit: int(args.First) + 1}
+	if args.UpdatedSince != nil {
+		categoryFindAllRequest.UpdatedSince = args.UpdatedSince.Time
+	}
+
+	categoryResponses := r.CategoryService.FindAll(ctx, categoryFindAllRequest)
+
+	connection = &categoryConnectionResolver{}
+	if len(categoryResponses) > int(args.First) {
+		categoryResponses = categoryResponses[:args.First]
+		connection.hasNextPage = true
+	}
+	connection.nodes = newCategoryResolvers(ctx, categoryResponses)
+	if len(categoryResponses) > 0 {
+		endCursor := strconv.Itoa(categoryResponses[len(categoryResponses)-1].Id)
+		connection.endCursor = &endCursor
+	}
+	return connection, nil
+}
+
+func (r *Resolver) CategoryChanges(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (connection *categoryChangeConnectionResolver, err error) {
+	defer recoverError(&err)
+
+	categoryChangesRequest := web.CategoryChangesRequest{Limit: int(args.First)}
+	if args.After != nil {
+		a, err := strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			panic(exception.NewBadRequestError("after is invalid"))
+		}
+		categoryChangesRequest.Cursor = a
+	}
+
+	categoryChangesResponse := r.CategoryService.FindChanges(ctx, categoryChangesRequest)
+
+	loader := categoryLoaderFromContext(ctx)
+	relationLoader := relationLoaderFromContext(ctx)
+	endCursor := strconv.FormatInt(categoryChangesResponse.NextCursor, 10)
+	connection = &categoryChangeConnectionResolver{
+		endCursor:   &endCursor,
+		hasNextPage: categoryChangesResponse.HasMore,
+	}
+	for _, change := range categoryChangesResponse.Changes {
+		if change.Operation != domain.CategoryDeleted {
+			loader.Enqueue(change.CategoryId)
+			relationLoader.Enqueue(change.CategoryId)
+		}
+		connection.nodes = append(connection.nodes, &categoryChangeResolver{change})
+	}
+	return connection, nil
+}
+
+func (r *Resolver) CreateCategory(ctx context.Context, args struct{ Input createCategoryInput }) (category *categoryResolver, err error) {
+	defer recoverError(&err)
+
+	categoryCreateRequest := web.CategoryCreateRequest{Name: args.Input.Name}
+	if args.Input.Slug != nil {
+		categoryCreateRequest.Slug = *args.Input.Slug
+	}
+
+	categoryResponse := r.CategoryService.Create(ctx, categoryCreateRequest)
+	return &categoryResolver{categoryResponse}, nil
+}
+
+func (r *Resolver) UpdateCategory(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateCategoryInput
+}) (category *categoryResolver, err error) {
+	defer recoverError(&err)
+
+	categoryUpdateRequest := web.CategoryUpdateRequest{Id: parseId(args.ID), Name: args.Input.Name}
+	if args.Input.Slug != nil {
+		categoryUpdateRequest.Slug = *args.Input.Slug
+	}
+
+	categoryResponse := r.CategoryService.UpdateById(ctx, categoryUpdateRequest)
+	return &categoryResolver{categoryResponse}, nil
+}
+
+func (r *Resolver) DeleteCategory(ctx context.Context, args struct{ ID graphql.ID }) (id graphql.ID, err error) {
+	defer recoverError(&err)
+
+	r.CategoryService.DeleteById(ctx, parseId(args.ID))
+	return args.ID, nil
+}
+
+func parseId(id graphql.ID) int {
+	categoryId, err := strconv.Atoi(string(id))
+	if err != nil {
+		panic(exception.NewBadRequestError("id must be an integer"))
+	}
+	return categoryId
+}
@@ -0,0 +1,15 @@
+package graph
+
+import (
+	"Data-Category/service"
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+func NewSchema(categoryService service.CategoryService) *graphql.Schema {
+	return graphql.MustParseSchema(schema, NewResolver(categoryService), graphql.MaxDepth(10))
+}
@@ -0,0 +1,11 @@
+package helper
+
+import "time"
+
+// Clock returns the current time. It is injected wherever stored timestamps
+// are set so tests can control them.
+type Clock func() time.Time
+
+func NewClock() Clock {
+	return time.Now
+}
@@ -0,0 +1,201 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Codec encodes response bodies and decodes request bodies of one media type.
+type Codec interface {
+	ContentType() string
+	Encode(w io.Writer, v interface{}) error
+	Decode(r io.Reader, v interface{}) error
+}
+
+// CodecRegistry holds the codecs responses can be negotiated to. The first
+// registered codec is the default for requests without a preference.
+type CodecRegistry struct {
+	codecs []Codec
+}
+
+func NewCodecRegistry() *CodecRegistry {
+	registry := &CodecRegistry{}
+	registry.Register(JsonCodec{})
+	registry.Register(XmlCodec{})
+	registry.Register(MsgpackCodec{})
+	registry.Register(CsvCodec{})
+	return registry
+}
+
+func (cr *CodecRegistry) Register(codec Codec) {
+	cr.codecs = append(cr.codecs, codec)
+}
+
+func (cr *CodecRegistry) ContentTypes() []string {
+	contentTypes := make([]string, 0, len(cr.codecs))
+	for _, codec := range cr.codecs {
+		contentTypes = append(contentTypes, codec.ContentType())
+	}
+	return contentTypes
+}
+
+// Lookup finds the codec for a Content-Type header, ignoring its parameters.
+func (cr *CodecRegistry) Lookup(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	for _, codec := range cr.codecs {
+		if codec.ContentType() == mediaType {
+			return codec, true
+		}
+	}
+	return nil, false
+}
+
+// Negotiate picks the codec with the highest quality in an Accept header.
+// The most specific media range decides the quality of a codec and ties go to
+// the codec registered first. An empty header accepts the default codec.
+func (cr *CodecRegistry) Negotiate(accept string) (Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return cr.codecs[0], true
+	}
+	mediaRanges := ParseAccept(accept)
+
+	var best Codec
+	bestQuality := 0.0
+	for _, codec := range cr.codecs {
+		if quality := AcceptQuality(mediaRanges, codec.ContentType()); quality > bestQuality {
+			best, bestQuality = codec, quality
+		}
+	}
+	return best, best != nil
+}
+
+// MediaRange is one entry of an Accept header.
+type MediaRange struct {
+	Type    string
+	Subtype string
+	Quality float64
+}
+
+func (mr MediaRange) specificity() int {
+	switch {
+	case mr.Type == "*":
+		return 0
+	case mr.Subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ParseAccept parses an Accept header, most specific ranges first. Entries
+// that do not parse are skipped.
+func ParseAccept(accept string) []MediaRange {
+	var mediaRanges []MediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		types := strings.SplitN(mediaType, "/", 2)
+		if len(types) != 2 {
+			continue
+		}
+		mediaRange := MediaRange{Type: types[0], Subtype: types[1], Quality: 1}
+		if q, ok := params["q"]; ok {
+			quality, err := strconv.ParseFloat(q, 64)
+			if err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+			mediaRange.Quality = quality
+		}
+		mediaRanges = append(mediaRanges, mediaRange)
+	}
+	sort.SliceStable(mediaRanges, func(i, j int) bool {
+		return mediaRanges[i].specificity() > mediaRanges[j].specificity()
+	})
+	return mediaRanges
+}
+
+// AcceptQuality is the quality the most specific matching range gives to
+// contentType, 0 when no range matches.
+func AcceptQuality(mediaRanges []MediaRange, contentType string) float64 {
+	types := strings.SplitN(contentType, "/", 2)
+	for _, mediaRange := range mediaRanges {
+		if (mediaRange.Type == "*" || mediaRange.Type == types[0]) && (mediaRange.Subtype == "*" || mediaRange.Subtype == types[1]) {
+			return mediaRange.Quality
+		}
+	}
+	return 0
+}
+
+type codecRegistryContextKey struct{}
+
+func WithCodecRegistry(ctx context.Context, registry *CodecRegistry) context.Context {
+	return context.WithValue(ctx, codecRegistryContextKey{}, registry)
+}
+
+// CodecResponseWriter is a ResponseWriter whose body is encoded with the
+// negotiated Codec. Wrapping writers expose it through Unwrap.
+type CodecResponseWriter struct {
+	http.ResponseWriter
+	Codec Codec
+}
+
+func (cw *CodecResponseWriter) Flush() {
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *CodecResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// ResponseCodec returns the Codec negotiated for w, JsonCodec when there is
+// none.
+func ResponseCodec(w http.ResponseWriter) Codec {
+	for {
+		if cw, ok := w.(*CodecResponseWriter); ok {
+			return cw.Codec
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return JsonCodec{}
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// RequestCodec returns the Codec of the body of r, JsonCodec when there is
+// none.
+func RequestCodec(r *http.Request) Codec {
+	if registry, ok := r.Context().Value(codecRegistryContextKey{}).(*CodecRegistry); ok {
+		if codec, ok := registry.Lookup(r.Header.Get("Content-Type")); ok {
+			return codec
+		}
+	}
+	return JsonCodec{}
+}
+
+type JsonCodec struct{}
+
+func (JsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (JsonCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (JsonCodec) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
@@ -0,0 +1,168 @@
+package helper
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// CsvCodec encodes the data of a response as rows with a header line. Nested
+// objects are flattened into dotted column names and arrays are written as
+// JSON. Responses without object data, like errors, are a single row of the
+// response itself.
+type CsvCodec struct{}
+
+func (CsvCodec) ContentType() string {
+	return "text/csv"
+}
+
+func (CsvCodec) Encode(w io.Writer, v interface{}) error {
+	value, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	var rows []interface{}
+	switch data := csvData(value).(type) {
+	case []interface{}:
+		rows = data
+	default:
+		rows = []interface{}{data}
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		if _, ok := row.(*orderedMap); !ok {
+			row = &orderedMap{Keys: []string{"value"}, Values: map[string]interface{}{"value": row}}
+		}
+		record := map[string]string{}
+		flattenCsv("", row, record, func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		})
+		records = append(records, record)
+	}
+
+	writer := csv.NewWriter(w)
+	if len(columns) > 0 {
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+	}
+	for _, record := range records {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = record[column]
+		}
+		if err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvData picks the data of a web.WebResponse when it is an object or array.
+func csvData(value interface{}) interface{} {
+	if response, ok := value.(*orderedMap); ok {
+		switch data := response.Values["data"].(type) {
+		case *orderedMap, []interface{}:
+			return data
+		}
+	}
+	return value
+}
+
+func flattenCsv(prefix string, value interface{}, record map[string]string, column func(string)) {
+	switch value := value.(type) {
+	case *orderedMap:
+		for _, key := range value.Keys {
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			flattenCsv(name, value.Values[key], record, column)
+		}
+		return
+	case []interface{}:
+		data, _ := json.Marshal(toPlain(value))
+		record[prefix] = string(data)
+	case nil:
+		record[prefix] = ""
+	case bool:
+		record[prefix] = "false"
+		if value {
+			record[prefix] = "true"
+		}
+	case json.Number:
+		record[prefix] = value.String()
+	case string:
+		record[prefix] = value
+	}
+	column(prefix)
+}
+
+// toPlain turns normalized values back into values encoding/json can encode.
+func toPlain(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *orderedMap:
+		object := make(map[string]interface{}, len(value.Keys))
+		for _, key := range value.Keys {
+			object[key] = toPlain(value.Values[key])
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, item := range value {
+			array[i] = toPlain(item)
+		}
+		return array
+	default:
+		return value
+	}
+}
+
+// Decode reads the first row after the header into v. Dotted column names
+// are nested objects, like the encoder writes them.
+func (CsvCodec) Decode(r io.Reader, v interface{}) error {
+	reader := csv.NewReader(r)
+	columns, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	line, err := reader.Read()
+	if err == io.EOF {
+		return errors.New("csv body has no rows")
+	} else if err != nil {
+		return err
+	}
+
+	object := map[string]interface{}{}
+	for i, column := range columns {
+		parts := strings.Split(column, ".")
+		current := object
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[part] = next
+			}
+			current = next
+		}
+		var cell interface{} = line[i]
+		if strings.HasPrefix(line[i], "[") {
+			var array []interface{}
+			if json.Unmarshal([]byte(line[i]), &array) == nil {
+				cell = array
+			}
+		}
+		current[parts[len(parts)-1]] = cell
+	}
+	return assign(object, v)
+}
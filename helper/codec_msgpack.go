@@ -0,0 +1,286 @@
+package helper
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// MsgpackCodec encodes values as MessagePack maps, arrays and scalars with
+// the same field names as JSON.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+func (MsgpackCodec) Encode(w io.Writer, v interface{}) error {
+	value, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(w)
+	if err := encodeMsgpack(writer, value); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func encodeMsgpack(w *bufio.Writer, value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return w.WriteByte(0xc0)
+	case bool:
+		if value {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+	case json.Number:
+		if i, err := strconv.ParseInt(value.String(), 10, 64); err == nil {
+			return encodeMsgpackInt(w, i)
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return err
+		}
+		w.WriteByte(0xcb)
+		return binary.Write(w, binary.BigEndian, math.Float64bits(f))
+	case string:
+		encodeMsgpackLength(w, len(value), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		_, err := w.WriteString(value)
+		return err
+	case []interface{}:
+		encodeMsgpackLength(w, len(value), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range value {
+			if err := encodeMsgpack(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *orderedMap:
+		encodeMsgpackLength(w, len(value.Keys), 0x80, 16, 0, 0xde, 0xdf)
+		for _, key := range value.Keys {
+			if err := encodeMsgpack(w, key); err != nil {
+				return err
+			}
+			if err := encodeMsgpack(w, value.Values[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("msgpack: unsupported value %T", value)
+	}
+}
+
+func encodeMsgpackInt(w *bufio.Writer, i int64) error {
+	switch {
+	case i >= 0 && i < 128:
+		return w.WriteByte(byte(i))
+	case i >= -32 && i < 0:
+		return w.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		w.WriteByte(0xd0)
+		return w.WriteByte(byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		w.WriteByte(0xd1)
+		return binary.Write(w, binary.BigEndian, int16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		w.WriteByte(0xd2)
+		return binary.Write(w, binary.BigEndian, int32(i))
+	default:
+		w.WriteByte(0xd3)
+		return binary.Write(w, binary.BigEndian, i)
+	}
+}
+
+// encodeMsgpackLength writes the header of a string, array or map: the fix
+// format below fixLimit, else the 8 (if any), 16 or 32 bit format.
+func encodeMsgpackLength(w *bufio.Writer, n int, fix byte, fixLimit int, code8 byte, code16 byte, code32 byte) {
+	switch {
+	case n < fixLimit:
+		w.WriteByte(fix | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		w.WriteByte(code8)
+		w.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		w.WriteByte(code16)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(code32)
+		binary.Write(w, binary.BigEndian, uint32(n))
+	}
+}
+
+func (MsgpackCodec) Decode(r io.Reader, v interface{}) error {
+	value, err := decodeMsgpack(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+	return assign(value, v)
+}
+
+var errMsgpackFormat = errors.New("msgpack: unsupported format")
+
+func decodeMsgpack(r *bufio.Reader) (interface{}, error) {
+	code, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return readMsgpackString(r, int(code&0x1f))
+	case code&0xf0 == 0x90:
+		return readMsgpackArray(r, int(code&0x0f))
+	case code&0xf0 == 0x80:
+		return readMsgpackMap(r, int(code&0x0f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3:
+		return readMsgpackInt(r, code)
+	case 0xca:
+		var bits uint32
+		err := binary.Read(r, binary.BigEndian, &bits)
+		return float64(math.Float32frombits(bits)), err
+	case 0xcb:
+		var bits uint64
+		err := binary.Read(r, binary.BigEndian, &bits)
+		return math.Float64frombits(bits), err
+	case 0xd9, 0xc4:
+		n, err := readMsgpackLength(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackString(r, n)
+	case 0xda, 0xc5:
+		n, err := readMsgpackLength(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackString(r, n)
+	case 0xdb, 0xc6:
+		n, err := readMsgpackLength(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackString(r, n)
+	case 0xdc, 0xdd:
+		n, err := readMsgpackLength(r, 2+2*int(code-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackArray(r, n)
+	case 0xde, 0xdf:
+		n, err := readMsgpackLength(r, 2+2*int(code-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackMap(r, n)
+	}
+	return nil, errMsgpackFormat
+}
+
+func readMsgpackInt(r *bufio.Reader, code byte) (interface{}, error) {
+	var err error
+	switch code {
+	case 0xcc:
+		var i uint8
+		err = binary.Read(r, binary.BigEndian, &i)
+		return int64(i), err
+	case 0xcd:
+		var i uint16
+		err = binary.Read(r, binary.BigEndian, &i)
+		return int64(i), err
+	case 0xce:
+		var i uint32
+		err = binary.Read(r, binary.BigEndian, &i)
+		return int64(i), err
+	case 0xcf:
+		var i uint64
+		err = binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xd0:
+		var i int8
+		err = binary.Read(r, binary.BigEndian, &i)
+		return int64(i), err
+	case 0xd1:
+		var i int16
+		err = binary.Read(r, binary.BigEndian, &i)
+		return int64(i), err
+	case 0xd2:
+		var i int32
+		err = binary.Read(r, binary.BigEndian, &i)
+		return int64(i), err
+	default:
+		var i int64
+		err = binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	}
+}
+
+func readMsgpackLength(r *bufio.Reader, size int) (int, error) {
+	buffer := make([]byte, size)
+	if _, err := io.ReadFull(r, buffer); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, b := range buffer {
+		n = n<<8 | int(b)
+	}
+	return n, nil
+}
+
+func readMsgpackString(r *bufio.Reader, n int) (interface{}, error) {
+	buffer := make([]byte, n)
+	_, err := io.ReadFull(r, buffer)
+	return string(buffer), err
+}
+
+func readMsgpackArray(r *bufio.Reader, n int) (interface{}, error) {
+	array := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		item, err := decodeMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+	return array, nil
+}
+
+func readMsgpackMap(r *bufio.Reader, n int) (interface{}, error) {
+	object := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := decodeMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, errors.New("msgpack: map keys must be strings")
+		}
+		value, err := decodeMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		object[name] = value
+	}
+	return object, nil
+}
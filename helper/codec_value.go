@@ -0,0 +1,160 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// orderedMap is a JSON object that keeps the order of its keys, so encoders
+// of other formats emit fields in the order of the struct they came from.
+type orderedMap struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// normalize turns v into the values its JSON encoding decodes to, with
+// orderedMap for objects and json.Number for numbers. Encoding through JSON
+// first means every format uses the field names of the json tags.
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		object := &orderedMap{Values: map[string]interface{}{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.Keys = append(object.Keys, key.(string))
+			object.Values[key.(string)] = value
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// assign stores a decoded value in v through JSON, after converting the
+// strings of text formats to the kinds of the fields of v.
+func assign(value interface{}, v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.New("decode target must be a non-nil pointer")
+	}
+	data, err := json.Marshal(coerce(value, target.Type().Elem()))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func coerce(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s, isString := value.(string)
+
+	switch {
+	case t == timeType:
+		return value
+	case t.Kind() == reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		result := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := jsonFieldName(field)
+			if name == "" {
+				continue
+			}
+			if fieldValue, ok := object[name]; ok {
+				result[name] = coerce(fieldValue, field.Type)
+			}
+		}
+		return result
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		if value == nil {
+			return nil
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			result = append(result, coerce(item, t.Elem()))
+		}
+		return result
+	case t.Kind() == reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		result := map[string]interface{}{}
+		for key, item := range object {
+			result[key] = coerce(item, t.Elem())
+		}
+		return result
+	case isString && t.Kind() == reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case isString && t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s)
+		}
+	}
+	return value
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
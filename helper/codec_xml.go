@@ -0,0 +1,142 @@
+package helper
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+const xmlItemElement = "item"
+
+// XmlCodec encodes values as their JSON structure in XML: objects become
+// elements named after their keys and array entries become <item> elements,
+// all inside a <response> root.
+type XmlCodec struct{}
+
+func (XmlCodec) ContentType() string {
+	return "application/xml"
+}
+
+func (XmlCodec) Encode(w io.Writer, v interface{}) error {
+	value, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if err := encodeXmlElement(encoder, "response", value); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+func encodeXmlElement(encoder *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case *orderedMap:
+		for _, key := range value.Keys {
+			if err := encodeXmlElement(encoder, key, value.Values[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := encodeXmlElement(encoder, xmlItemElement, item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	case json.Number:
+		if err := encoder.EncodeToken(xml.CharData(value)); err != nil {
+			return err
+		}
+	case bool:
+		text := "false"
+		if value {
+			text = "true"
+		}
+		if err := encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	case string:
+		if err := encoder.EncodeToken(xml.CharData(value)); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+func (XmlCodec) Decode(r io.Reader, v interface{}) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := decodeXmlElement(decoder, start)
+			if err != nil {
+				return err
+			}
+			return assign(value, v)
+		}
+	}
+}
+
+// decodeXmlElement reads an element into a string when it only has text, a
+// slice when all children are <item> and a map otherwise. Repeated children
+// of the same name are collected into a slice.
+func decodeXmlElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	var text strings.Builder
+	var names []string
+	children := map[string][]interface{}{}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXmlElement(decoder, token)
+			if err != nil {
+				return nil, err
+			}
+			name := token.Name.Local
+			if _, ok := children[name]; !ok {
+				names = append(names, name)
+			}
+			children[name] = append(children[name], child)
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			if token.Name != start.Name {
+				return nil, errors.New("unexpected end element " + token.Name.Local)
+			}
+			if len(names) == 0 {
+				return strings.TrimSpace(text.String()), nil
+			}
+			if len(names) == 1 && names[0] == xmlItemElement {
+				return children[xmlItemElement], nil
+			}
+			object := map[string]interface{}{}
+			for _, name := range names {
+				if len(children[name]) == 1 {
+					object[name] = children[name][0]
+				} else {
+					object[name] = children[name]
+				}
+			}
+			return object, nil
+		}
+	}
+}
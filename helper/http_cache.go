@@ -0,0 +1,28 @@
+package helper
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NotModified evaluates the conditional headers of a GET request against the
+// validators of the current representation. If-None-Match takes precedence
+// over If-Modified-Since and is compared weakly.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
@@ -1,19 +1,27 @@
 package helper
 
 import (
-	"encoding/json"
 	"net/http"
 )
 
+// ReadFromRequestBody decodes the body with the codec of its Content-Type,
+// JSON when there is none.
 func ReadFromRequestBody(r *http.Request, result interface{}) {
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(result)
+	err := RequestCodec(r).Decode(r.Body, result)
 	PanicIfError(err)
 }
 
+// WriteToResponseBody encodes result with the codec negotiated for the
+// response, JSON when there is none.
 func WriteToResponseBody(w http.ResponseWriter, result interface{}) {
-	w.Header().Add("Content-Type", "application/json")
-	encoder := json.NewEncoder(w)
-	err := encoder.Encode(result)
+	codec := ResponseCodec(w)
+	w.Header().Set("Content-Type", codec.ContentType())
+	err := codec.Encode(w, result)
 	PanicIfError(err)
 }
+
+// SetContentType sets the Content-Type of the negotiated codec, for handlers
+// that write the status code before the body.
+func SetContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ResponseCodec(w).ContentType())
+}
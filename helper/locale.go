@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+type localesContextKey struct{}
+
+// WithLocales stores the locales names are resolved in, most preferred
+// first.
+func WithLocales(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, localesContextKey{}, locales)
+}
+
+// LocalesFromContext returns the locales of WithLocales, none when names are
+// not localized.
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(localesContextKey{}).([]string)
+	return locales
+}
+
+// CanonicalLocale returns the BCP 47 form of a language tag, like en-GB for
+// en_gb, and false when it is not a language tag.
+func CanonicalLocale(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", false
+	}
+	return tag.String(), true
+}
+
+// LocaleChain lists tags with their parents, so en-GB falls back to en,
+// followed by fallback. Duplicates are left out.
+func LocaleChain(tags []language.Tag, fallback []string) []string {
+	var chain []string
+	seen := map[string]bool{}
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			chain = append(chain, locale)
+		}
+	}
+	for _, tag := range tags {
+		for ; tag != language.Und; tag = tag.Parent() {
+			add(tag.String())
+		}
+	}
+	for _, locale := range fallback {
+		add(locale)
+	}
+	return chain
+}
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"Data-Category/model/domain"
+	"context"
+	"errors"
+)
+
+type principalContextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal domain.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (domain.Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(domain.Principal)
+	return principal, ok
+}
+
+// TenantIdFromContext returns the tenant of the authenticated principal and
+// panics when there is none, so tenant scoped queries can never run unscoped.
+func TenantIdFromContext(ctx context.Context) int {
+	return MustPrincipalFromContext(ctx).TenantId
+}
+
+func MustPrincipalFromContext(ctx context.Context) domain.Principal {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		panic(errors.New("no authenticated principal in context"))
+	}
+	return principal
+}
@@ -0,0 +1,97 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLength = 190
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// transliterations covers letters that do not decompose into an ASCII base
+// letter plus combining marks under NFKD.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th",
+	'ı': "i", '&': "and",
+}
+
+// Slugify turns name into a lowercase, URL-safe slug made of ASCII letters,
+// digits and single dashes, e.g. "Crème Brûlée & Co" becomes
+// "creme-brulee-and-co".
+func Slugify(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(name) {
+		if replacement, ok := transliterations[r]; ok {
+			if dash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			builder.WriteString(replacement)
+			dash = false
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			builder.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	slug := builder.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		return "category"
+	}
+	return slug
+}
+
+func IsSlug(slug string) bool {
+	return slugPattern.MatchString(slug)
+}
+
+// Fold lowercases s and strips its accents like Slugify, but keeps every
+// letter and digit and separates words by single spaces, e.g. "Crème  Brûlée"
+// becomes "creme brulee". Folded strings compare case and accent
+// insensitively.
+func Fold(s string) string {
+	var builder strings.Builder
+	space := false
+	for _, r := range norm.NFKD.String(s) {
+		if replacement, ok := transliterations[r]; ok && r != '&' {
+			if space && builder.Len() > 0 {
+				builder.WriteByte(' ')
+			}
+			builder.WriteString(replacement)
+			space = false
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && builder.Len() > 0 {
+				builder.WriteByte(' ')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return builder.String()
+}
@@ -1,9 +1,27 @@
 package helper
 
-import "database/sql"
+import (
+	"database/sql"
+	"sync"
+)
+
+var (
+	afterCommitMutex sync.Mutex
+	afterCommit      = map[*sql.Tx][]func(){}
+)
+
+// OnCommit registers fn to be called once CommitOrRollback has committed tx.
+// It is dropped when tx is rolled back instead.
+func OnCommit(tx *sql.Tx, fn func()) {
+	afterCommitMutex.Lock()
+	defer afterCommitMutex.Unlock()
+
+	afterCommit[tx] = append(afterCommit[tx], fn)
+}
 
 func CommitOrRollback(tx *sql.Tx) {
 	err := recover()
+	hooks := takeAfterCommit(tx)
 	if err != nil {
 		errorRollback := tx.Rollback()
 		PanicIfError(errorRollback)
@@ -11,5 +29,17 @@ func CommitOrRollback(tx *sql.Tx) {
 	} else {
 		errorCommit := tx.Commit()
 		PanicIfError(errorCommit)
+		for _, hook := range hooks {
+			hook()
+		}
 	}
 }
+
+func takeAfterCommit(tx *sql.Tx) []func() {
+	afterCommitMutex.Lock()
+	defer afterCommitMutex.Unlock()
+
+	hooks := afterCommit[tx]
+	delete(afterCommit, tx)
+	return hooks
+}
@@ -1,23 +1,79 @@
 package main
 
 import (
+	"Data-Category/api"
+	"Data-Category/app"
 	"Data-Category/helper"
 	"Data-Category/middleware"
+	"Data-Category/service"
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
 
+	"github.com/go-chi/chi/v5"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 )
 
-func NewServer(am *middleware.AuthMiddleware) *http.Server {
+// App is the HTTP and gRPC servers together with the background workers that
+// run alongside them.
+type App struct {
+	Server        *http.Server
+	Router        *chi.Mux
+	GrpcServer    *grpc.Server
+	GrpcConfig    app.GrpcConfig
+	WebhookWorker *service.WebhookWorker
+	SuggestIndex  *service.CategorySuggestIndex
+}
+
+func NewApp(server *http.Server, router *chi.Mux, grpcServer *grpc.Server, grpcConfig app.GrpcConfig, webhookWorker *service.WebhookWorker, suggestIndex *service.CategorySuggestIndex) *App {
+	return &App{
+		Server:        server,
+		Router:        router,
+		GrpcServer:    grpcServer,
+		GrpcConfig:    grpcConfig,
+		WebhookWorker: webhookWorker,
+		SuggestIndex:  suggestIndex,
+	}
+}
+
+// NewServer limits requests by client IP before they are authenticated.
+func NewServer(am *middleware.AuthMiddleware, rm *middleware.RateLimitMiddleware) *http.Server {
 	return &http.Server{
 		Addr:    "localhost:3000",
-		Handler: am,
+		Handler: rm.WrapIp(am),
 	}
 }
 
 func main() {
-	server := InitializeServer()
+	a := InitializeApp()
+
+	// check-routes reports drift between the router and APIspec.json.
+	if len(os.Args) > 1 && os.Args[1] == "check-routes" {
+		report, err := app.CheckRoutes(a.Router, api.NewDocument())
+		helper.PanicIfError(err)
+		fmt.Print(report)
+		if !report.Ok() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.SuggestIndex.Build(ctx)
+	go a.SuggestIndex.Run(ctx)
+	go a.WebhookWorker.Run(ctx)
+
+	listener, err := net.Listen("tcp", a.GrpcConfig.Addr)
+	helper.PanicIfError(err)
+	go func() {
+		err := a.GrpcServer.Serve(listener)
+		helper.PanicIfError(err)
+	}()
 
-	err := server.ListenAndServe()
+	err = a.Server.ListenAndServe()
 	helper.PanicIfError(err)
 }
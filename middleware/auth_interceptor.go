@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"Data-Category/helper"
+	"Data-Category/service"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ApiKeyMetadata is the gRPC metadata key carrying the API key, the
+// counterpart of the X-API-KEY header.
+const ApiKeyMetadata = "x-api-key"
+
+// AuthInterceptor authenticates gRPC calls with the same rules as
+// AuthMiddleware and puts the principal into the context of the handler.
+type AuthInterceptor struct {
+	TenantService service.TenantService
+}
+
+func NewAuthInterceptor(tenantService service.TenantService) *AuthInterceptor {
+	return &AuthInterceptor{
+		TenantService: tenantService,
+	}
+}
+
+func (ai *AuthInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := ai.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (ai *AuthInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := ai.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+func (ai *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	var apiKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ApiKeyMetadata); len(values) > 0 {
+			apiKey = values[0]
+		}
+	}
+	principal, ok := ai.TenantService.Authenticate(ctx, apiKey)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	return helper.WithPrincipal(ctx, principal), nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (as *authenticatedStream) Context() context.Context {
+	return as.ctx
+}
@@ -1,26 +1,30 @@
 package middleware
 
 import (
+	"Data-Category/exception"
 	"Data-Category/helper"
 	"Data-Category/model/web"
+	"Data-Category/service"
 	"net/http"
 )
 
 type AuthMiddleware struct {
-	Handler http.Handler
+	Handler       http.Handler
+	TenantService service.TenantService
 }
 
-func NewAuthMiddleware(h http.Handler) *AuthMiddleware {
+func NewAuthMiddleware(h http.Handler, tenantService service.TenantService) *AuthMiddleware {
 	return &AuthMiddleware{
-		Handler: h,
+		Handler:       h,
+		TenantService: tenantService,
 	}
 }
 
 func (a *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if "RAHASIA" == r.Header.Get("X-API-KEY") {
-		a.Handler.ServeHTTP(w, r)
+	if principal, ok := a.TenantService.Authenticate(r.Context(), r.Header.Get("X-API-KEY")); ok {
+		a.Handler.ServeHTTP(w, r.WithContext(helper.WithPrincipal(r.Context(), principal)))
 	} else {
-		w.Header().Set("Content-Type", "application/json")
+		helper.SetContentType(w)
 		w.WriteHeader(http.StatusUnauthorized)
 		webResponse := web.WebResponse{
 			Code:   http.StatusUnauthorized,
@@ -29,3 +33,13 @@ func (a *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		helper.WriteToResponseBody(w, webResponse)
 	}
 }
+
+// RequireAdmin only lets requests of the admin principal through.
+func RequireAdmin(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := helper.PrincipalFromContext(r.Context()); !ok || !principal.Admin {
+			panic(exception.NewForbiddenError("admin API key is required"))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
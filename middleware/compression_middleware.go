@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+type CompressionConfig struct {
+	// MinSize is the body size from which responses are compressed; smaller
+	// bodies cost more to compress than they save.
+	MinSize int
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressionEncodings are the supported content codings in order of
+// preference when the client accepts several with the same quality.
+var compressionEncodings = []string{"br", "zstd", "gzip"}
+
+type CompressionMiddleware struct {
+	Config CompressionConfig
+	pools  map[string]*sync.Pool
+}
+
+func NewCompressionMiddleware(config CompressionConfig) *CompressionMiddleware {
+	return &CompressionMiddleware{
+		Config: config,
+		pools: map[string]*sync.Pool{
+			"br": {New: func() interface{} {
+				return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+			}},
+			"zstd": {New: func() interface{} {
+				encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+				return encoder
+			}},
+			"gzip": {New: func() interface{} {
+				return gzip.NewWriter(nil)
+			}},
+		},
+	}
+}
+
+// Wrap compresses responses with the coding negotiated from Accept-Encoding.
+// The body is buffered until MinSize to decide whether compressing is worth
+// it. A Flush before that, as Server-Sent Events do after every event, sends
+// the response uncompressed, so streams are never held back.
+func (cm *CompressionMiddleware) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{ResponseWriter: w, middleware: cm, encoding: encoding}
+		defer cw.close()
+		h.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the supported coding with the highest quality in
+// an Accept-Encoding header, "" for identity.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					q = 0
+				}
+				quality = q
+			}
+		}
+		qualities[coding] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range compressionEncodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+type compressResponseWriter struct {
+	http.ResponseWriter
+	middleware *CompressionMiddleware
+	encoding   string
+
+	statusCode  int
+	buffer      []byte
+	decided     bool
+	compressor  compressor
+	wroteHeader bool
+}
+
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressResponseWriter) WriteHeader(statusCode int) {
+	if cw.statusCode != 0 {
+		return
+	}
+	cw.statusCode = statusCode
+	if statusCode == http.StatusNotModified || statusCode == http.StatusNoContent || statusCode < 200 {
+		cw.passthrough()
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if cw.statusCode == 0 {
+		cw.statusCode = http.StatusOK
+	}
+	if !cw.decided {
+		if !cw.compressible() {
+			cw.passthrough()
+		} else {
+			cw.buffer = append(cw.buffer, b...)
+			if len(cw.buffer) >= cw.middleware.Config.MinSize {
+				cw.compress()
+			}
+			return len(b), nil
+		}
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		cw.passthrough()
+	}
+	if cw.compressor != nil {
+		_ = cw.compressor.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressResponseWriter) compressible() bool {
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	return !strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
+
+func (cw *compressResponseWriter) compress() {
+	cw.decided = true
+	header := cw.Header()
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	cw.writeHeader()
+
+	cw.compressor = cw.middleware.pools[cw.encoding].Get().(compressor)
+	cw.compressor.Reset(cw.ResponseWriter)
+	_, _ = cw.compressor.Write(cw.buffer)
+	cw.buffer = nil
+}
+
+func (cw *compressResponseWriter) passthrough() {
+	cw.decided = true
+	cw.writeHeader()
+	if len(cw.buffer) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buffer)
+		cw.buffer = nil
+	}
+}
+
+func (cw *compressResponseWriter) writeHeader() {
+	if !cw.wroteHeader && cw.statusCode != 0 {
+		cw.wroteHeader = true
+		cw.ResponseWriter.WriteHeader(cw.statusCode)
+	}
+}
+
+func (cw *compressResponseWriter) close() {
+	if !cw.decided {
+		cw.passthrough()
+	}
+	if cw.compressor != nil {
+		_ = cw.compressor.Close()
+		cw.compressor.Reset(nil)
+		cw.middleware.pools[cw.encoding].Put(cw.compressor)
+		cw.compressor = nil
+	}
+}
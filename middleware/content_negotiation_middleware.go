@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"net/http"
+	"strings"
+)
+
+type ContentNegotiationMiddleware struct {
+	CodecRegistry *helper.CodecRegistry
+	// StreamTypes are media types handlers write themselves, like
+	// text/event-stream; accepting one of them is never answered with 406.
+	StreamTypes []string
+}
+
+func NewContentNegotiationMiddleware(codecRegistry *helper.CodecRegistry) *ContentNegotiationMiddleware {
+	return &ContentNegotiationMiddleware{
+		CodecRegistry: codecRegistry,
+		StreamTypes:   []string{"text/event-stream"},
+	}
+}
+
+// Wrap picks the codec of the response from the Accept header and the codec
+// of the request body from Content-Type. It runs before exception.ErrorHandler
+// so error responses are negotiated too, which is why it answers 406 and 415
+// itself instead of panicking.
+func (cm *ContentNegotiationMiddleware) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		accept := r.Header.Get("Accept")
+		codec, ok := cm.CodecRegistry.Negotiate(accept)
+		if !ok && cm.acceptsStream(accept) {
+			codec, ok = helper.JsonCodec{}, true
+		}
+		if !ok {
+			writeNegotiationError(w, http.StatusNotAcceptable, "Not Acceptable",
+				"supported media types are "+strings.Join(cm.CodecRegistry.ContentTypes(), ", "))
+			return
+		}
+
+		if contentType := r.Header.Get("Content-Type"); contentType != "" && r.ContentLength != 0 {
+			if _, ok := cm.CodecRegistry.Lookup(contentType); !ok {
+				writeNegotiationError(w, http.StatusUnsupportedMediaType, "Unsupported Media Type",
+					"supported media types are "+strings.Join(cm.CodecRegistry.ContentTypes(), ", "))
+				return
+			}
+		}
+
+		ctx := helper.WithCodecRegistry(r.Context(), cm.CodecRegistry)
+		h.ServeHTTP(&helper.CodecResponseWriter{ResponseWriter: w, Codec: codec}, r.WithContext(ctx))
+	})
+}
+
+func (cm *ContentNegotiationMiddleware) acceptsStream(accept string) bool {
+	mediaRanges := helper.ParseAccept(accept)
+	for _, streamType := range cm.StreamTypes {
+		if helper.AcceptQuality(mediaRanges, streamType) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func writeNegotiationError(w http.ResponseWriter, code int, status string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	webResponse := web.WebResponse{
+		Code:   code,
+		Status: status,
+		Data:   message,
+	}
+	helper.WriteToResponseBody(w, webResponse)
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/repository"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 200
+)
+
+type IdempotencyConfig struct {
+	// TTL is how long a key replays its response. Expired keys are purged
+	// by the repository.
+	TTL time.Duration
+}
+
+type IdempotencyMiddleware struct {
+	IdempotencyRepository repository.IdempotencyRepository
+	Config                IdempotencyConfig
+}
+
+func NewIdempotencyMiddleware(idempotencyRepository repository.IdempotencyRepository, config IdempotencyConfig) *IdempotencyMiddleware {
+	if config.TTL <= 0 {
+		panic("idempotency key TTL must be positive")
+	}
+	return &IdempotencyMiddleware{
+		IdempotencyRepository: idempotencyRepository,
+		Config:                config,
+	}
+}
+
+// Wrap makes h idempotent for requests carrying an Idempotency-Key header.
+// The first request with a key is served normally and its response stored;
+// repeats with the same payload and media types replay it, repeats with
+// another payload or Content-Type, or that accept another media type, are
+// rejected with 422 and repeats while the first is still running with 409.
+func (im *IdempotencyMiddleware) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			panic(exception.NewUnprocessableEntityError("idempotency key is too long"))
+		}
+
+		body, err := io.ReadAll(r.Body)
+		helper.PanicIfError(err)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are only unique per tenant, so they are stored with the tenant id.
+		key = strconv.Itoa(helper.TenantIdFromContext(r.Context())) + ":" + key
+		record := domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash(w, r, body),
+			ExpiresAt:   time.Now().Add(im.Config.TTL),
+		}
+
+		if !im.IdempotencyRepository.Save(r.Context(), record) {
+			existing, err := im.IdempotencyRepository.FindByKey(r.Context(), key)
+			if err != nil {
+				// The previous record expired between both calls, so the key can be taken again.
+				im.Wrap(h).ServeHTTP(w, r)
+				return
+			}
+			replay(w, existing, record.RequestHash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed {
+				im.IdempotencyRepository.DeleteByKey(r.Context(), key)
+			}
+		}()
+
+		h.ServeHTTP(recorder, r)
+
+		record.StatusCode = recorder.statusCode
+		record.Body = recorder.body.Bytes()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		im.IdempotencyRepository.UpdateByKey(r.Context(), record)
+		completed = true
+	})
+}
+
+func replay(w http.ResponseWriter, record domain.IdempotencyRecord, requestHash string) {
+	if record.RequestHash != requestHash {
+		panic(exception.NewUnprocessableEntityError("idempotency key was already used with a different payload or media type"))
+	}
+	if !record.Completed() {
+		panic(exception.NewConflictError("request with this idempotency key is still being processed"))
+	}
+
+	w.Header().Set("Content-Type", record.ContentType)
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, err := w.Write(record.Body)
+	helper.PanicIfError(err)
+}
+
+// requestHash covers the codecs of the request and the response as well, so
+// a repeat that negotiates another representation is not served the stored
+// one.
+func requestHash(w http.ResponseWriter, r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write([]byte(helper.RequestCodec(r).ContentType()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(helper.ResponseCodec(w).ContentType()))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if !rr.wroteHeader {
+		rr.statusCode = statusCode
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+type LocaleConfig struct {
+	// Fallback are the locales tried after the requested ones, before the
+	// untranslated name.
+	Fallback []string
+}
+
+type LocaleMiddleware struct {
+	Config LocaleConfig
+}
+
+func NewLocaleMiddleware(config LocaleConfig) *LocaleMiddleware {
+	return &LocaleMiddleware{
+		Config: config,
+	}
+}
+
+// Wrap stores the locales names are resolved in. The lang query parameter
+// takes precedence over Accept-Language, whose unparsable entries are
+// ignored.
+func (lm *LocaleMiddleware) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+
+		var tags []language.Tag
+		if lang := r.URL.Query().Get("lang"); lang != "" {
+			tag, err := language.Parse(lang)
+			if err != nil {
+				panic(exception.NewBadRequestError("lang must be a language tag"))
+			}
+			tags = append(tags, tag)
+		} else if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
+			accepted, qualities, _ := language.ParseAcceptLanguage(acceptLanguage)
+			for i, tag := range accepted {
+				if qualities[i] > 0 && tag != language.Make("mul") {
+					tags = append(tags, tag)
+				}
+			}
+		}
+
+		locales := helper.LocaleChain(tags, lm.Config.Fallback)
+		h.ServeHTTP(w, r.WithContext(helper.WithLocales(r.Context(), locales)))
+	})
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"Data-Category/api"
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"bytes"
+	"io"
+	"net/http"
+)
+
+type OpenApiConfig struct {
+	ValidateRequests bool
+}
+
+type OpenApiMiddleware struct {
+	Document *api.Document
+	Config   OpenApiConfig
+}
+
+func NewOpenApiMiddleware(document *api.Document, config OpenApiConfig) *OpenApiMiddleware {
+	return &OpenApiMiddleware{
+		Document: document,
+		Config:   config,
+	}
+}
+
+// Wrap rejects requests to documented operations whose parameters or body do
+// not match APIspec.json with 400. Undocumented routes are passed through.
+func (om *OpenApiMiddleware) Wrap(h http.Handler) http.Handler {
+	if !om.Config.ValidateRequests {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, ok := om.Document.FindRoute(r.Method, r.URL.Path)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			b, err := io.ReadAll(r.Body)
+			helper.PanicIfError(err)
+			r.Body = io.NopCloser(bytes.NewReader(b))
+			body = b
+		}
+
+		if err := om.Document.ValidateRequest(route, r, body); err != nil {
+			panic(exception.NewBadRequestError(err.Error()))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
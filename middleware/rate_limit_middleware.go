@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RateLimitConfig struct {
+	Read  domain.RateLimitBudget
+	Write domain.RateLimitBudget
+	// Ip is the budget of every client IP, spent by all requests before
+	// they are authenticated, so floods of unauthenticated requests and
+	// guessed API keys are limited too.
+	Ip domain.RateLimitBudget
+}
+
+type RateLimitMiddleware struct {
+	RateLimitRepository repository.RateLimitRepository
+	Config              RateLimitConfig
+}
+
+func NewRateLimitMiddleware(rateLimitRepository repository.RateLimitRepository, config RateLimitConfig) *RateLimitMiddleware {
+	for _, budget := range []domain.RateLimitBudget{config.Read, config.Write, config.Ip} {
+		if budget.Limit <= 0 || budget.Window <= 0 {
+			panic("rate limit budgets need a positive limit and window")
+		}
+	}
+	return &RateLimitMiddleware{
+		RateLimitRepository: rateLimitRepository,
+		Config:              config,
+	}
+}
+
+// Wrap limits every API key to the read or write budget depending on the
+// request method and reports the remaining quota in RateLimit-* headers. It
+// runs after authentication, so every request carries a valid API key.
+func (rm *RateLimitMiddleware) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, budget := "write", rm.Config.Write
+		if isReadMethod(r.Method) {
+			scope, budget = "read", rm.Config.Read
+		}
+		rm.limit(w, r, h, scope+":"+apiKeyHash(r), budget)
+	})
+}
+
+// WrapIp limits every client IP to the IP budget. It runs before
+// authentication; the headers of Wrap replace its own once a request gets
+// that far.
+func (rm *RateLimitMiddleware) WrapIp(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rm.limit(w, r, h, "ip:"+clientIp(r), rm.Config.Ip)
+	})
+}
+
+func (rm *RateLimitMiddleware) limit(w http.ResponseWriter, r *http.Request, h http.Handler, key string, budget domain.RateLimitBudget) {
+	status := rm.RateLimitRepository.Take(r.Context(), key, budget)
+
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", budget.Limit, int(budget.Window.Seconds())))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(status.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(status.Reset)))
+
+	if status.Allowed {
+		h.ServeHTTP(w, r)
+	} else {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(status.RetryAfter)))
+		helper.SetContentType(w)
+		w.WriteHeader(http.StatusTooManyRequests)
+		webResponse := web.WebResponse{
+			Code:   http.StatusTooManyRequests,
+			Status: "Too Many Requests",
+		}
+		helper.WriteToResponseBody(w, webResponse)
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// apiKeyHash identifies the caller by API key. API keys are hashed so they
+// are never kept in the limiter store.
+func apiKeyHash(r *http.Request) string {
+	sum := sha256.Sum256([]byte(r.Header.Get("X-API-KEY")))
+	return "key:" + hex.EncodeToString(sum[:])
+}
+
+func clientIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
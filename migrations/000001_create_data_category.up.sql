@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS data_category (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL
+);
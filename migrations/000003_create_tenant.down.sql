@@ -0,0 +1,3 @@
+ALTER TABLE data_category DROP CONSTRAINT IF EXISTS data_category_tenant_id_name_key;
+ALTER TABLE data_category DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS tenant;
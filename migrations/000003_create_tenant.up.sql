@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS tenant (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL UNIQUE,
+    api_key_hash CHAR(64) UNIQUE,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Existing categories belong to the default tenant, which is reached with the admin API key.
+INSERT INTO tenant(id, name) VALUES (1, 'default') ON CONFLICT DO NOTHING;
+SELECT setval('tenant_id_seq', GREATEST(1, (SELECT MAX(id) FROM tenant)));
+
+ALTER TABLE data_category ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenant(id);
+ALTER TABLE data_category ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE data_category ADD CONSTRAINT data_category_tenant_id_name_key UNIQUE (tenant_id, name);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS data_category_slug_history;
+ALTER TABLE data_category DROP CONSTRAINT IF EXISTS data_category_tenant_id_slug_key;
+ALTER TABLE data_category DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,12 @@
+ALTER TABLE data_category ADD COLUMN slug VARCHAR(200);
+UPDATE data_category
+SET slug = COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(left(name, 180)), '[^a-z0-9]+', '-', 'g')), '') || '-' || id, 'category-' || id);
+ALTER TABLE data_category ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE data_category ADD CONSTRAINT data_category_tenant_id_slug_key UNIQUE (tenant_id, slug);
+
+CREATE TABLE IF NOT EXISTS data_category_slug_history (
+    tenant_id INTEGER NOT NULL REFERENCES tenant(id),
+    slug VARCHAR(200) NOT NULL,
+    category_id INTEGER NOT NULL REFERENCES data_category(id) ON DELETE CASCADE,
+    PRIMARY KEY (tenant_id, slug)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS data_category_tenant_id_updated_at_idx;
+ALTER TABLE data_category DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE data_category DROP COLUMN IF EXISTS created_by;
+ALTER TABLE data_category DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE data_category DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,6 @@
+ALTER TABLE data_category ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE data_category ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE data_category ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT 'system';
+ALTER TABLE data_category ADD COLUMN updated_by VARCHAR(255) NOT NULL DEFAULT 'system';
+
+CREATE INDEX IF NOT EXISTS data_category_tenant_id_updated_at_idx ON data_category (tenant_id, updated_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS category_change;
+DROP TABLE IF EXISTS category_change_sequence;
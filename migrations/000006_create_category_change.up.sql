@@ -0,0 +1,17 @@
+-- One row per tenant holding the last allocated change sequence. Writers lock
+-- it for the rest of their transaction, so sequences commit in order.
+CREATE TABLE IF NOT EXISTS category_change_sequence (
+    tenant_id INTEGER PRIMARY KEY REFERENCES tenant(id),
+    last_seq BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS category_change (
+    tenant_id INTEGER NOT NULL REFERENCES tenant(id),
+    seq BIGINT NOT NULL,
+    category_id INTEGER NOT NULL,
+    operation VARCHAR(16) NOT NULL,
+    category JSONB NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL,
+    changed_by VARCHAR(255) NOT NULL,
+    PRIMARY KEY (tenant_id, seq)
+);
@@ -0,0 +1 @@
+ALTER TABLE idempotency_key ADD COLUMN content_type TEXT NOT NULL DEFAULT 'application/json';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS data_category_name_trgm_idx;
+DROP INDEX IF EXISTS data_category_search_vector_idx;
+ALTER TABLE data_category DROP COLUMN search_vector;
+
+DROP INDEX IF EXISTS data_category_parent_id_idx;
+ALTER TABLE data_category DROP COLUMN parent_id;
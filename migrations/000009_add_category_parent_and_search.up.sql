@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE data_category ADD COLUMN parent_id INTEGER REFERENCES data_category(id);
+CREATE INDEX IF NOT EXISTS data_category_parent_id_idx ON data_category (parent_id);
+
+ALTER TABLE data_category ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name || ' ' || replace(slug, '-', ' '))) STORED;
+CREATE INDEX IF NOT EXISTS data_category_search_vector_idx ON data_category USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS data_category_name_trgm_idx ON data_category USING GIN (name gin_trgm_ops);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS category_alias (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenant(id),
+    category_id INTEGER NOT NULL REFERENCES data_category(id) ON DELETE CASCADE,
+    alias VARCHAR(200) NOT NULL,
+    slug VARCHAR(200) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    created_by VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS category_alias_tenant_id_alias_key ON category_alias (tenant_id, lower(alias));
+CREATE INDEX IF NOT EXISTS category_alias_tenant_id_slug_idx ON category_alias (tenant_id, slug);
+CREATE INDEX IF NOT EXISTS category_alias_category_id_idx ON category_alias (category_id);
+CREATE INDEX IF NOT EXISTS category_alias_alias_trgm_idx ON category_alias USING GIN (alias gin_trgm_ops);
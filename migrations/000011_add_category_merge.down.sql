@@ -0,0 +1,8 @@
+ALTER TABLE category_change DROP COLUMN IF EXISTS merged_into_id;
+
+DELETE FROM data_category WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS data_category_tenant_id_name_key;
+ALTER TABLE data_category ADD CONSTRAINT data_category_tenant_id_name_key UNIQUE (tenant_id, name);
+DROP INDEX IF EXISTS data_category_tenant_id_slug_key;
+ALTER TABLE data_category ADD CONSTRAINT data_category_tenant_id_slug_key UNIQUE (tenant_id, slug);
+ALTER TABLE data_category DROP COLUMN IF EXISTS deleted_at;
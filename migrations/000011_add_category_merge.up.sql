@@ -0,0 +1,9 @@
+-- Merged categories are kept as soft-deleted rows, so only live categories
+-- need unique names and slugs.
+ALTER TABLE data_category ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE data_category DROP CONSTRAINT IF EXISTS data_category_tenant_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS data_category_tenant_id_name_key ON data_category (tenant_id, name) WHERE deleted_at IS NULL;
+ALTER TABLE data_category DROP CONSTRAINT IF EXISTS data_category_tenant_id_slug_key;
+CREATE UNIQUE INDEX IF NOT EXISTS data_category_tenant_id_slug_key ON data_category (tenant_id, slug) WHERE deleted_at IS NULL;
+
+ALTER TABLE category_change ADD COLUMN merged_into_id INTEGER;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS data_category_sibling_position_key;
+ALTER TABLE data_category DROP COLUMN IF EXISTS position;
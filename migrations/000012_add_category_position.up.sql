@@ -0,0 +1,13 @@
+ALTER TABLE data_category ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+UPDATE data_category c SET position = s.rn - 1
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY tenant_id, parent_id ORDER BY id) AS rn
+    FROM data_category WHERE deleted_at IS NULL
+) s
+WHERE c.id = s.id;
+
+-- Siblings never share a position. Renumbering moves positions through
+-- negative values, so the index holds after every statement.
+CREATE UNIQUE INDEX IF NOT EXISTS data_category_sibling_position_key
+    ON data_category (tenant_id, COALESCE(parent_id, 0), position) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE data_category DROP COLUMN IF EXISTS attribute_schema;
+ALTER TABLE data_category DROP COLUMN IF EXISTS attributes;
@@ -0,0 +1,5 @@
+ALTER TABLE data_category ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+-- The JSON Schema the attributes of the category and of its descendants must
+-- match, NULL when the category adds no constraints.
+ALTER TABLE data_category ADD COLUMN attribute_schema JSONB;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS category_translation (
+    tenant_id INTEGER NOT NULL REFERENCES tenant(id),
+    category_id INTEGER NOT NULL REFERENCES data_category(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    updated_by VARCHAR(255) NOT NULL,
+    PRIMARY KEY (category_id, locale)
+);
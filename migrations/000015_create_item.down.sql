@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS item_category;
+DROP TABLE IF EXISTS item;
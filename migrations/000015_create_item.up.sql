@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS item (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenant(id),
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    updated_by VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS item_tenant_id_idx ON item (tenant_id);
+
+-- Assignments of items to categories. Deleting either side deletes the
+-- assignment; merged categories hand theirs to the merge target.
+CREATE TABLE IF NOT EXISTS item_category (
+    item_id INTEGER NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES data_category(id) ON DELETE CASCADE,
+    PRIMARY KEY (item_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS item_category_category_id_idx ON item_category (category_id);
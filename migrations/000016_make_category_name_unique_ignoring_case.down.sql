@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS data_category_tenant_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS data_category_tenant_id_name_key ON data_category (tenant_id, name) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Names are unique ignoring case, like aliases.
+DROP INDEX IF EXISTS data_category_tenant_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS data_category_tenant_id_name_key ON data_category (tenant_id, lower(name)) WHERE deleted_at IS NULL;
@@ -1,6 +1,69 @@
 package domain
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Category struct {
 	Id   int
 	Name string
+	// Locale is the locale Name was translated to, empty for the name the
+	// category was created with.
+	Locale   string
+	Slug     string
+	ParentId *int
+	Position int
+	// Attributes is free-form metadata like an icon or a tax class. It must
+	// match the AttributeSchema of the category and of all its ancestors.
+	Attributes      map[string]interface{}
+	AttributeSchema json.RawMessage
+	// ItemCount is the number of items assigned to the category itself.
+	ItemCount int
+	// Aliases are the other names the category is found by.
+	Aliases   []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy string
+	UpdatedBy string
+}
+
+// CategoryFilter narrows down FindAll. Zero fields do not filter.
+type CategoryFilter struct {
+	UpdatedSince time.Time
+	// Attributes keeps the categories whose attributes have these values,
+	// compared as text.
+	Attributes map[string]string
+	// When Limit is positive, at most Limit categories with ids above
+	// AfterId are returned ordered by id, instead of all of them depth first.
+	AfterId int
+	Limit   int
+}
+
+// CategoryDeleteFilter selects the categories of a bulk delete, which also
+// deletes their descendants. Zero fields do not filter.
+type CategoryDeleteFilter struct {
+	SubtreeId int
+	// NamePattern matches names case-insensitively, with * matching any
+	// run of characters.
+	NamePattern string
+}
+
+// CategorySearch is a ranked search over the categories of TenantId,
+// optionally limited to the subtree below SubtreeId.
+type CategorySearch struct {
+	TenantId  int
+	Query     string
+	SubtreeId int
+	Limit     int
+}
+
+// CategorySearchResult is a match of a search. Highlight is the HTML-escaped
+// name with the matched words wrapped in <mark> tags. Alias is set when the category
+// matched by one of its aliases.
+type CategorySearchResult struct {
+	Category  Category
+	Score     float64
+	Highlight string
+	Alias     string
 }
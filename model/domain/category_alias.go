@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// CategoryAlias is another name a category is found by. Slug resolves to the
+// category like an old slug does; it is the alias slugified, or the slug of a
+// category that was merged into this one.
+type CategoryAlias struct {
+	Id         int
+	CategoryId int
+	Alias      string
+	Slug       string
+	CreatedAt  time.Time
+	CreatedBy  string
+}
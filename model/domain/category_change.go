@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	CategoryCreated = "created"
+	CategoryUpdated = "updated"
+	CategoryDeleted = "deleted"
+	CategoryMerged  = "merged"
+)
+
+// CategoryChange is one entry of a tenant's change feed. Category holds the
+// state after the change, or the last state for deletes and merges.
+// MergedIntoId is the category a merged category was merged into.
+type CategoryChange struct {
+	TenantId     int
+	Sequence     int64
+	Operation    string
+	Category     Category
+	MergedIntoId int
+	ChangedAt    time.Time
+	ChangedBy    string
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// CategoryTranslation is the name of a category in a locale, a BCP 47
+// language tag like id or en-GB.
+type CategoryTranslation struct {
+	CategoryId int
+	Locale     string
+	Name       string
+	UpdatedAt  time.Time
+	UpdatedBy  string
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+	ContentType string
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the response of the original request has been stored.
+func (ir IdempotencyRecord) Completed() bool {
+	return ir.StatusCode != 0
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Item is a thing being categorized. CategoryIds are the categories it is
+// assigned to, in ascending order.
+type Item struct {
+	Id          int
+	Name        string
+	CategoryIds []int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CreatedBy   string
+	UpdatedBy   string
+}
@@ -0,0 +1,10 @@
+package domain
+
+// Principal is the authenticated caller of a request. Every category
+// operation is scoped to the principal's tenant, and Subject is recorded as
+// the actor of the changes it makes.
+type Principal struct {
+	TenantId int
+	Subject  string
+	Admin    bool
+}
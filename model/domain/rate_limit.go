@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// RateLimitBudget allows Limit requests per Window, refilled continuously.
+type RateLimitBudget struct {
+	Limit  int
+	Window time.Duration
+}
+
+type RateLimitStatus struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
@@ -0,0 +1,10 @@
+package domain
+
+const DefaultTenantId = 1
+
+type Tenant struct {
+	Id         int
+	Name       string
+	ApiKeyHash string
+	Disabled   bool
+}
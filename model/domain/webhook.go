@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// Webhook event types, one per category change operation.
+const (
+	WebhookCategoryCreated = "category.created"
+	WebhookCategoryUpdated = "category.updated"
+	WebhookCategoryDeleted = "category.deleted"
+	WebhookCategoryMerged  = "category.merged"
+)
+
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+	WebhookDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	Id         int
+	Url        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// WebhookOutbox is an event waiting to be delivered to one subscription. Url
+// and Secret are those of the subscription at the time the event is claimed.
+type WebhookOutbox struct {
+	Id             int64
+	TenantId       int
+	SubscriptionId int
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	CreatedAt      time.Time
+	Url            string
+	Secret         string
+}
+
+// WebhookDelivery is the log entry of one delivery attempt. Outcome is
+// WebhookDelivered, WebhookFailed when the attempt will be retried, or
+// WebhookDead when the event was given up on.
+type WebhookDelivery struct {
+	Id             int64
+	OutboxId       int64
+	SubscriptionId int
+	EventType      string
+	Attempt        int
+	StatusCode     int
+	Error          string
+	Duration       time.Duration
+	Outcome        string
+	AttemptedAt    time.Time
+}
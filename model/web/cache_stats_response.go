@@ -0,0 +1,8 @@
+package web
+
+type CacheStatsResponse struct {
+	Enabled bool   `json:"enabled"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Size    int    `json:"size"`
+}
@@ -0,0 +1,6 @@
+package web
+
+type CategoryAliasCreateRequest struct {
+	CategoryId int    `validate:"required"`
+	Alias      string `validate:"required,max=200,min=1" json:"alias"`
+}
@@ -0,0 +1,12 @@
+package web
+
+import "time"
+
+type CategoryAliasResponse struct {
+	Id         int       `json:"id"`
+	CategoryId int       `json:"category_id"`
+	Alias      string    `json:"alias"`
+	Slug       string    `json:"slug"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by"`
+}
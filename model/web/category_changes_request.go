@@ -0,0 +1,6 @@
+package web
+
+type CategoryChangesRequest struct {
+	Cursor int64 `validate:"min=0"`
+	Limit  int   `validate:"min=1,max=1000"`
+}
@@ -0,0 +1,19 @@
+package web
+
+import "time"
+
+type CategoryChangeResponse struct {
+	Sequence     int64             `json:"sequence"`
+	Operation    string            `json:"operation"`
+	CategoryId   int               `json:"category_id"`
+	Category     *CategoryResponse `json:"category,omitempty"`
+	MergedIntoId int               `json:"merged_into_id,omitempty"`
+	ChangedAt    time.Time         `json:"changed_at"`
+	ChangedBy    string            `json:"changed_by"`
+}
+
+type CategoryChangesResponse struct {
+	Changes    []CategoryChangeResponse `json:"changes"`
+	NextCursor int64                    `json:"next_cursor"`
+	HasMore    bool                     `json:"has_more"`
+}
@@ -1,5 +1,15 @@
 package web
 
+import "encoding/json"
+
 type CategoryCreateRequest struct {
-	Name string `validate:"required,max=200,min=1" json:"name"`
+	Name            string                 `validate:"required,max=200,min=1" json:"name"`
+	Slug            string                 `validate:"omitempty,max=200,slug" json:"slug"`
+	ParentId        *int                   `validate:"omitempty,min=1" json:"parent_id"`
+	Attributes      map[string]interface{} `json:"attributes"`
+	AttributeSchema json.RawMessage        `json:"attribute_schema"`
+	// Translations are the names in other locales, by locale.
+	Translations map[string]string `validate:"omitempty,dive,keys,max=35,locale,endkeys,required,max=200,min=1" json:"translations"`
+	// Aliases are the other names the category is found by.
+	Aliases []string `validate:"omitempty,dive,required,max=200,min=1" json:"aliases"`
 }
@@ -0,0 +1,12 @@
+package web
+
+// CategoryDeleteAllRequest deletes nothing when DryRun is set; it counts the
+// categories the filter selects and issues the ConfirmationToken that the
+// actual delete has to present.
+type CategoryDeleteAllRequest struct {
+	TenantId          int    `validate:"min=0"`
+	SubtreeId         int    `validate:"min=0"`
+	Name              string `validate:"max=200"`
+	DryRun            bool
+	ConfirmationToken string `validate:"max=1000"`
+}
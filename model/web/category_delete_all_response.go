@@ -0,0 +1,10 @@
+package web
+
+import "time"
+
+type CategoryDeleteAllResponse struct {
+	DryRun            bool       `json:"dry_run"`
+	Count             int        `json:"count"`
+	ConfirmationToken string     `json:"confirmation_token,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+}
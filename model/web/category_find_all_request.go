@@ -0,0 +1,12 @@
+package web
+
+import "time"
+
+type CategoryFindAllRequest struct {
+	UpdatedSince time.Time
+	Attributes   map[string]string
+	// AfterId and Limit page through the categories by id, see
+	// domain.CategoryFilter.
+	AfterId int
+	Limit   int
+}
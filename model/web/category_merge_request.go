@@ -0,0 +1,6 @@
+package web
+
+type CategoryMergeRequest struct {
+	TargetId int `validate:"required"`
+	SourceId int `validate:"required,min=1" json:"source_id"`
+}
@@ -0,0 +1,7 @@
+package web
+
+type CategoryMoveRequest struct {
+	Id       int  `validate:"required"`
+	ParentId *int `validate:"omitempty,min=1" json:"parent_id"`
+	Position int  `validate:"min=0" json:"position"`
+}
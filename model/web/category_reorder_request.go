@@ -0,0 +1,6 @@
+package web
+
+type CategoryReorderRequest struct {
+	ParentId    *int  `validate:"omitempty,min=1"`
+	CategoryIds []int `validate:"required,min=1,dive,min=1" json:"category_ids"`
+}
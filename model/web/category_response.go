@@ -1,6 +1,23 @@
 package web
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type CategoryResponse struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
+	Id              int                    `json:"id"`
+	Name            string                 `json:"name"`
+	Locale          string                 `json:"locale,omitempty"`
+	Slug            string                 `json:"slug"`
+	ParentId        *int                   `json:"parent_id"`
+	Position        int                    `json:"position"`
+	Attributes      map[string]interface{} `json:"attributes"`
+	AttributeSchema json.RawMessage        `json:"attribute_schema,omitempty"`
+	ItemCount       int                    `json:"item_count"`
+	Aliases         []string               `json:"aliases"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	CreatedBy       string                 `json:"created_by"`
+	UpdatedBy       string                 `json:"updated_by"`
 }
@@ -0,0 +1,8 @@
+package web
+
+type CategorySearchRequest struct {
+	Query     string `validate:"required,max=200"`
+	Limit     int    `validate:"min=1,max=100"`
+	TenantId  int    `validate:"min=0"`
+	SubtreeId int    `validate:"min=0"`
+}
@@ -0,0 +1,8 @@
+package web
+
+type CategorySearchResponse struct {
+	Category  CategoryResponse `json:"category"`
+	Score     float64          `json:"score"`
+	Highlight string           `json:"highlight"`
+	Alias     string           `json:"alias,omitempty"`
+}
@@ -0,0 +1,14 @@
+package web
+
+// CategorySlugResponse is the result of looking a category up by slug. Moved
+// is set when the slug is an old one and Category.Slug is the current one.
+type CategorySlugResponse struct {
+	Category CategoryResponse
+	Moved    bool
+}
+
+type CategoryRedirectResponse struct {
+	Slug     string           `json:"slug"`
+	Location string           `json:"location"`
+	Category CategoryResponse `json:"category"`
+}
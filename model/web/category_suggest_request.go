@@ -0,0 +1,6 @@
+package web
+
+type CategorySuggestRequest struct {
+	Prefix string `validate:"required,max=200"`
+	Limit  int    `validate:"min=1,max=50"`
+}
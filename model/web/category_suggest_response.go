@@ -0,0 +1,7 @@
+package web
+
+type CategorySuggestResponse struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
@@ -0,0 +1,7 @@
+package web
+
+type CategoryTranslationRequest struct {
+	CategoryId int    `validate:"required"`
+	Locale     string `validate:"required,max=35,locale"`
+	Name       string `validate:"required,max=200,min=1" json:"name"`
+}
@@ -0,0 +1,11 @@
+package web
+
+import "time"
+
+type CategoryTranslationResponse struct {
+	CategoryId int       `json:"category_id"`
+	Locale     string    `json:"locale"`
+	Name       string    `json:"name"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UpdatedBy  string    `json:"updated_by"`
+}
@@ -1,6 +1,13 @@
 package web
 
+import "encoding/json"
+
 type CategoryUpdateRequest struct {
 	Id   int    `validate:"required"`
 	Name string `validate:"required,max=200,min=1" json:"name"`
+	Slug string `validate:"omitempty,max=200,slug" json:"slug"`
+	// Attributes and AttributeSchema are kept when left out. A null
+	// AttributeSchema removes the schema.
+	Attributes      map[string]interface{} `json:"attributes"`
+	AttributeSchema json.RawMessage        `json:"attribute_schema"`
 }
@@ -0,0 +1,6 @@
+package web
+
+type ItemCreateRequest struct {
+	Name        string `validate:"required,max=200,min=1" json:"name"`
+	CategoryIds []int  `validate:"omitempty,max=100,dive,min=1" json:"category_ids"`
+}
@@ -0,0 +1,8 @@
+package web
+
+// ItemFindByCategoryRequest lists the items of a category, and of its
+// descendants when IncludeDescendants is set.
+type ItemFindByCategoryRequest struct {
+	CategoryId         int `validate:"required"`
+	IncludeDescendants bool
+}
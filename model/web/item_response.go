@@ -0,0 +1,13 @@
+package web
+
+import "time"
+
+type ItemResponse struct {
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	CategoryIds []int     `json:"category_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
+}
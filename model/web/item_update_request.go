@@ -0,0 +1,9 @@
+package web
+
+// ItemUpdateRequest replaces the name and the category assignments of an
+// item, so category_ids left out unassigns it from every category.
+type ItemUpdateRequest struct {
+	Id          int    `validate:"required"`
+	Name        string `validate:"required,max=200,min=1" json:"name"`
+	CategoryIds []int  `validate:"omitempty,max=100,dive,min=1" json:"category_ids"`
+}
@@ -0,0 +1,5 @@
+package web
+
+type TenantCreateRequest struct {
+	Name string `validate:"required,max=200,min=1" json:"name"`
+}
@@ -0,0 +1,8 @@
+package web
+
+type TenantResponse struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Disabled bool   `json:"disabled"`
+	ApiKey   string `json:"api_key,omitempty"`
+}
@@ -0,0 +1,7 @@
+package web
+
+type WebhookCreateRequest struct {
+	Url        string   `validate:"required,max=2000,url" json:"url"`
+	EventTypes []string `validate:"required,min=1,dive,oneof=category.created category.updated category.deleted category.merged" json:"event_types"`
+	Secret     string   `validate:"required,min=16,max=255" json:"secret"`
+}
@@ -0,0 +1,15 @@
+package web
+
+import "time"
+
+type WebhookDeliveryResponse struct {
+	Id          int64     `json:"id"`
+	OutboxId    int64     `json:"outbox_id"`
+	EventType   string    `json:"event_type"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code"`
+	Error       string    `json:"error"`
+	DurationMs  int64     `json:"duration_ms"`
+	Outcome     string    `json:"outcome"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,14 @@
+package web
+
+import "time"
+
+// WebhookEventPayload is the body POSTed to webhook subscribers. Deliveries
+// are at least once and may arrive out of order; Sequence orders the events
+// of a tenant.
+type WebhookEventPayload struct {
+	EventType  string                 `json:"event_type"`
+	TenantId   int                    `json:"tenant_id"`
+	Sequence   int64                  `json:"sequence"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       CategoryChangeResponse `json:"data"`
+}
@@ -0,0 +1,11 @@
+package web
+
+import "time"
+
+// WebhookResponse never includes the secret of the subscription.
+type WebhookResponse struct {
+	Id         int       `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,4 @@
+// Package pb holds the code generated from proto/category.proto.
+package pb
+
+//go:generate protoc -I ../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative category.proto
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CategoryAliasRepository stores the aliases of the categories of the tenant
+// in ctx. Aliases are compared ignoring case; deleting a category deletes its
+// aliases.
+type CategoryAliasRepository interface {
+	FindAllByCategory(ctx context.Context, tx *sql.Tx, categoryId int) []domain.CategoryAlias
+	// FindAllByCategories returns the aliases of all of categoryIds.
+	FindAllByCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) []domain.CategoryAlias
+	FindById(ctx context.Context, tx *sql.Tx, aliasId int) (domain.CategoryAlias, error)
+	FindByAlias(ctx context.Context, tx *sql.Tx, alias string) (domain.CategoryAlias, error)
+	FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (domain.CategoryAlias, error)
+	// NameTaken reports whether name is the name of a category, ignoring
+	// case, so an alias never shadows a name.
+	NameTaken(ctx context.Context, tx *sql.Tx, name string) bool
+	Save(ctx context.Context, tx *sql.Tx, alias domain.CategoryAlias) domain.CategoryAlias
+	DeleteById(ctx context.Context, tx *sql.Tx, alias domain.CategoryAlias)
+	// MoveAll hands the aliases of sourceId over to targetId.
+	MoveAll(ctx context.Context, tx *sql.Tx, sourceId int, targetId int)
+}
+
+const categoryAliasColumns = "id, category_id, alias, slug, created_at, created_by"
+
+type CategoryAliasRepositoryImpl struct {
+	Clock helper.Clock
+}
+
+func NewCategoryAliasRepository(clock helper.Clock) *CategoryAliasRepositoryImpl {
+	return &CategoryAliasRepositoryImpl{
+		Clock: clock,
+	}
+}
+
+func (ar *CategoryAliasRepositoryImpl) FindAllByCategory(ctx context.Context, tx *sql.Tx, categoryId int) []domain.CategoryAlias {
+	querySQL := "SELECT " + categoryAliasColumns + " FROM category_alias WHERE category_id = $1 AND tenant_id = $2 ORDER BY id"
+	rows, err := tx.QueryContext(ctx, querySQL, categoryId, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var aliases []domain.CategoryAlias
+	for rows.Next() {
+		aliases = append(aliases, scanCategoryAlias(rows))
+	}
+	return aliases
+}
+
+func (ar *CategoryAliasRepositoryImpl) FindAllByCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) []domain.CategoryAlias {
+	querySQL := "SELECT " + categoryAliasColumns + " FROM category_alias WHERE category_id = ANY($1) AND tenant_id = $2 ORDER BY category_id, id"
+	rows, err := tx.QueryContext(ctx, querySQL, pq.Array(categoryIds), helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var aliases []domain.CategoryAlias
+	for rows.Next() {
+		aliases = append(aliases, scanCategoryAlias(rows))
+	}
+	return aliases
+}
+
+func (ar *CategoryAliasRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, aliasId int) (domain.CategoryAlias, error) {
+	querySQL := "SELECT " + categoryAliasColumns + " FROM category_alias WHERE id = $1 AND tenant_id = $2"
+	return findCategoryAlias(ctx, tx, querySQL, aliasId, helper.TenantIdFromContext(ctx))
+}
+
+func (ar *CategoryAliasRepositoryImpl) FindByAlias(ctx context.Context, tx *sql.Tx, alias string) (domain.CategoryAlias, error) {
+	querySQL := "SELECT " + categoryAliasColumns + " FROM category_alias WHERE lower(alias) = lower($1) AND tenant_id = $2"
+	return findCategoryAlias(ctx, tx, querySQL, alias, helper.TenantIdFromContext(ctx))
+}
+
+func (ar *CategoryAliasRepositoryImpl) FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (domain.CategoryAlias, error) {
+	querySQL := "SELECT " + categoryAliasColumns + " FROM category_alias WHERE slug = $1 AND tenant_id = $2 ORDER BY id LIMIT 1"
+	return findCategoryAlias(ctx, tx, querySQL, slug, helper.TenantIdFromContext(ctx))
+}
+
+func (ar *CategoryAliasRepositoryImpl) NameTaken(ctx context.Context, tx *sql.Tx, name string) bool {
+	querySQL := "SELECT EXISTS (SELECT 1 FROM data_category WHERE lower(name) = lower($1) AND tenant_id = $2 AND deleted_at IS NULL)"
+	var exists bool
+	err := tx.QueryRowContext(ctx, querySQL, name, helper.TenantIdFromContext(ctx)).Scan(&exists)
+	helper.PanicIfError(err)
+	return exists
+}
+
+func (ar *CategoryAliasRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, alias domain.CategoryAlias) domain.CategoryAlias {
+	principal := helper.MustPrincipalFromContext(ctx)
+	alias.CreatedAt = ar.Clock().UTC().Truncate(time.Microsecond)
+	alias.CreatedBy = principal.Subject
+
+	querySQL := `INSERT INTO category_alias(tenant_id, category_id, alias, slug, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err := tx.QueryRowContext(ctx, querySQL, principal.TenantId, alias.CategoryId, alias.Alias, alias.Slug,
+		alias.CreatedAt, alias.CreatedBy).Scan(&alias.Id)
+	helper.PanicIfError(err)
+	return alias
+}
+
+func (ar *CategoryAliasRepositoryImpl) DeleteById(ctx context.Context, tx *sql.Tx, alias domain.CategoryAlias) {
+	querySQL := "DELETE FROM category_alias WHERE id = $1 AND tenant_id = $2"
+	_, err := tx.ExecContext(ctx, querySQL, alias.Id, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+}
+
+func (ar *CategoryAliasRepositoryImpl) MoveAll(ctx context.Context, tx *sql.Tx, sourceId int, targetId int) {
+	querySQL := "UPDATE category_alias SET category_id = $1 WHERE category_id = $2 AND tenant_id = $3"
+	_, err := tx.ExecContext(ctx, querySQL, targetId, sourceId, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+}
+
+func findCategoryAlias(ctx context.Context, tx *sql.Tx, querySQL string, args ...interface{}) (domain.CategoryAlias, error) {
+	rows, err := tx.QueryContext(ctx, querySQL, args...)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanCategoryAlias(rows), nil
+	} else {
+		return domain.CategoryAlias{}, errors.New("category alias is not found")
+	}
+}
+
+func scanCategoryAlias(rows *sql.Rows) domain.CategoryAlias {
+	alias := domain.CategoryAlias{}
+	err := rows.Scan(&alias.Id, &alias.CategoryId, &alias.Alias, &alias.Slug, &alias.CreatedAt, &alias.CreatedBy)
+	helper.PanicIfError(err)
+	return alias
+}
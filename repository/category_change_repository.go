@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CategoryChangeRepository stores the change feed of each tenant. Writers call
+// Lock before changing any category; the lock is held until the transaction
+// ends, so changes of a tenant commit in sequence order and a reader can never
+// see a sequence number before all smaller ones are visible.
+type CategoryChangeRepository interface {
+	Lock(ctx context.Context, tx *sql.Tx)
+	Save(ctx context.Context, tx *sql.Tx, operation string, category domain.Category) domain.CategoryChange
+	// SaveMerge records that source was merged into the category targetId.
+	SaveMerge(ctx context.Context, tx *sql.Tx, source domain.Category, targetId int) domain.CategoryChange
+	FindAllAfter(ctx context.Context, tx *sql.Tx, cursor int64, limit int) []domain.CategoryChange
+	FindLatest(ctx context.Context, tx *sql.Tx) (domain.CategoryChange, error)
+}
+
+type CategoryChangeRepositoryImpl struct {
+	Clock helper.Clock
+}
+
+func NewCategoryChangeRepository(clock helper.Clock) *CategoryChangeRepositoryImpl {
+	return &CategoryChangeRepositoryImpl{
+		Clock: clock,
+	}
+}
+
+func (cr *CategoryChangeRepositoryImpl) Lock(ctx context.Context, tx *sql.Tx) {
+	cr.allocate(ctx, tx, 0)
+}
+
+func (cr *CategoryChangeRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, operation string, category domain.Category) domain.CategoryChange {
+	return cr.save(ctx, tx, domain.CategoryChange{Operation: operation, Category: category})
+}
+
+func (cr *CategoryChangeRepositoryImpl) SaveMerge(ctx context.Context, tx *sql.Tx, source domain.Category, targetId int) domain.CategoryChange {
+	return cr.save(ctx, tx, domain.CategoryChange{Operation: domain.CategoryMerged, Category: source, MergedIntoId: targetId})
+}
+
+func (cr *CategoryChangeRepositoryImpl) save(ctx context.Context, tx *sql.Tx, change domain.CategoryChange) domain.CategoryChange {
+	principal := helper.MustPrincipalFromContext(ctx)
+	change.TenantId = principal.TenantId
+	change.Sequence = cr.allocate(ctx, tx, 1)
+	change.ChangedAt = cr.Clock().UTC().Truncate(time.Microsecond)
+	change.ChangedBy = principal.Subject
+
+	data, err := json.Marshal(change.Category)
+	helper.PanicIfError(err)
+
+	var mergedIntoId sql.NullInt64
+	if change.MergedIntoId != 0 {
+		mergedIntoId = sql.NullInt64{Int64: int64(change.MergedIntoId), Valid: true}
+	}
+
+	querySQL := `INSERT INTO category_change(tenant_id, seq, category_id, operation, category, merged_into_id, changed_at, changed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = tx.ExecContext(ctx, querySQL, principal.TenantId, change.Sequence, change.Category.Id, change.Operation, data,
+		mergedIntoId, change.ChangedAt, change.ChangedBy)
+	helper.PanicIfError(err)
+	return change
+}
+
+func (cr *CategoryChangeRepositoryImpl) FindAllAfter(ctx context.Context, tx *sql.Tx, cursor int64, limit int) []domain.CategoryChange {
+	querySQL := `SELECT seq, operation, category, COALESCE(merged_into_id, 0), changed_at, changed_by FROM category_change
+		WHERE tenant_id = $1 AND seq > $2 ORDER BY seq LIMIT $3`
+	tenantId := helper.TenantIdFromContext(ctx)
+	rows, err := tx.QueryContext(ctx, querySQL, tenantId, cursor, limit)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var changes []domain.CategoryChange
+	for rows.Next() {
+		change := domain.CategoryChange{TenantId: tenantId}
+		var data []byte
+		err := rows.Scan(&change.Sequence, &change.Operation, &data, &change.MergedIntoId, &change.ChangedAt, &change.ChangedBy)
+		helper.PanicIfError(err)
+		err = json.Unmarshal(data, &change.Category)
+		helper.PanicIfError(err)
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func (cr *CategoryChangeRepositoryImpl) FindLatest(ctx context.Context, tx *sql.Tx) (domain.CategoryChange, error) {
+	querySQL := `SELECT seq, operation, category, COALESCE(merged_into_id, 0), changed_at, changed_by FROM category_change
+		WHERE tenant_id = $1 ORDER BY seq DESC LIMIT 1`
+	tenantId := helper.TenantIdFromContext(ctx)
+	rows, err := tx.QueryContext(ctx, querySQL, tenantId)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	change := domain.CategoryChange{TenantId: tenantId}
+	if rows.Next() {
+		var data []byte
+		err := rows.Scan(&change.Sequence, &change.Operation, &data, &change.MergedIntoId, &change.ChangedAt, &change.ChangedBy)
+		helper.PanicIfError(err)
+		err = json.Unmarshal(data, &change.Category)
+		helper.PanicIfError(err)
+		return change, nil
+	} else {
+		return change, errors.New("category change is not found")
+	}
+}
+
+// allocate reserves n sequence numbers for the tenant in ctx and returns the
+// last one. Updating the counter row locks it until the transaction ends.
+func (cr *CategoryChangeRepositoryImpl) allocate(ctx context.Context, tx *sql.Tx, n int64) int64 {
+	querySQL := `INSERT INTO category_change_sequence(tenant_id, last_seq) VALUES ($1, $2)
+		ON CONFLICT (tenant_id) DO UPDATE SET last_seq = category_change_sequence.last_seq + EXCLUDED.last_seq
+		RETURNING last_seq`
+	var lastSeq int64
+	err := tx.QueryRowContext(ctx, querySQL, helper.TenantIdFromContext(ctx), n).Scan(&lastSeq)
+	helper.PanicIfError(err)
+	return lastSeq
+}
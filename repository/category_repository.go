@@ -5,88 +5,514 @@ import (
 	"Data-Category/model/domain"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type CategoryRepository interface {
-	FindAll(ctx context.Context, tx *sql.Tx) []domain.Category
+	// FindAll returns the categories depth first, siblings by position.
+	FindAll(ctx context.Context, tx *sql.Tx, filter domain.CategoryFilter) []domain.Category
 	Save(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category
-	DeleteAll(ctx context.Context, tx *sql.Tx)
+	// DeleteAll deletes the categories filter selects and their descendants,
+	// and returns them by id.
+	DeleteAll(ctx context.Context, tx *sql.Tx, filter domain.CategoryDeleteFilter) []domain.Category
+	// CountAll returns how many categories DeleteAll would delete.
+	CountAll(ctx context.Context, tx *sql.Tx, filter domain.CategoryDeleteFilter) int
 	FindById(ctx context.Context, tx *sql.Tx, categoryId int) (domain.Category, error)
+	FindAllByIds(ctx context.Context, tx *sql.Tx, categoryIds []int) []domain.Category
+	// FindByName finds the category named name, ignoring case.
+	FindByName(ctx context.Context, tx *sql.Tx, name string) (domain.Category, error)
+	FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (domain.Category, error)
+	// FindBySlugHistory finds the category that used slug before it was renamed.
+	FindBySlugHistory(ctx context.Context, tx *sql.Tx, slug string) (domain.Category, error)
+	SaveSlugHistory(ctx context.Context, tx *sql.Tx, category domain.Category, slug string)
+	DeleteSlugHistory(ctx context.Context, tx *sql.Tx, slug string)
 	UpdateById(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category
 	DeleteById(ctx context.Context, tx *sql.Tx, category domain.Category)
+	HasChildren(ctx context.Context, tx *sql.Tx, category domain.Category) bool
+	// FindChildren returns the children of parentId by position, or the
+	// root categories when parentId is nil.
+	FindChildren(ctx context.Context, tx *sql.Tx, parentId *int) []domain.Category
+	// FindAllByParentIds returns the children of all of parentIds, grouped by
+	// parent and by position within a group.
+	FindAllByParentIds(ctx context.Context, tx *sql.Tx, parentIds []int) []domain.Category
+	// FindSubtree returns rootId and its descendants depth first.
+	FindSubtree(ctx context.Context, tx *sql.Tx, rootId int) []domain.Category
+	// Renumber makes categoryIds the children of parentId at positions in
+	// list order and returns the categories whose parent or position changed.
+	// categoryIds must be the whole group of siblings.
+	Renumber(ctx context.Context, tx *sql.Tx, parentId *int, categoryIds []int) []domain.Category
+	// InSubtree reports whether categoryId is rootId or one of its descendants.
+	InSubtree(ctx context.Context, tx *sql.Tx, rootId int, categoryId int) bool
+	// MoveChildren makes the children of source children of target and
+	// returns them as moved.
+	MoveChildren(ctx context.Context, tx *sql.Tx, source domain.Category, target domain.Category) []domain.Category
+	// MoveSlugHistory makes the old slugs of source resolve to target.
+	MoveSlugHistory(ctx context.Context, tx *sql.Tx, source domain.Category, target domain.Category)
+	// SoftDeleteById hides category from every other method while keeping
+	// its row, and detaches it from its parent.
+	SoftDeleteById(ctx context.Context, tx *sql.Tx, category domain.Category)
+	CategorySearcher
+}
+
+// CategorySearcher ranks categories by how well their name matches a query.
+// Unlike the other methods it filters by search.TenantId rather than the
+// tenant in ctx, so the admin can search any tenant.
+type CategorySearcher interface {
+	Search(ctx context.Context, tx *sql.Tx, search domain.CategorySearch) []domain.CategorySearchResult
 }
 
+const categoryColumns = "id, name, slug, parent_id, position, attributes, attribute_schema, created_at, updated_at, created_by, updated_by"
+
+// categoryTree numbers the live categories of tenant $1 with the path of
+// positions from their root, which orders them depth first like a menu.
+const categoryTree = `WITH RECURSIVE tree AS (
+		SELECT id, ARRAY[position] AS path FROM data_category
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND parent_id IS NULL
+		UNION ALL
+		SELECT child.id, tree.path || child.position FROM data_category child
+		JOIN tree ON child.parent_id = tree.id WHERE child.deleted_at IS NULL
+	)`
+
+// CategoryRepositoryImpl scopes every query to the tenant of the principal in
+// ctx, so categories of other tenants can neither be read nor changed.
+// Soft-deleted categories are left out as if they did not exist. The
+// principal and Clock also provide the audit fields of saved categories.
 type CategoryRepositoryImpl struct {
+	Clock helper.Clock
 }
 
-func NewCategoryRepository() *CategoryRepositoryImpl {
-	return &CategoryRepositoryImpl{}
+func NewCategoryRepository(clock helper.Clock) *CategoryRepositoryImpl {
+	return &CategoryRepositoryImpl{
+		Clock: clock,
+	}
 }
 
-func (c *CategoryRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.Category {
-	querySQL := "SELECT id, name FROM data_category"
-	rows, err := tx.QueryContext(ctx, querySQL)
+func (c *CategoryRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, filter domain.CategoryFilter) []domain.Category {
+	args := []interface{}{helper.TenantIdFromContext(ctx)}
+	var querySQL string
+	if filter.Limit > 0 {
+		// A page is read by id without the tree, which is fine as the
+		// parents of live categories are live themselves.
+		args = append(args, filter.AfterId)
+		querySQL = "SELECT " + categoryColumns + " FROM data_category WHERE tenant_id = $1 AND deleted_at IS NULL AND id > $2"
+	} else {
+		querySQL = categoryTree + " SELECT " + categoryColumns + " FROM data_category JOIN tree USING (id) WHERE true"
+	}
+	if !filter.UpdatedSince.IsZero() {
+		args = append(args, filter.UpdatedSince)
+		querySQL += " AND updated_at > $" + strconv.Itoa(len(args))
+	}
+	names := make([]string, 0, len(filter.Attributes))
+	for name := range filter.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, name, filter.Attributes[name])
+		querySQL += " AND attributes->>$" + strconv.Itoa(len(args)-1) + " = $" + strconv.Itoa(len(args))
+	}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		querySQL += " ORDER BY id LIMIT $" + strconv.Itoa(len(args))
+	} else {
+		querySQL += " ORDER BY tree.path"
+	}
+	rows, err := tx.QueryContext(ctx, querySQL, args...)
 	helper.PanicIfError(err)
 	defer rows.Close()
 
 	var categories []domain.Category
 	for rows.Next() {
-		var category domain.Category
-		err := rows.Scan(&category.Id, &category.Name)
-		helper.PanicIfError(err)
-		categories = append(categories, category)
+		categories = append(categories, scanCategory(rows))
 	}
 	return categories
 }
 
 func (c *CategoryRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category {
-	querySQL := "INSERT INTO data_category(name) VALUES ($1) RETURNING id"
+	principal := helper.MustPrincipalFromContext(ctx)
+	category.CreatedAt = c.now()
+	category.UpdatedAt = category.CreatedAt
+	category.CreatedBy = principal.Subject
+	category.UpdatedBy = principal.Subject
+	if category.Attributes == nil {
+		category.Attributes = map[string]interface{}{}
+	}
+
+	// New categories come after their siblings.
+	querySQL := `INSERT INTO data_category(tenant_id, name, slug, parent_id, position, attributes, attribute_schema,
+			created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, (SELECT COUNT(*) FROM data_category
+			WHERE tenant_id = $1 AND parent_id IS NOT DISTINCT FROM $4 AND deleted_at IS NULL), $5, $6, $7, $8, $9, $10)
+		RETURNING id, position`
 	var id int
-	rows, err := tx.QueryContext(ctx, querySQL, category.Name)
+	rows, err := tx.QueryContext(ctx, querySQL, principal.TenantId, category.Name, category.Slug, category.ParentId,
+		attributesJSON(category.Attributes), schemaJSON(category.AttributeSchema),
+		category.CreatedAt, category.UpdatedAt, category.CreatedBy, category.UpdatedBy)
 	helper.PanicIfError(err)
 	defer rows.Close()
 
 	if rows.Next() {
-		err := rows.Scan(&id)
+		err := rows.Scan(&id, &category.Position)
 		helper.PanicIfError(err)
 	}
 	category.Id = int(id)
 	return category
 }
 
-func (c *CategoryRepositoryImpl) DeleteAll(ctx context.Context, tx *sql.Tx) {
-	querySQL := "DELETE FROM data_category"
-	_, err := tx.ExecContext(ctx, querySQL)
+// deleteSelection selects the ids of the live categories a
+// domain.CategoryDeleteFilter matches, closed over their descendants, as
+// matched. Its parameters are the tenant, the subtree id and the LIKE
+// pattern of the name.
+const deleteSelection = `WITH RECURSIVE subtree AS (
+		SELECT id FROM data_category WHERE id = $2 AND tenant_id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT child.id FROM data_category child JOIN subtree ON child.parent_id = subtree.id WHERE child.deleted_at IS NULL
+	), matched AS (
+		SELECT id FROM data_category WHERE tenant_id = $1 AND deleted_at IS NULL
+			AND ($2 = 0 OR id IN (SELECT id FROM subtree))
+			AND ($3 = '' OR name ILIKE $3)
+		UNION
+		SELECT child.id FROM data_category child JOIN matched ON child.parent_id = matched.id WHERE child.deleted_at IS NULL
+	)`
+
+// DeleteAll without a filter also removes the soft-deleted categories, but
+// only returns the live ones.
+func (c *CategoryRepositoryImpl) DeleteAll(ctx context.Context, tx *sql.Tx, filter domain.CategoryDeleteFilter) []domain.Category {
+	var rows *sql.Rows
+	var err error
+	if filter == (domain.CategoryDeleteFilter{}) {
+		querySQL := `WITH deleted AS (DELETE FROM data_category WHERE tenant_id = $1 RETURNING *)
+			SELECT ` + categoryColumns + ` FROM deleted WHERE deleted_at IS NULL ORDER BY id`
+		rows, err = tx.QueryContext(ctx, querySQL, helper.TenantIdFromContext(ctx))
+	} else {
+		querySQL := deleteSelection + `, deleted AS (DELETE FROM data_category WHERE id IN (SELECT id FROM matched) RETURNING *)
+			SELECT ` + categoryColumns + ` FROM deleted ORDER BY id`
+		rows, err = tx.QueryContext(ctx, querySQL, helper.TenantIdFromContext(ctx), filter.SubtreeId, likePattern(filter.NamePattern))
+	}
 	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
+	}
+	return categories
+}
+
+func (c *CategoryRepositoryImpl) CountAll(ctx context.Context, tx *sql.Tx, filter domain.CategoryDeleteFilter) int {
+	querySQL := deleteSelection + " SELECT COUNT(*) FROM matched"
+	var count int
+	err := tx.QueryRowContext(ctx, querySQL, helper.TenantIdFromContext(ctx), filter.SubtreeId, likePattern(filter.NamePattern)).Scan(&count)
+	helper.PanicIfError(err)
+	return count
+}
+
+// likePattern turns a name pattern with * wildcards into a LIKE pattern,
+// escaping the characters LIKE treats specially.
+func likePattern(namePattern string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%").Replace(namePattern)
 }
 
 func (c *CategoryRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, categoryId int) (domain.Category, error) {
-	querySQL := "SELECT id, name FROM data_category WHERE id = $1"
-	rows, err := tx.QueryContext(ctx, querySQL, categoryId)
+	querySQL := "SELECT " + categoryColumns + " FROM data_category WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL"
+	return findCategory(ctx, tx, querySQL, categoryId, helper.TenantIdFromContext(ctx))
+}
+
+func (c *CategoryRepositoryImpl) FindAllByIds(ctx context.Context, tx *sql.Tx, categoryIds []int) []domain.Category {
+	querySQL := "SELECT " + categoryColumns + " FROM data_category WHERE id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL"
+	rows, err := tx.QueryContext(ctx, querySQL, pq.Array(categoryIds), helper.TenantIdFromContext(ctx))
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	var category domain.Category
-	if rows.Next() {
-		err := rows.Scan(&category.Id, &category.Name)
-		helper.PanicIfError(err)
-		return category, nil
-	} else {
-		return category, errors.New("category is not found")
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
 	}
+	return categories
+}
+
+func (c *CategoryRepositoryImpl) FindByName(ctx context.Context, tx *sql.Tx, name string) (domain.Category, error) {
+	querySQL := "SELECT " + categoryColumns + " FROM data_category WHERE lower(name) = lower($1) AND tenant_id = $2 AND deleted_at IS NULL"
+	return findCategory(ctx, tx, querySQL, name, helper.TenantIdFromContext(ctx))
+}
+
+func (c *CategoryRepositoryImpl) FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (domain.Category, error) {
+	querySQL := "SELECT " + categoryColumns + " FROM data_category WHERE slug = $1 AND tenant_id = $2 AND deleted_at IS NULL"
+	return findCategory(ctx, tx, querySQL, slug, helper.TenantIdFromContext(ctx))
+}
+
+func (c *CategoryRepositoryImpl) FindBySlugHistory(ctx context.Context, tx *sql.Tx, slug string) (domain.Category, error) {
+	querySQL := `SELECT c.id, c.name, c.slug, c.parent_id, c.position, c.attributes, c.attribute_schema, c.created_at, c.updated_at, c.created_by, c.updated_by FROM data_category_slug_history h
+		JOIN data_category c ON c.id = h.category_id AND c.tenant_id = h.tenant_id
+		WHERE h.slug = $1 AND h.tenant_id = $2 AND c.deleted_at IS NULL`
+	return findCategory(ctx, tx, querySQL, slug, helper.TenantIdFromContext(ctx))
+}
 
+func (c *CategoryRepositoryImpl) SaveSlugHistory(ctx context.Context, tx *sql.Tx, category domain.Category, slug string) {
+	querySQL := `INSERT INTO data_category_slug_history(tenant_id, slug, category_id) VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, slug) DO UPDATE SET category_id = EXCLUDED.category_id`
+	_, err := tx.ExecContext(ctx, querySQL, helper.TenantIdFromContext(ctx), slug, category.Id)
+	helper.PanicIfError(err)
+}
+
+func (c *CategoryRepositoryImpl) DeleteSlugHistory(ctx context.Context, tx *sql.Tx, slug string) {
+	querySQL := "DELETE FROM data_category_slug_history WHERE slug = $1 AND tenant_id = $2"
+	_, err := tx.ExecContext(ctx, querySQL, slug, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
 }
 
 func (c *CategoryRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category {
-	querySQL := "UPDATE data_category SET name = $1 WHERE id = $2"
-	_, err := tx.ExecContext(ctx, querySQL, category.Name, category.Id)
+	principal := helper.MustPrincipalFromContext(ctx)
+	category.UpdatedAt = c.now()
+	category.UpdatedBy = principal.Subject
+
+	querySQL := `UPDATE data_category SET name = $1, slug = $2, attributes = $3, attribute_schema = $4, updated_at = $5, updated_by = $6
+		WHERE id = $7 AND tenant_id = $8`
+	_, err := tx.ExecContext(ctx, querySQL, category.Name, category.Slug, attributesJSON(category.Attributes), schemaJSON(category.AttributeSchema),
+		category.UpdatedAt, category.UpdatedBy, category.Id, principal.TenantId)
 	helper.PanicIfError(err)
 	return category
 }
 
 func (c *CategoryRepositoryImpl) DeleteById(ctx context.Context, tx *sql.Tx, category domain.Category) {
-	querySQL := "DELETE FROM data_category WHERE id = $1"
-	_, err := tx.ExecContext(ctx, querySQL, category.Id)
+	querySQL := "DELETE FROM data_category WHERE id = $1 AND tenant_id = $2"
+	_, err := tx.ExecContext(ctx, querySQL, category.Id, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+}
+
+func (c *CategoryRepositoryImpl) HasChildren(ctx context.Context, tx *sql.Tx, category domain.Category) bool {
+	querySQL := "SELECT EXISTS (SELECT 1 FROM data_category WHERE parent_id = $1 AND tenant_id = $2 AND deleted_at IS NULL)"
+	var exists bool
+	err := tx.QueryRowContext(ctx, querySQL, category.Id, helper.TenantIdFromContext(ctx)).Scan(&exists)
+	helper.PanicIfError(err)
+	return exists
+}
+
+func (c *CategoryRepositoryImpl) FindChildren(ctx context.Context, tx *sql.Tx, parentId *int) []domain.Category {
+	querySQL := "SELECT " + categoryColumns + ` FROM data_category
+		WHERE parent_id IS NOT DISTINCT FROM $1 AND tenant_id = $2 AND deleted_at IS NULL ORDER BY position, id`
+	rows, err := tx.QueryContext(ctx, querySQL, parentId, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
+	}
+	return categories
+}
+
+func (c *CategoryRepositoryImpl) FindAllByParentIds(ctx context.Context, tx *sql.Tx, parentIds []int) []domain.Category {
+	querySQL := "SELECT " + categoryColumns + ` FROM data_category
+		WHERE parent_id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL ORDER BY parent_id, position, id`
+	rows, err := tx.QueryContext(ctx, querySQL, pq.Array(parentIds), helper.TenantIdFromContext(ctx))
 	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
+	}
+	return categories
+}
+
+func (c *CategoryRepositoryImpl) FindSubtree(ctx context.Context, tx *sql.Tx, rootId int) []domain.Category {
+	querySQL := `WITH RECURSIVE tree AS (
+			SELECT id, ARRAY[0] AS path FROM data_category WHERE id = $2 AND tenant_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, tree.path || child.position FROM data_category child
+			JOIN tree ON child.parent_id = tree.id WHERE child.deleted_at IS NULL
+		)
+		SELECT ` + categoryColumns + " FROM data_category JOIN tree USING (id) ORDER BY tree.path"
+	rows, err := tx.QueryContext(ctx, querySQL, helper.TenantIdFromContext(ctx), rootId)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
+	}
+	return categories
+}
+
+// Renumber first parks the changed categories at negative positions, so no
+// statement ever sees two siblings at the same position.
+func (c *CategoryRepositoryImpl) Renumber(ctx context.Context, tx *sql.Tx, parentId *int, categoryIds []int) []domain.Category {
+	principal := helper.MustPrincipalFromContext(ctx)
+	querySQL := `UPDATE data_category c SET parent_id = $1, position = -o.idx
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, idx)
+		WHERE c.id = o.id AND c.tenant_id = $3 AND c.deleted_at IS NULL
+			AND (c.position <> o.idx - 1 OR c.parent_id IS DISTINCT FROM $1)`
+	_, err := tx.ExecContext(ctx, querySQL, parentId, pq.Array(categoryIds), principal.TenantId)
+	helper.PanicIfError(err)
+
+	querySQL = `UPDATE data_category SET position = -position - 1, updated_at = $1, updated_by = $2
+		WHERE tenant_id = $3 AND position < 0 RETURNING ` + categoryColumns
+	rows, err := tx.QueryContext(ctx, querySQL, c.now(), principal.Subject, principal.TenantId)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
+	}
+	return categories
+}
+
+func (c *CategoryRepositoryImpl) InSubtree(ctx context.Context, tx *sql.Tx, rootId int, categoryId int) bool {
+	querySQL := `WITH RECURSIVE subtree AS (
+			SELECT id FROM data_category WHERE id = $1 AND tenant_id = $3
+			UNION ALL
+			SELECT child.id FROM data_category child JOIN subtree ON child.parent_id = subtree.id
+		)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+	var exists bool
+	err := tx.QueryRowContext(ctx, querySQL, rootId, categoryId, helper.TenantIdFromContext(ctx)).Scan(&exists)
+	helper.PanicIfError(err)
+	return exists
+}
+
+func (c *CategoryRepositoryImpl) MoveChildren(ctx context.Context, tx *sql.Tx, source domain.Category, target domain.Category) []domain.Category {
+	principal := helper.MustPrincipalFromContext(ctx)
+	// The children keep their order after the children target already has.
+	querySQL := `UPDATE data_category SET parent_id = $1, updated_at = $2, updated_by = $3,
+			position = position + (SELECT COUNT(*) FROM data_category WHERE parent_id = $1 AND tenant_id = $5 AND deleted_at IS NULL)
+		WHERE parent_id = $4 AND tenant_id = $5 AND deleted_at IS NULL RETURNING ` + categoryColumns
+	rows, err := tx.QueryContext(ctx, querySQL, target.Id, c.now(), principal.Subject, source.Id, principal.TenantId)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		categories = append(categories, scanCategory(rows))
+	}
+	return categories
+}
+
+func (c *CategoryRepositoryImpl) MoveSlugHistory(ctx context.Context, tx *sql.Tx, source domain.Category, target domain.Category) {
+	querySQL := "UPDATE data_category_slug_history SET category_id = $1 WHERE category_id = $2 AND tenant_id = $3"
+	_, err := tx.ExecContext(ctx, querySQL, target.Id, source.Id, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+}
+
+func (c *CategoryRepositoryImpl) SoftDeleteById(ctx context.Context, tx *sql.Tx, category domain.Category) {
+	principal := helper.MustPrincipalFromContext(ctx)
+	now := c.now()
+	querySQL := `UPDATE data_category SET deleted_at = $1, parent_id = NULL, updated_at = $1, updated_by = $2
+		WHERE id = $3 AND tenant_id = $4`
+	_, err := tx.ExecContext(ctx, querySQL, now, principal.Subject, category.Id, principal.TenantId)
+	helper.PanicIfError(err)
+}
+
+// escapedName is the name escaped like html.EscapeString. It is highlighted
+// instead of the name, so the <mark> tags are the only markup in a highlight
+// and a name cannot inject any of its own.
+const escapedName = `replace(replace(replace(replace(replace(name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+
+// Search combines the full-text rank of search_vector with the trigram
+// similarity of the name or the best matching alias, so both whole words and
+// misspellings match and aliases resolve to their category.
+func (c *CategoryRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, search domain.CategorySearch) []domain.CategorySearchResult {
+	querySQL := `SELECT ` + categoryColumns + `,
+			ts_rank(search_vector, query) + greatest(similarity(name, $2), CASE WHEN a.alias_matched THEN a.alias_similarity ELSE 0 END) AS score,
+			ts_headline('simple', ` + escapedName + `, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight,
+			CASE WHEN a.alias_matched THEN a.alias ELSE '' END AS alias
+		FROM data_category
+		CROSS JOIN websearch_to_tsquery('simple', $2) query
+		LEFT JOIN LATERAL (
+			SELECT alias, similarity(alias, $2) AS alias_similarity,
+				to_tsvector('simple', alias) @@ query OR alias % $2 AS alias_matched
+			FROM category_alias WHERE category_id = data_category.id
+			ORDER BY alias_matched DESC, alias_similarity DESC LIMIT 1
+		) a ON true
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND (search_vector @@ query OR name % $2 OR a.alias_matched)`
+	args := []interface{}{search.TenantId, search.Query}
+	if search.SubtreeId != 0 {
+		args = append(args, search.SubtreeId)
+		querySQL += ` AND id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM data_category WHERE id = $3 AND tenant_id = $1
+				UNION ALL
+				SELECT child.id FROM data_category child JOIN subtree ON child.parent_id = subtree.id
+			)
+			SELECT id FROM subtree)`
+	}
+	args = append(args, search.Limit)
+	querySQL += " ORDER BY score DESC, id LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := tx.QueryContext(ctx, querySQL, args...)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var results []domain.CategorySearchResult
+	for rows.Next() {
+		var result domain.CategorySearchResult
+		result.Category = scanCategory(rows, &result.Score, &result.Highlight, &result.Alias)
+		results = append(results, result)
+	}
+	return results
+}
+
+// now is truncated to the precision PostgreSQL stores, so returned categories
+// match what is read back later.
+func (c *CategoryRepositoryImpl) now() time.Time {
+	return c.Clock().UTC().Truncate(time.Microsecond)
+}
+
+func findCategory(ctx context.Context, tx *sql.Tx, querySQL string, args ...interface{}) (domain.Category, error) {
+	rows, err := tx.QueryContext(ctx, querySQL, args...)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanCategory(rows), nil
+	} else {
+		return domain.Category{}, errors.New("category is not found")
+	}
+}
+
+// scanCategory scans the categoryColumns of a row followed by extra columns.
+func scanCategory(rows *sql.Rows, extra ...interface{}) domain.Category {
+	var category domain.Category
+	var parentId sql.NullInt64
+	var attributes, attributeSchema []byte
+	dest := []interface{}{&category.Id, &category.Name, &category.Slug, &parentId, &category.Position, &attributes, &attributeSchema,
+		&category.CreatedAt, &category.UpdatedAt, &category.CreatedBy, &category.UpdatedBy}
+	err := rows.Scan(append(dest, extra...)...)
+	helper.PanicIfError(err)
+	if parentId.Valid {
+		id := int(parentId.Int64)
+		category.ParentId = &id
+	}
+	err = json.Unmarshal(attributes, &category.Attributes)
+	helper.PanicIfError(err)
+	if attributeSchema != nil {
+		category.AttributeSchema = attributeSchema
+	}
+	return category
+}
+
+// attributesJSON encodes attributes for a JSONB parameter. lib/pq sends
+// []byte as bytea, so JSON is passed as a string.
+func attributesJSON(attributes map[string]interface{}) string {
+	if attributes == nil {
+		return "{}"
+	}
+	data, err := json.Marshal(attributes)
+	helper.PanicIfError(err)
+	return string(data)
+}
+
+func schemaJSON(schema json.RawMessage) interface{} {
+	if schema == nil {
+		return nil
+	}
+	return string(schema)
 }
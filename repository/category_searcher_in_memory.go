@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// similarityThreshold is the default of pg_trgm's % operator.
+const similarityThreshold = 0.3
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// CategorySearcherInMemory models the ranking of CategoryRepositoryImpl.Search
+// without PostgreSQL: every query word must be a word of the name or slug, or
+// the name must be similar enough by trigrams. Aliases match the same way.
+// It only pins those rules down in unit tests; the service always searches
+// through CategoryRepository, so the tests do not cover the SQL itself.
+type CategorySearcherInMemory struct {
+	mu         sync.Mutex
+	categories map[int][]domain.Category
+	aliases    map[int][]string
+}
+
+func NewCategorySearcherInMemory() *CategorySearcherInMemory {
+	return &CategorySearcherInMemory{
+		categories: map[int][]domain.Category{},
+		aliases:    map[int][]string{},
+	}
+}
+
+// Add makes categories of the tenant searchable.
+func (cs *CategorySearcherInMemory) Add(tenantId int, categories ...domain.Category) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.categories[tenantId] = append(cs.categories[tenantId], categories...)
+}
+
+// AddAliases makes the category searchable by aliases.
+func (cs *CategorySearcherInMemory) AddAliases(categoryId int, aliases ...string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.aliases[categoryId] = append(cs.aliases[categoryId], aliases...)
+}
+
+func (cs *CategorySearcherInMemory) Search(ctx context.Context, tx *sql.Tx, search domain.CategorySearch) []domain.CategorySearchResult {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	categories := cs.categories[search.TenantId]
+	if search.SubtreeId != 0 {
+		categories = subtree(categories, search.SubtreeId)
+	}
+
+	queryWords := map[string]bool{}
+	for _, word := range wordPattern.FindAllString(strings.ToLower(search.Query), -1) {
+		queryWords[word] = true
+	}
+
+	var results []domain.CategorySearchResult
+	for _, category := range categories {
+		fullText := wordsMatch(queryWords, category.Name+" "+category.Slug)
+		similarity := trigramSimilarity(category.Name, search.Query)
+
+		matchedAlias, aliasSimilarity := "", 0.0
+		for _, alias := range cs.aliases[category.Id] {
+			s := trigramSimilarity(alias, search.Query)
+			if (wordsMatch(queryWords, alias) || s >= similarityThreshold) && (matchedAlias == "" || s > aliasSimilarity) {
+				matchedAlias, aliasSimilarity = alias, s
+			}
+		}
+		if !fullText && similarity < similarityThreshold && matchedAlias == "" {
+			continue
+		}
+
+		score := math.Max(similarity, aliasSimilarity)
+		if fullText {
+			score += 0.1
+		}
+		results = append(results, domain.CategorySearchResult{Category: category, Score: score, Highlight: highlight(category.Name, queryWords, fullText), Alias: matchedAlias})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Category.Id < results[j].Category.Id
+	})
+	if len(results) > search.Limit {
+		results = results[:search.Limit]
+	}
+	return results
+}
+
+// highlight escapes name like the escapedName of CategoryRepositoryImpl and
+// wraps the query words in <mark> tags when the full text matched.
+func highlight(name string, queryWords map[string]bool, fullText bool) string {
+	var b strings.Builder
+	end := 0
+	for _, match := range wordPattern.FindAllStringIndex(name, -1) {
+		b.WriteString(html.EscapeString(name[end:match[0]]))
+		word := name[match[0]:match[1]]
+		if fullText && queryWords[strings.ToLower(word)] {
+			b.WriteString("<mark>" + word + "</mark>")
+		} else {
+			b.WriteString(word)
+		}
+		end = match[1]
+	}
+	b.WriteString(html.EscapeString(name[end:]))
+	return b.String()
+}
+
+// wordsMatch reports whether every query word is a word of text.
+func wordsMatch(queryWords map[string]bool, text string) bool {
+	words := map[string]bool{}
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		words[word] = true
+	}
+	for word := range queryWords {
+		if !words[word] {
+			return false
+		}
+	}
+	return len(queryWords) > 0
+}
+
+func subtree(categories []domain.Category, rootId int) []domain.Category {
+	included := map[int]bool{rootId: true}
+	for changed := true; changed; {
+		changed = false
+		for _, category := range categories {
+			if !included[category.Id] && category.ParentId != nil && included[*category.ParentId] {
+				included[category.Id] = true
+				changed = true
+			}
+		}
+	}
+
+	var result []domain.Category
+	for _, category := range categories {
+		if included[category.Id] {
+			result = append(result, category)
+		}
+	}
+	return result
+}
+
+// trigramSimilarity is pg_trgm's similarity: the shared trigrams of both
+// strings divided by all of their trigrams, with words padded by spaces.
+func trigramSimilarity(a string, b string) float64 {
+	trigramsA, trigramsB := trigrams(a), trigrams(b)
+	if len(trigramsA) == 0 || len(trigramsB) == 0 {
+		return 0
+	}
+	shared := 0
+	for trigram := range trigramsA {
+		if trigramsB[trigram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(trigramsA)+len(trigramsB)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	result := map[string]bool{}
+	for _, word := range wordPattern.FindAllString(strings.ToLower(s), -1) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			result[string(padded[i:i+3])] = true
+		}
+	}
+	return result
+}
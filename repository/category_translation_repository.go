@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CategoryTranslationRepository stores the names of the categories of the
+// tenant in ctx in other locales. Deleting a category deletes its
+// translations.
+type CategoryTranslationRepository interface {
+	FindAllByCategory(ctx context.Context, tx *sql.Tx, categoryId int) []domain.CategoryTranslation
+	// FindNames returns the names of categoryIds in locales by category id
+	// and locale, leaving out missing translations.
+	FindNames(ctx context.Context, tx *sql.Tx, categoryIds []int, locales []string) map[int]map[string]string
+	// Save adds the translation or replaces the name of an existing one.
+	Save(ctx context.Context, tx *sql.Tx, translation domain.CategoryTranslation) domain.CategoryTranslation
+	// Delete reports whether there was a translation to delete.
+	Delete(ctx context.Context, tx *sql.Tx, categoryId int, locale string) bool
+}
+
+const categoryTranslationColumns = "category_id, locale, name, updated_at, updated_by"
+
+type CategoryTranslationRepositoryImpl struct {
+	Clock helper.Clock
+}
+
+func NewCategoryTranslationRepository(clock helper.Clock) *CategoryTranslationRepositoryImpl {
+	return &CategoryTranslationRepositoryImpl{
+		Clock: clock,
+	}
+}
+
+func (tr *CategoryTranslationRepositoryImpl) FindAllByCategory(ctx context.Context, tx *sql.Tx, categoryId int) []domain.CategoryTranslation {
+	querySQL := "SELECT " + categoryTranslationColumns + " FROM category_translation WHERE category_id = $1 AND tenant_id = $2 ORDER BY locale"
+	rows, err := tx.QueryContext(ctx, querySQL, categoryId, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var translations []domain.CategoryTranslation
+	for rows.Next() {
+		translation := domain.CategoryTranslation{}
+		err := rows.Scan(&translation.CategoryId, &translation.Locale, &translation.Name, &translation.UpdatedAt, &translation.UpdatedBy)
+		helper.PanicIfError(err)
+		translations = append(translations, translation)
+	}
+	return translations
+}
+
+func (tr *CategoryTranslationRepositoryImpl) FindNames(ctx context.Context, tx *sql.Tx, categoryIds []int, locales []string) map[int]map[string]string {
+	names := map[int]map[string]string{}
+	if len(categoryIds) == 0 || len(locales) == 0 {
+		return names
+	}
+
+	querySQL := "SELECT category_id, locale, name FROM category_translation WHERE category_id = ANY($1) AND locale = ANY($2) AND tenant_id = $3"
+	rows, err := tx.QueryContext(ctx, querySQL, pq.Array(categoryIds), pq.Array(locales), helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var categoryId int
+		var locale, name string
+		err := rows.Scan(&categoryId, &locale, &name)
+		helper.PanicIfError(err)
+		if names[categoryId] == nil {
+			names[categoryId] = map[string]string{}
+		}
+		names[categoryId][locale] = name
+	}
+	return names
+}
+
+func (tr *CategoryTranslationRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, translation domain.CategoryTranslation) domain.CategoryTranslation {
+	principal := helper.MustPrincipalFromContext(ctx)
+	translation.UpdatedAt = tr.Clock().UTC().Truncate(time.Microsecond)
+	translation.UpdatedBy = principal.Subject
+
+	querySQL := `INSERT INTO category_translation(tenant_id, category_id, locale, name, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (category_id, locale) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by`
+	_, err := tx.ExecContext(ctx, querySQL, principal.TenantId, translation.CategoryId, translation.Locale, translation.Name,
+		translation.UpdatedAt, translation.UpdatedBy)
+	helper.PanicIfError(err)
+	return translation
+}
+
+func (tr *CategoryTranslationRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, categoryId int, locale string) bool {
+	querySQL := "DELETE FROM category_translation WHERE category_id = $1 AND locale = $2 AND tenant_id = $3"
+	result, err := tx.ExecContext(ctx, querySQL, categoryId, locale, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	deleted, err := result.RowsAffected()
+	helper.PanicIfError(err)
+	return deleted > 0
+}
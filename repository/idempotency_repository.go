@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IdempotencyRepository stores the responses of requests sent with an
+// Idempotency-Key header. Records outlive a single request transaction, so
+// implementations manage their own storage instead of taking a *sql.Tx.
+// Expired records are purged as new ones are saved.
+type IdempotencyRepository interface {
+	// Save reserves record.Key and reports whether it succeeded. A key that is
+	// already held by a record which has not expired cannot be reserved.
+	Save(ctx context.Context, record domain.IdempotencyRecord) bool
+	FindByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error)
+	UpdateByKey(ctx context.Context, record domain.IdempotencyRecord) domain.IdempotencyRecord
+	DeleteByKey(ctx context.Context, key string)
+}
+
+type IdempotencyRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (ir *IdempotencyRepositoryImpl) Save(ctx context.Context, record domain.IdempotencyRecord) bool {
+	querySQL := `WITH purged AS (DELETE FROM idempotency_key WHERE expires_at <= now() AND key <> $1)
+		INSERT INTO idempotency_key(key, request_hash, status_code, body, expires_at) VALUES ($1, $2, 0, NULL, $3)
+		ON CONFLICT (key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status_code = 0, body = NULL, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at <= now() RETURNING key`
+	rows, err := ir.DB.QueryContext(ctx, querySQL, record.Key, record.RequestHash, record.ExpiresAt)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	return rows.Next()
+}
+
+func (ir *IdempotencyRepositoryImpl) FindByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	querySQL := "SELECT key, request_hash, status_code, body, content_type, expires_at FROM idempotency_key WHERE key = $1 AND expires_at > now()"
+	rows, err := ir.DB.QueryContext(ctx, querySQL, key)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var record domain.IdempotencyRecord
+	if rows.Next() {
+		err := rows.Scan(&record.Key, &record.RequestHash, &record.StatusCode, &record.Body, &record.ContentType, &record.ExpiresAt)
+		helper.PanicIfError(err)
+		return record, nil
+	} else {
+		return record, errors.New("idempotency key is not found")
+	}
+}
+
+func (ir *IdempotencyRepositoryImpl) UpdateByKey(ctx context.Context, record domain.IdempotencyRecord) domain.IdempotencyRecord {
+	querySQL := "UPDATE idempotency_key SET status_code = $1, body = $2, content_type = $3 WHERE key = $4"
+	_, err := ir.DB.ExecContext(ctx, querySQL, record.StatusCode, record.Body, record.ContentType, record.Key)
+	helper.PanicIfError(err)
+	return record
+}
+
+func (ir *IdempotencyRepositoryImpl) DeleteByKey(ctx context.Context, key string) {
+	querySQL := "DELETE FROM idempotency_key WHERE key = $1"
+	_, err := ir.DB.ExecContext(ctx, querySQL, key)
+	helper.PanicIfError(err)
+}
+
+// idempotencyPurgeInterval is how often IdempotencyRepositoryInMemory scans
+// for expired records, so Save stays cheap.
+const idempotencyPurgeInterval = time.Minute
+
+type IdempotencyRepositoryInMemory struct {
+	mu        sync.Mutex
+	records   map[string]domain.IdempotencyRecord
+	nextPurge time.Time
+	Now       func() time.Time
+}
+
+func NewIdempotencyRepositoryInMemory() *IdempotencyRepositoryInMemory {
+	return &IdempotencyRepositoryInMemory{
+		records: map[string]domain.IdempotencyRecord{},
+		Now:     time.Now,
+	}
+}
+
+func (ir *IdempotencyRepositoryInMemory) Save(ctx context.Context, record domain.IdempotencyRecord) bool {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	now := ir.Now()
+	if !now.Before(ir.nextPurge) {
+		for key, existing := range ir.records {
+			if !now.Before(existing.ExpiresAt) {
+				delete(ir.records, key)
+			}
+		}
+		ir.nextPurge = now.Add(idempotencyPurgeInterval)
+	}
+	if existing, ok := ir.records[record.Key]; ok && now.Before(existing.ExpiresAt) {
+		return false
+	}
+	record.StatusCode = 0
+	record.Body = nil
+	ir.records[record.Key] = record
+	return true
+}
+
+func (ir *IdempotencyRepositoryInMemory) FindByKey(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	record, ok := ir.records[key]
+	if !ok || !ir.Now().Before(record.ExpiresAt) {
+		return domain.IdempotencyRecord{}, errors.New("idempotency key is not found")
+	}
+	return record, nil
+}
+
+func (ir *IdempotencyRepositoryInMemory) UpdateByKey(ctx context.Context, record domain.IdempotencyRecord) domain.IdempotencyRecord {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if existing, ok := ir.records[record.Key]; ok {
+		existing.StatusCode = record.StatusCode
+		existing.Body = record.Body
+		existing.ContentType = record.ContentType
+		ir.records[record.Key] = existing
+	}
+	return record
+}
+
+func (ir *IdempotencyRepositoryInMemory) DeleteByKey(ctx context.Context, key string) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	delete(ir.records, key)
+}
+
+// Len returns the number of records held, including expired ones that have
+// not been purged yet.
+func (ir *IdempotencyRepositoryInMemory) Len() int {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	return len(ir.records)
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ItemRepository stores the items of the tenant in ctx and their assignments
+// to categories. The category ids it is given are expected to be categories
+// of the same tenant.
+type ItemRepository interface {
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.Item
+	Save(ctx context.Context, tx *sql.Tx, item domain.Item) domain.Item
+	FindById(ctx context.Context, tx *sql.Tx, itemId int) (domain.Item, error)
+	UpdateById(ctx context.Context, tx *sql.Tx, item domain.Item) domain.Item
+	DeleteById(ctx context.Context, tx *sql.Tx, item domain.Item)
+	// FindByCategories returns the items assigned to any of categoryIds.
+	FindByCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) []domain.Item
+	// CountByCategories returns the number of items assigned to each of
+	// categoryIds, leaving out categories without items.
+	CountByCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) map[int]int
+	// MoveAssignments assigns the items of source to target instead.
+	MoveAssignments(ctx context.Context, tx *sql.Tx, source domain.Category, target domain.Category)
+}
+
+// itemColumns selects the assigned category ids as an array, so an item is
+// always read with its assignments.
+const itemColumns = `id, name, ARRAY(SELECT category_id FROM item_category WHERE item_id = item.id ORDER BY category_id),
+	created_at, updated_at, created_by, updated_by`
+
+type ItemRepositoryImpl struct {
+	Clock helper.Clock
+}
+
+func NewItemRepository(clock helper.Clock) *ItemRepositoryImpl {
+	return &ItemRepositoryImpl{
+		Clock: clock,
+	}
+}
+
+func (ir *ItemRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.Item {
+	querySQL := "SELECT " + itemColumns + " FROM item WHERE tenant_id = $1 ORDER BY id"
+	return findItems(ctx, tx, querySQL, helper.TenantIdFromContext(ctx))
+}
+
+func (ir *ItemRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, item domain.Item) domain.Item {
+	principal := helper.MustPrincipalFromContext(ctx)
+	item.CreatedAt = ir.now()
+	item.UpdatedAt = item.CreatedAt
+	item.CreatedBy = principal.Subject
+	item.UpdatedBy = principal.Subject
+
+	querySQL := `INSERT INTO item(tenant_id, name, created_at, updated_at, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err := tx.QueryRowContext(ctx, querySQL, principal.TenantId, item.Name, item.CreatedAt, item.UpdatedAt,
+		item.CreatedBy, item.UpdatedBy).Scan(&item.Id)
+	helper.PanicIfError(err)
+
+	ir.saveAssignments(ctx, tx, item)
+	return item
+}
+
+func (ir *ItemRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, itemId int) (domain.Item, error) {
+	querySQL := "SELECT " + itemColumns + " FROM item WHERE id = $1 AND tenant_id = $2"
+	items := findItems(ctx, tx, querySQL, itemId, helper.TenantIdFromContext(ctx))
+	if len(items) == 0 {
+		return domain.Item{}, errors.New("item is not found")
+	}
+	return items[0], nil
+}
+
+func (ir *ItemRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx, item domain.Item) domain.Item {
+	principal := helper.MustPrincipalFromContext(ctx)
+	item.UpdatedAt = ir.now()
+	item.UpdatedBy = principal.Subject
+
+	querySQL := "UPDATE item SET name = $1, updated_at = $2, updated_by = $3 WHERE id = $4 AND tenant_id = $5"
+	_, err := tx.ExecContext(ctx, querySQL, item.Name, item.UpdatedAt, item.UpdatedBy, item.Id, principal.TenantId)
+	helper.PanicIfError(err)
+
+	ir.saveAssignments(ctx, tx, item)
+	return item
+}
+
+func (ir *ItemRepositoryImpl) DeleteById(ctx context.Context, tx *sql.Tx, item domain.Item) {
+	querySQL := "DELETE FROM item WHERE id = $1 AND tenant_id = $2"
+	_, err := tx.ExecContext(ctx, querySQL, item.Id, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+}
+
+func (ir *ItemRepositoryImpl) FindByCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) []domain.Item {
+	querySQL := "SELECT " + itemColumns + ` FROM item
+		WHERE tenant_id = $1 AND id IN (SELECT item_id FROM item_category WHERE category_id = ANY($2)) ORDER BY id`
+	return findItems(ctx, tx, querySQL, helper.TenantIdFromContext(ctx), pq.Array(categoryIds))
+}
+
+func (ir *ItemRepositoryImpl) CountByCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) map[int]int {
+	counts := map[int]int{}
+	if len(categoryIds) == 0 {
+		return counts
+	}
+
+	querySQL := `SELECT ic.category_id, COUNT(*) FROM item_category ic JOIN item i ON i.id = ic.item_id
+		WHERE ic.category_id = ANY($1) AND i.tenant_id = $2 GROUP BY ic.category_id`
+	rows, err := tx.QueryContext(ctx, querySQL, pq.Array(categoryIds), helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var categoryId, count int
+		err := rows.Scan(&categoryId, &count)
+		helper.PanicIfError(err)
+		counts[categoryId] = count
+	}
+	return counts
+}
+
+func (ir *ItemRepositoryImpl) MoveAssignments(ctx context.Context, tx *sql.Tx, source domain.Category, target domain.Category) {
+	querySQL := `INSERT INTO item_category(item_id, category_id)
+		SELECT item_id, $2 FROM item_category WHERE category_id = $1 ON CONFLICT DO NOTHING`
+	_, err := tx.ExecContext(ctx, querySQL, source.Id, target.Id)
+	helper.PanicIfError(err)
+
+	querySQL = "DELETE FROM item_category WHERE category_id = $1"
+	_, err = tx.ExecContext(ctx, querySQL, source.Id)
+	helper.PanicIfError(err)
+}
+
+// saveAssignments makes item.CategoryIds the only categories of item.
+func (ir *ItemRepositoryImpl) saveAssignments(ctx context.Context, tx *sql.Tx, item domain.Item) {
+	querySQL := "DELETE FROM item_category WHERE item_id = $1 AND NOT category_id = ANY($2)"
+	_, err := tx.ExecContext(ctx, querySQL, item.Id, pq.Array(item.CategoryIds))
+	helper.PanicIfError(err)
+
+	querySQL = `INSERT INTO item_category(item_id, category_id)
+		SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING`
+	_, err = tx.ExecContext(ctx, querySQL, item.Id, pq.Array(item.CategoryIds))
+	helper.PanicIfError(err)
+}
+
+func (ir *ItemRepositoryImpl) now() time.Time {
+	return ir.Clock().UTC().Truncate(time.Microsecond)
+}
+
+func findItems(ctx context.Context, tx *sql.Tx, querySQL string, args ...interface{}) []domain.Item {
+	rows, err := tx.QueryContext(ctx, querySQL, args...)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var items []domain.Item
+	for rows.Next() {
+		var item domain.Item
+		var categoryIds pq.Int64Array
+		err := rows.Scan(&item.Id, &item.Name, &categoryIds, &item.CreatedAt, &item.UpdatedAt, &item.CreatedBy, &item.UpdatedBy)
+		helper.PanicIfError(err)
+		item.CategoryIds = make([]int, 0, len(categoryIds))
+		for _, categoryId := range categoryIds {
+			item.CategoryIds = append(item.CategoryIds, int(categoryId))
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"Data-Category/model/domain"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitRepository keeps one token bucket per key. Take has to be atomic
+// per key so that concurrent requests cannot spend the same token twice.
+type RateLimitRepository interface {
+	Take(ctx context.Context, key string, budget domain.RateLimitBudget) domain.RateLimitStatus
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	window  time.Duration
+}
+
+type RateLimitRepositoryInMemory struct {
+	Now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	takes   int
+}
+
+func NewRateLimitRepositoryInMemory() *RateLimitRepositoryInMemory {
+	return &RateLimitRepositoryInMemory{
+		Now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+func (rr *RateLimitRepositoryInMemory) Take(ctx context.Context, key string, budget domain.RateLimitBudget) domain.RateLimitStatus {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	now := rr.Now()
+	limit := float64(budget.Limit)
+	rate := limit / budget.Window.Seconds()
+
+	rr.takes++
+	if rr.takes%1024 == 0 {
+		rr.sweep(now)
+	}
+
+	bucket, ok := rr.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limit, updated: now, window: budget.Window}
+		rr.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(limit, bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+
+	status := domain.RateLimitStatus{
+		Limit: budget.Limit,
+	}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		status.Allowed = true
+	} else {
+		status.RetryAfter = secondsToDuration((1 - bucket.tokens) / rate)
+	}
+	status.Remaining = int(math.Floor(bucket.tokens))
+	status.Reset = secondsToDuration((limit - bucket.tokens) / rate)
+
+	return status
+}
+
+// sweep drops buckets that have been idle long enough to be full again, since
+// a fresh bucket behaves exactly the same.
+func (rr *RateLimitRepositoryInMemory) sweep(now time.Time) {
+	for key, bucket := range rr.buckets {
+		if now.Sub(bucket.updated) > bucket.window {
+			delete(rr.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
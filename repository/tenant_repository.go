@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type TenantRepository interface {
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.Tenant
+	Save(ctx context.Context, tx *sql.Tx, tenant domain.Tenant) domain.Tenant
+	FindById(ctx context.Context, tx *sql.Tx, tenantId int) (domain.Tenant, error)
+	FindByName(ctx context.Context, tx *sql.Tx, name string) (domain.Tenant, error)
+	FindByApiKeyHash(ctx context.Context, tx *sql.Tx, apiKeyHash string) (domain.Tenant, error)
+	UpdateById(ctx context.Context, tx *sql.Tx, tenant domain.Tenant) domain.Tenant
+}
+
+type TenantRepositoryImpl struct {
+}
+
+func NewTenantRepository() *TenantRepositoryImpl {
+	return &TenantRepositoryImpl{}
+}
+
+func (tr *TenantRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.Tenant {
+	querySQL := "SELECT id, name, COALESCE(api_key_hash, ''), disabled FROM tenant ORDER BY id"
+	rows, err := tx.QueryContext(ctx, querySQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var tenants []domain.Tenant
+	for rows.Next() {
+		var tenant domain.Tenant
+		err := rows.Scan(&tenant.Id, &tenant.Name, &tenant.ApiKeyHash, &tenant.Disabled)
+		helper.PanicIfError(err)
+		tenants = append(tenants, tenant)
+	}
+	return tenants
+}
+
+func (tr *TenantRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, tenant domain.Tenant) domain.Tenant {
+	querySQL := "INSERT INTO tenant(name, api_key_hash, disabled) VALUES ($1, $2, $3) RETURNING id"
+	rows, err := tx.QueryContext(ctx, querySQL, tenant.Name, tenant.ApiKeyHash, tenant.Disabled)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	if rows.Next() {
+		err := rows.Scan(&tenant.Id)
+		helper.PanicIfError(err)
+	}
+	return tenant
+}
+
+func (tr *TenantRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, tenantId int) (domain.Tenant, error) {
+	querySQL := "SELECT id, name, COALESCE(api_key_hash, ''), disabled FROM tenant WHERE id = $1"
+	return tr.findOne(ctx, tx, querySQL, tenantId)
+}
+
+func (tr *TenantRepositoryImpl) FindByName(ctx context.Context, tx *sql.Tx, name string) (domain.Tenant, error) {
+	querySQL := "SELECT id, name, COALESCE(api_key_hash, ''), disabled FROM tenant WHERE name = $1"
+	return tr.findOne(ctx, tx, querySQL, name)
+}
+
+func (tr *TenantRepositoryImpl) FindByApiKeyHash(ctx context.Context, tx *sql.Tx, apiKeyHash string) (domain.Tenant, error) {
+	querySQL := "SELECT id, name, COALESCE(api_key_hash, ''), disabled FROM tenant WHERE api_key_hash = $1"
+	return tr.findOne(ctx, tx, querySQL, apiKeyHash)
+}
+
+func (tr *TenantRepositoryImpl) UpdateById(ctx context.Context, tx *sql.Tx, tenant domain.Tenant) domain.Tenant {
+	querySQL := "UPDATE tenant SET name = $1, disabled = $2 WHERE id = $3"
+	_, err := tx.ExecContext(ctx, querySQL, tenant.Name, tenant.Disabled, tenant.Id)
+	helper.PanicIfError(err)
+	return tenant
+}
+
+func (tr *TenantRepositoryImpl) findOne(ctx context.Context, tx *sql.Tx, querySQL string, args ...interface{}) (domain.Tenant, error) {
+	rows, err := tx.QueryContext(ctx, querySQL, args...)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var tenant domain.Tenant
+	if rows.Next() {
+		err := rows.Scan(&tenant.Id, &tenant.Name, &tenant.ApiKeyHash, &tenant.Disabled)
+		helper.PanicIfError(err)
+		return tenant, nil
+	} else {
+		return tenant, errors.New("tenant is not found")
+	}
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WebhookRepository stores the webhook subscriptions of each tenant, the
+// outbox of events still to be delivered and the log of delivery attempts.
+// Subscriptions and delivery logs are scoped to the tenant in ctx; the outbox
+// is drained by a worker for all tenants.
+type WebhookRepository interface {
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.WebhookSubscription
+	Save(ctx context.Context, tx *sql.Tx, subscription domain.WebhookSubscription) domain.WebhookSubscription
+	FindById(ctx context.Context, tx *sql.Tx, subscriptionId int) (domain.WebhookSubscription, error)
+	DeleteById(ctx context.Context, tx *sql.Tx, subscription domain.WebhookSubscription)
+	// SaveEvent queues payload for every subscription of the tenant in ctx
+	// that listens to eventType.
+	SaveEvent(ctx context.Context, tx *sql.Tx, eventType string, payload []byte)
+	// ClaimEvents returns up to limit pending events that are due and hides
+	// them from other claims until leaseUntil.
+	ClaimEvents(ctx context.Context, tx *sql.Tx, limit int, leaseUntil time.Time) []domain.WebhookOutbox
+	UpdateEvent(ctx context.Context, tx *sql.Tx, event domain.WebhookOutbox)
+	SaveDelivery(ctx context.Context, tx *sql.Tx, delivery domain.WebhookDelivery)
+	FindDeliveries(ctx context.Context, tx *sql.Tx, subscriptionId int, limit int) []domain.WebhookDelivery
+}
+
+type WebhookRepositoryImpl struct {
+	Clock helper.Clock
+}
+
+func NewWebhookRepository(clock helper.Clock) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{
+		Clock: clock,
+	}
+}
+
+func (wr *WebhookRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.WebhookSubscription {
+	querySQL := "SELECT id, url, event_types, secret, created_at FROM webhook_subscription WHERE tenant_id = $1 ORDER BY id"
+	rows, err := tx.QueryContext(ctx, querySQL, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var subscriptions []domain.WebhookSubscription
+	for rows.Next() {
+		subscriptions = append(subscriptions, scanWebhookSubscription(rows))
+	}
+	return subscriptions
+}
+
+func (wr *WebhookRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, subscription domain.WebhookSubscription) domain.WebhookSubscription {
+	subscription.CreatedAt = wr.now()
+
+	querySQL := `INSERT INTO webhook_subscription(tenant_id, url, event_types, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := tx.QueryRowContext(ctx, querySQL, helper.TenantIdFromContext(ctx), subscription.Url,
+		pq.Array(subscription.EventTypes), subscription.Secret, subscription.CreatedAt).Scan(&subscription.Id)
+	helper.PanicIfError(err)
+	return subscription
+}
+
+func (wr *WebhookRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, subscriptionId int) (domain.WebhookSubscription, error) {
+	querySQL := "SELECT id, url, event_types, secret, created_at FROM webhook_subscription WHERE id = $1 AND tenant_id = $2"
+	rows, err := tx.QueryContext(ctx, querySQL, subscriptionId, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanWebhookSubscription(rows), nil
+	} else {
+		return domain.WebhookSubscription{}, errors.New("webhook is not found")
+	}
+}
+
+func (wr *WebhookRepositoryImpl) DeleteById(ctx context.Context, tx *sql.Tx, subscription domain.WebhookSubscription) {
+	querySQL := "DELETE FROM webhook_subscription WHERE id = $1 AND tenant_id = $2"
+	_, err := tx.ExecContext(ctx, querySQL, subscription.Id, helper.TenantIdFromContext(ctx))
+	helper.PanicIfError(err)
+}
+
+func (wr *WebhookRepositoryImpl) SaveEvent(ctx context.Context, tx *sql.Tx, eventType string, payload []byte) {
+	querySQL := `INSERT INTO webhook_outbox(tenant_id, subscription_id, event_type, payload, next_attempt_at, created_at)
+		SELECT tenant_id, id, $2, $3, $4, $4 FROM webhook_subscription
+		WHERE tenant_id = $1 AND $2 = ANY(event_types)`
+	_, err := tx.ExecContext(ctx, querySQL, helper.TenantIdFromContext(ctx), eventType, payload, wr.now())
+	helper.PanicIfError(err)
+}
+
+func (wr *WebhookRepositoryImpl) ClaimEvents(ctx context.Context, tx *sql.Tx, limit int, leaseUntil time.Time) []domain.WebhookOutbox {
+	querySQL := `UPDATE webhook_outbox o SET next_attempt_at = $2
+		FROM webhook_subscription s
+		WHERE s.id = o.subscription_id AND o.id IN (
+			SELECT id FROM webhook_outbox WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED)
+		RETURNING o.id, o.tenant_id, o.subscription_id, o.event_type, o.payload, o.status, o.attempts,
+			o.next_attempt_at, o.last_error, o.created_at, s.url, s.secret`
+	rows, err := tx.QueryContext(ctx, querySQL, wr.now(), leaseUntil, limit)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var events []domain.WebhookOutbox
+	for rows.Next() {
+		var event domain.WebhookOutbox
+		err := rows.Scan(&event.Id, &event.TenantId, &event.SubscriptionId, &event.EventType, &event.Payload, &event.Status,
+			&event.Attempts, &event.NextAttemptAt, &event.LastError, &event.CreatedAt, &event.Url, &event.Secret)
+		helper.PanicIfError(err)
+		events = append(events, event)
+	}
+	return events
+}
+
+func (wr *WebhookRepositoryImpl) UpdateEvent(ctx context.Context, tx *sql.Tx, event domain.WebhookOutbox) {
+	querySQL := "UPDATE webhook_outbox SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4 WHERE id = $5"
+	_, err := tx.ExecContext(ctx, querySQL, event.Status, event.Attempts, event.NextAttemptAt, event.LastError, event.Id)
+	helper.PanicIfError(err)
+}
+
+func (wr *WebhookRepositoryImpl) SaveDelivery(ctx context.Context, tx *sql.Tx, delivery domain.WebhookDelivery) {
+	querySQL := `INSERT INTO webhook_delivery(outbox_id, subscription_id, event_type, attempt, status_code, error, duration_ms, outcome, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := tx.ExecContext(ctx, querySQL, delivery.OutboxId, delivery.SubscriptionId, delivery.EventType, delivery.Attempt,
+		delivery.StatusCode, delivery.Error, delivery.Duration.Milliseconds(), delivery.Outcome, delivery.AttemptedAt)
+	helper.PanicIfError(err)
+}
+
+func (wr *WebhookRepositoryImpl) FindDeliveries(ctx context.Context, tx *sql.Tx, subscriptionId int, limit int) []domain.WebhookDelivery {
+	querySQL := `SELECT d.id, d.outbox_id, d.subscription_id, d.event_type, d.attempt, d.status_code, d.error, d.duration_ms, d.outcome, d.attempted_at
+		FROM webhook_delivery d JOIN webhook_subscription s ON s.id = d.subscription_id
+		WHERE d.subscription_id = $1 AND s.tenant_id = $2 ORDER BY d.id DESC LIMIT $3`
+	rows, err := tx.QueryContext(ctx, querySQL, subscriptionId, helper.TenantIdFromContext(ctx), limit)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		var durationMs int64
+		err := rows.Scan(&delivery.Id, &delivery.OutboxId, &delivery.SubscriptionId, &delivery.EventType, &delivery.Attempt,
+			&delivery.StatusCode, &delivery.Error, &durationMs, &delivery.Outcome, &delivery.AttemptedAt)
+		helper.PanicIfError(err)
+		delivery.Duration = time.Duration(durationMs) * time.Millisecond
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (wr *WebhookRepositoryImpl) now() time.Time {
+	return wr.Clock().UTC().Truncate(time.Microsecond)
+}
+
+func scanWebhookSubscription(rows *sql.Rows) domain.WebhookSubscription {
+	var subscription domain.WebhookSubscription
+	err := rows.Scan(&subscription.Id, &subscription.Url, pq.Array(&subscription.EventTypes), &subscription.Secret, &subscription.CreatedAt)
+	helper.PanicIfError(err)
+	return subscription
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"Data-Category/api"
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// attributeDocument validates attributes. It has no components, so attribute
+// schemas cannot refer to the schemas of the API.
+var attributeDocument = &api.Document{}
+
+// attributeSchemaKeywords are the keywords attribute schemas may use: the
+// OpenAPI 3.0 subset api.Schema validates, plus annotations that do not
+// affect validation. Anything else, like allOf, const or minItems, would be
+// ignored silently, so it is refused. $ref is refused by
+// checkAttributeSchema.
+var attributeSchemaKeywords = map[string]bool{
+	"$ref": true, "type": true, "format": true, "nullable": true, "enum": true,
+	"properties": true, "required": true, "additionalProperties": true, "items": true,
+	"minLength": true, "maxLength": true, "pattern": true, "minimum": true, "maximum": true,
+	"title": true, "description": true, "default": true, "example": true,
+}
+
+// parseAttributeSchema checks the attribute_schema of a request. null means
+// no schema and is returned as nil. A schema that is not a JSON Schema
+// object is a 400, one that uses what attribute schemas do not support a 422.
+func parseAttributeSchema(raw json.RawMessage) json.RawMessage {
+	if raw == nil || string(raw) == "null" {
+		return nil
+	}
+	var document interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		panic(exception.NewBadRequestError("attribute_schema must be a JSON Schema object"))
+	}
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		panic(exception.NewBadRequestError("attribute_schema must be a JSON Schema object"))
+	}
+	if message := checkAttributeKeywords(object, ""); message != "" {
+		panic(exception.NewUnprocessableEntityError("attribute_schema " + message))
+	}
+	schema := &api.Schema{}
+	if err := json.Unmarshal(raw, schema); err != nil {
+		panic(exception.NewBadRequestError("attribute_schema must be a JSON Schema object"))
+	}
+	if schema.Type != "object" {
+		panic(exception.NewBadRequestError("attribute_schema must have type object"))
+	}
+	if message := checkAttributeSchema(schema); message != "" {
+		panic(exception.NewBadRequestError("attribute_schema " + message))
+	}
+	return raw
+}
+
+// checkAttributeKeywords returns why the schema object at path uses what
+// attribute schemas do not support, or "" when it does not.
+func checkAttributeKeywords(schema map[string]interface{}, path string) string {
+	for _, keyword := range sortedNames(schema) {
+		if !attributeSchemaKeywords[keyword] {
+			return path + keyword + " is not supported"
+		}
+	}
+	if _, ok := schema["type"].([]interface{}); ok {
+		return path + "type must be a single type, with nullable for null"
+	}
+	if additionalProperties, ok := schema["additionalProperties"]; ok {
+		if _, ok := additionalProperties.(bool); !ok {
+			return path + "additionalProperties must be a boolean"
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedNames(properties) {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				if message := checkAttributeKeywords(property, path+"properties."+name+"."); message != "" {
+					return message
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		return checkAttributeKeywords(items, path+"items.")
+	}
+	return ""
+}
+
+// sortedNames returns the keys of object in order, so the first problem of
+// a schema is reported the same way every time.
+func sortedNames(object map[string]interface{}) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func checkAttributeSchema(schema *api.Schema) string {
+	if schema.Ref != "" {
+		return "must not use $ref"
+	}
+	if schema.Pattern != "" {
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			return "has an invalid pattern " + schema.Pattern
+		}
+	}
+	for _, property := range schema.Properties {
+		if message := checkAttributeSchema(property); message != "" {
+			return message
+		}
+	}
+	if schema.Items != nil {
+		return checkAttributeSchema(schema.Items)
+	}
+	return ""
+}
+
+// attributeSchemas returns the schemas of categoryId and its ancestors, root
+// first. A nil categoryId has none.
+func (cs *CategoryServiceImpl) attributeSchemas(ctx context.Context, tx *sql.Tx, categoryId *int) []*api.Schema {
+	var schemas []*api.Schema
+	for categoryId != nil {
+		category, err := cs.CategoryRepository.FindById(ctx, tx, *categoryId)
+		helper.PanicIfError(err)
+		if schema := decodeAttributeSchema(category.AttributeSchema); schema != nil {
+			schemas = append([]*api.Schema{schema}, schemas...)
+		}
+		categoryId = category.ParentId
+	}
+	return schemas
+}
+
+// validateAttributes panics with 422 unless the attributes of category match
+// every schema it inherits and its own.
+func validateAttributes(category domain.Category, schemas []*api.Schema) {
+	if message := attributesError(category, schemas); message != "" {
+		panic(exception.NewUnprocessableEntityError(message))
+	}
+}
+
+func attributesError(category domain.Category, schemas []*api.Schema) string {
+	var attributes interface{} = map[string]interface{}{}
+	if category.Attributes != nil {
+		attributes = category.Attributes
+	}
+	if schema := decodeAttributeSchema(category.AttributeSchema); schema != nil {
+		schemas = append(schemas[:len(schemas):len(schemas)], schema)
+	}
+	for _, schema := range schemas {
+		err := attributeDocument.Validate(schema, attributes)
+		if validationError, ok := err.(*api.ValidationError); ok {
+			path := "attributes"
+			if validationError.Path != "" {
+				path += "." + validationError.Path
+			}
+			return path + ": " + validationError.Message
+		}
+		helper.PanicIfError(err)
+	}
+	return ""
+}
+
+// validateSubtree validates the attributes of categoryId and its descendants
+// after their schemas may have changed.
+func (cs *CategoryServiceImpl) validateSubtree(ctx context.Context, tx *sql.Tx, categoryId int) {
+	subtree := cs.CategoryRepository.FindSubtree(ctx, tx, categoryId)
+	if len(subtree) == 0 {
+		return
+	}
+
+	// The subtree comes depth first, so parents are seen before children.
+	inherited := map[int][]*api.Schema{}
+	for _, category := range subtree {
+		var schemas []*api.Schema
+		if category.Id == categoryId {
+			schemas = cs.attributeSchemas(ctx, tx, category.ParentId)
+		} else {
+			schemas = inherited[*category.ParentId]
+		}
+		if message := attributesError(category, schemas); message != "" {
+			if category.Id != categoryId {
+				message = "descendant " + strconv.Itoa(category.Id) + " " + message
+			}
+			panic(exception.NewUnprocessableEntityError(message))
+		}
+
+		if schema := decodeAttributeSchema(category.AttributeSchema); schema != nil {
+			schemas = append(schemas[:len(schemas):len(schemas)], schema)
+		}
+		inherited[category.Id] = schemas
+	}
+}
+
+func decodeAttributeSchema(raw json.RawMessage) *api.Schema {
+	if raw == nil {
+		return nil
+	}
+	schema := &api.Schema{}
+	err := json.Unmarshal(raw, schema)
+	helper.PanicIfError(err)
+	return schema
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"Data-Category/model/domain"
+	"sync"
+)
+
+const categoryChangeBufferSize = 64
+
+// CategoryChangeBroker fans committed category changes out to the
+// subscribers of the same tenant. A subscriber that falls behind by more than
+// its buffer is dropped by closing its channel; it is expected to resume from
+// the change feed.
+type CategoryChangeBroker struct {
+	mutex       sync.Mutex
+	subscribers map[int]map[chan domain.CategoryChange]struct{}
+}
+
+func NewCategoryChangeBroker() *CategoryChangeBroker {
+	return &CategoryChangeBroker{
+		subscribers: map[int]map[chan domain.CategoryChange]struct{}{},
+	}
+}
+
+func (cb *CategoryChangeBroker) Subscribe(tenantId int) (<-chan domain.CategoryChange, func()) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	ch := make(chan domain.CategoryChange, categoryChangeBufferSize)
+	if cb.subscribers[tenantId] == nil {
+		cb.subscribers[tenantId] = map[chan domain.CategoryChange]struct{}{}
+	}
+	cb.subscribers[tenantId][ch] = struct{}{}
+
+	return ch, func() {
+		cb.mutex.Lock()
+		defer cb.mutex.Unlock()
+
+		cb.remove(tenantId, ch)
+	}
+}
+
+func (cb *CategoryChangeBroker) Publish(change domain.CategoryChange) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	for ch := range cb.subscribers[change.TenantId] {
+		select {
+		case ch <- change:
+		default:
+			cb.remove(change.TenantId, ch)
+		}
+	}
+}
+
+func (cb *CategoryChangeBroker) remove(tenantId int, ch chan domain.CategoryChange) {
+	if _, ok := cb.subscribers[tenantId][ch]; !ok {
+		return
+	}
+	delete(cb.subscribers[tenantId], ch)
+	if len(cb.subscribers[tenantId]) == 0 {
+		delete(cb.subscribers, tenantId)
+	}
+	close(ch)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type CategoryDeleteConfig struct {
+	// TokenSecret signs the confirmation tokens. Every instance behind the
+	// same load balancer needs the same secret.
+	TokenSecret []byte
+	TokenTTL    time.Duration
+}
+
+// CategoryDeleteGuard decides which tenant a bulk delete may target and
+// issues and checks its confirmation tokens. A token is bound to the tenant,
+// the filter and the change feed sequence of the dry run, so it stops
+// working as soon as any category of the tenant changes.
+type CategoryDeleteGuard struct {
+	TenantRepository repository.TenantRepository
+	Clock            helper.Clock
+	Config           CategoryDeleteConfig
+}
+
+func NewCategoryDeleteGuard(tenantRepository repository.TenantRepository, clock helper.Clock, config CategoryDeleteConfig) *CategoryDeleteGuard {
+	return &CategoryDeleteGuard{
+		TenantRepository: tenantRepository,
+		Clock:            clock,
+		Config:           config,
+	}
+}
+
+// CategoryDeleteClaims is what a confirmation token vouches for.
+type CategoryDeleteClaims struct {
+	TenantId  int    `json:"tenant_id"`
+	SubtreeId int    `json:"subtree_id"`
+	Name      string `json:"name"`
+	Sequence  int64  `json:"sequence"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// TenantContext returns ctx scoped to tenantId, which is the caller's own
+// tenant when zero. Only the admin can target other tenants.
+func (dg *CategoryDeleteGuard) TenantContext(ctx context.Context, tx *sql.Tx, tenantId int) context.Context {
+	principal := helper.MustPrincipalFromContext(ctx)
+	if tenantId == 0 || tenantId == principal.TenantId {
+		return ctx
+	}
+	if !principal.Admin {
+		panic(exception.NewForbiddenError("only the admin can delete the categories of other tenants"))
+	}
+	if _, err := dg.TenantRepository.FindById(ctx, tx, tenantId); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	principal.TenantId = tenantId
+	return helper.WithPrincipal(ctx, principal)
+}
+
+// Issue signs claims and returns the token with its expiry.
+func (dg *CategoryDeleteGuard) Issue(claims CategoryDeleteClaims) (string, time.Time) {
+	expiresAt := dg.Clock().Add(dg.Config.TokenTTL).UTC().Truncate(time.Second)
+	claims.ExpiresAt = expiresAt.Unix()
+
+	data, err := json.Marshal(claims)
+	helper.PanicIfError(err)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + dg.sign(payload), expiresAt
+}
+
+// Check panics with 422 unless token is a valid, unexpired token for the
+// tenant and filter of claims, and with 409 when the categories changed
+// since the token was issued.
+func (dg *CategoryDeleteGuard) Check(token string, claims CategoryDeleteClaims) {
+	payload, signature, ok := cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(dg.sign(payload))) {
+		panic(exception.NewUnprocessableEntityError("confirmation_token is invalid"))
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		panic(exception.NewUnprocessableEntityError("confirmation_token is invalid"))
+	}
+	var issued CategoryDeleteClaims
+	if err := json.Unmarshal(data, &issued); err != nil {
+		panic(exception.NewUnprocessableEntityError("confirmation_token is invalid"))
+	}
+
+	if !dg.Clock().Before(time.Unix(issued.ExpiresAt, 0)) {
+		panic(exception.NewUnprocessableEntityError("confirmation_token has expired"))
+	}
+	if issued.TenantId != claims.TenantId || issued.SubtreeId != claims.SubtreeId || issued.Name != claims.Name {
+		panic(exception.NewUnprocessableEntityError("confirmation_token was issued for another filter"))
+	}
+	if issued.Sequence != claims.Sequence {
+		panic(exception.NewAbortedError("categories changed since the dry run"))
+	}
+}
+
+func (dg *CategoryDeleteGuard) sign(payload string) string {
+	mac := hmac.New(sha256.New, dg.Config.TokenSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// cut is strings.Cut, which needs Go 1.18.
+func cut(s string, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
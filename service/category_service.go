@@ -1,6 +1,7 @@
 package service
 
 import (
+	"Data-Category/event"
 	"Data-Category/exception"
 	"Data-Category/helper"
 	"Data-Category/model/domain"
@@ -8,56 +9,205 @@ import (
 	"Data-Category/repository"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 )
 
+var webhookEventTypes = map[string]string{
+	domain.CategoryCreated: domain.WebhookCategoryCreated,
+	domain.CategoryUpdated: domain.WebhookCategoryUpdated,
+	domain.CategoryDeleted: domain.WebhookCategoryDeleted,
+	domain.CategoryMerged:  domain.WebhookCategoryMerged,
+}
+
+// CategoryService reads names in the locales of helper.LocalesFromContext,
+// falling back to the untranslated name. Writes use the untranslated name.
 type CategoryService interface {
 	Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse
-	FindAll(ctx context.Context) []web.CategoryResponse
-	DeleteAll(ctx context.Context)
+	FindAll(ctx context.Context, request web.CategoryFindAllRequest) []web.CategoryResponse
+	// DeleteAll deletes the categories request selects, with their
+	// descendants, in two steps: a dry run counts them and issues a
+	// confirmation token, which the delete has to present.
+	DeleteAll(ctx context.Context, request web.CategoryDeleteAllRequest) web.CategoryDeleteAllResponse
 	UpdateById(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse
 	FindById(ctx context.Context, categoryId int) web.CategoryResponse
+	// FindByIds returns the categories with the given ids in one query,
+	// leaving out ids that do not exist.
+	FindByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse
+	FindBySlug(ctx context.Context, slug string) web.CategorySlugResponse
 	DeleteById(ctx context.Context, categoryId int)
+	// Merge moves the children, aliases and items of the source category to
+	// the target, keeps the source name and slugs resolving to the target and
+	// soft-deletes the source.
+	Merge(ctx context.Context, request web.CategoryMergeRequest) web.CategoryResponse
+	// Move makes the category a child of request.ParentId, or a root when it
+	// is nil, at request.Position among its new siblings.
+	Move(ctx context.Context, request web.CategoryMoveRequest) web.CategoryResponse
+	// Reorder sets the order of all children of request.ParentId at once, or
+	// of the root categories when it is nil.
+	Reorder(ctx context.Context, request web.CategoryReorderRequest) []web.CategoryResponse
+	FindChildren(ctx context.Context, categoryId int) []web.CategoryResponse
+	// FindChildrenByIds returns the children of all of categoryIds in one
+	// query, grouped by parent and by position within a group.
+	FindChildrenByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse
+	// FindSubtree returns the category and its descendants depth first.
+	FindSubtree(ctx context.Context, categoryId int) []web.CategoryResponse
+	// Search ranks the categories of the caller's tenant, or of another
+	// tenant for the admin, by how well they match request.Query.
+	Search(ctx context.Context, request web.CategorySearchRequest) []web.CategorySearchResponse
+	// Suggest completes a name prefix from CategorySuggestIndex without
+	// querying the database.
+	Suggest(ctx context.Context, request web.CategorySuggestRequest) []web.CategorySuggestResponse
+	FindAliases(ctx context.Context, categoryId int) []web.CategoryAliasResponse
+	// FindAliasesByIds returns the aliases of all of categoryIds in one query.
+	FindAliasesByIds(ctx context.Context, categoryIds []int) []web.CategoryAliasResponse
+	CreateAlias(ctx context.Context, request web.CategoryAliasCreateRequest) web.CategoryAliasResponse
+	DeleteAlias(ctx context.Context, categoryId int, aliasId int)
+	FindTranslations(ctx context.Context, categoryId int) []web.CategoryTranslationResponse
+	// SetTranslation adds the name of the category in request.Locale or
+	// replaces it.
+	SetTranslation(ctx context.Context, request web.CategoryTranslationRequest) web.CategoryTranslationResponse
+	DeleteTranslation(ctx context.Context, categoryId int, locale string)
+	FindChanges(ctx context.Context, request web.CategoryChangesRequest) web.CategoryChangesResponse
+	// FindLatestChange returns the newest change of the caller's tenant, or a
+	// change with sequence 0 when nothing has changed yet.
+	FindLatestChange(ctx context.Context) web.CategoryChangeResponse
+	// Subscribe streams the changes of the caller's tenant as they are
+	// committed until the returned function is called.
+	Subscribe(ctx context.Context) (<-chan web.CategoryChangeResponse, func())
+}
+
+// CategoryToucher records categories as updated after a write outside of
+// CategoryService changed what they carry, like their item counts. It is
+// called within tx, after taking the lock of CategoryChangeRepository.
+type CategoryToucher interface {
+	TouchCategories(ctx context.Context, tx *sql.Tx, categoryIds []int)
+}
+
+// CategoryInvalidator drops cached categories after such a write.
+type CategoryInvalidator interface {
+	Invalidate(ctx context.Context, categoryIds ...int)
 }
 
+// CategoryServiceImpl records every write in the change feed of
+// CategoryChangeRepository within the same transaction. Writers take the
+// feed's lock first, which serializes the writes of a tenant. Once committed,
+// the changes are published to CategoryChangeBroker. Webhook events are
+// queued in the outbox of WebhookRepository in the same transaction, so they
+// are sent if and only if the change commits. Domain events are published
+// to EventBus after the commit.
 type CategoryServiceImpl struct {
-	CategoryRepository repository.CategoryRepository
-	DB                 *sql.DB
-	Validate           *validator.Validate
+	CategoryRepository            repository.CategoryRepository
+	CategoryAliasRepository       repository.CategoryAliasRepository
+	CategoryTranslationRepository repository.CategoryTranslationRepository
+	ItemRepository                repository.ItemRepository
+	CategoryChangeRepository      repository.CategoryChangeRepository
+	WebhookRepository             repository.WebhookRepository
+	CategoryChangeBroker          *CategoryChangeBroker
+	EventBus                      event.EventBus
+	SuggestIndex                  *CategorySuggestIndex
+	DeleteGuard                   *CategoryDeleteGuard
+	DB                            *sql.DB
+	Validate                      *validator.Validate
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository, db *sql.DB, validate *validator.Validate) *CategoryServiceImpl {
+func NewCategoryService(categoryRepository repository.CategoryRepository, categoryAliasRepository repository.CategoryAliasRepository, categoryTranslationRepository repository.CategoryTranslationRepository, itemRepository repository.ItemRepository, categoryChangeRepository repository.CategoryChangeRepository, webhookRepository repository.WebhookRepository, categoryChangeBroker *CategoryChangeBroker, eventBus event.EventBus, suggestIndex *CategorySuggestIndex, deleteGuard *CategoryDeleteGuard, db *sql.DB, validate *validator.Validate) *CategoryServiceImpl {
 	return &CategoryServiceImpl{
-		CategoryRepository: categoryRepository,
-		DB:                 db,
-		Validate:           validate,
+		CategoryRepository:            categoryRepository,
+		CategoryAliasRepository:       categoryAliasRepository,
+		CategoryTranslationRepository: categoryTranslationRepository,
+		ItemRepository:                itemRepository,
+		CategoryChangeRepository:      categoryChangeRepository,
+		WebhookRepository:             webhookRepository,
+		CategoryChangeBroker:          categoryChangeBroker,
+		EventBus:                      eventBus,
+		SuggestIndex:                  suggestIndex,
+		DeleteGuard:                   deleteGuard,
+		DB:                            db,
+		Validate:                      validate,
 	}
 }
 
 func (cs *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse {
 	err := cs.Validate.Struct(request)
 	helper.PanicIfError(err)
+	attributeSchema := parseAttributeSchema(request.AttributeSchema)
 
 	tx, err := cs.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	if _, err := cs.CategoryRepository.FindByName(ctx, tx, request.Name); err == nil {
+		panic(exception.NewConflictError("category name is already used"))
+	}
+	if _, err := cs.CategoryAliasRepository.FindByAlias(ctx, tx, request.Name); err == nil {
+		panic(exception.NewConflictError("category name is already used as an alias"))
+	}
+
+	slug := request.Slug
+	if slug == "" {
+		slug = cs.uniqueSlug(ctx, tx, helper.Slugify(request.Name), 0)
+	} else if cs.slugTaken(ctx, tx, slug, 0) {
+		panic(exception.NewConflictError("category slug is already used"))
+	}
+
+	if request.ParentId != nil {
+		if _, err := cs.CategoryRepository.FindById(ctx, tx, *request.ParentId); err != nil {
+			panic(exception.NewBadRequestError("parent category is not found"))
+		}
+	}
+
 	category := domain.Category{
-		Name: request.Name,
+		Name:            request.Name,
+		Slug:            slug,
+		ParentId:        request.ParentId,
+		Attributes:      request.Attributes,
+		AttributeSchema: attributeSchema,
 	}
+	validateAttributes(category, cs.attributeSchemas(ctx, tx, category.ParentId))
 
 	category = cs.CategoryRepository.Save(ctx, tx, category)
+	for _, locale := range sortedKeys(request.Translations) {
+		canonical, _ := helper.CanonicalLocale(locale)
+		cs.CategoryTranslationRepository.Save(ctx, tx, domain.CategoryTranslation{
+			CategoryId: category.Id,
+			Locale:     canonical,
+			Name:       request.Translations[locale],
+		})
+	}
+	for _, alias := range request.Aliases {
+		cs.saveAlias(ctx, tx, category, alias)
+	}
+	change := cs.recordChange(ctx, tx, domain.CategoryCreated, category)
+	cs.publish(ctx, tx, event.CategoryCreated{
+		TenantId:   change.TenantId,
+		Category:   category,
+		OccurredAt: change.ChangedAt,
+		Actor:      change.ChangedBy,
+	})
 
-	return (web.CategoryResponse)(category)
+	return (web.CategoryResponse)(change.Category)
 }
 
-func (cs *CategoryServiceImpl) FindAll(ctx context.Context) []web.CategoryResponse {
+func (cs *CategoryServiceImpl) FindAll(ctx context.Context, request web.CategoryFindAllRequest) []web.CategoryResponse {
 	tx, err := cs.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	categories := cs.CategoryRepository.FindAll(ctx, tx)
+	filter := domain.CategoryFilter{
+		UpdatedSince: request.UpdatedSince,
+		Attributes:   request.Attributes,
+		AfterId:      request.AfterId,
+		Limit:        request.Limit,
+	}
+
+	categories := cs.decorate(ctx, tx, cs.CategoryRepository.FindAll(ctx, tx, filter))
 
 	var categoriesResponse []web.CategoryResponse
 	for _, category := range categories {
@@ -66,32 +216,174 @@ func (cs *CategoryServiceImpl) FindAll(ctx context.Context) []web.CategoryRespon
 	return categoriesResponse
 }
 
-func (cs *CategoryServiceImpl) DeleteAll(ctx context.Context) {
+func (cs *CategoryServiceImpl) DeleteAll(ctx context.Context, request web.CategoryDeleteAllRequest) web.CategoryDeleteAllResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+	if !request.DryRun && request.ConfirmationToken == "" {
+		panic(exception.NewBadRequestError("confirmation_token is required, request one with dry_run=true"))
+	}
+
 	tx, err := cs.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	cs.CategoryRepository.DeleteAll(ctx, tx)
+	ctx = cs.DeleteGuard.TenantContext(ctx, tx, request.TenantId)
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	filter := domain.CategoryDeleteFilter{
+		SubtreeId:   request.SubtreeId,
+		NamePattern: request.Name,
+	}
+	claims := CategoryDeleteClaims{
+		TenantId:  helper.TenantIdFromContext(ctx),
+		SubtreeId: request.SubtreeId,
+		Name:      request.Name,
+	}
+	if latest, err := cs.CategoryChangeRepository.FindLatest(ctx, tx); err == nil {
+		claims.Sequence = latest.Sequence
+	}
+
+	if request.DryRun {
+		token, expiresAt := cs.DeleteGuard.Issue(claims)
+		return web.CategoryDeleteAllResponse{
+			DryRun:            true,
+			Count:             cs.CategoryRepository.CountAll(ctx, tx, filter),
+			ConfirmationToken: token,
+			ExpiresAt:         &expiresAt,
+		}
+	}
+	cs.DeleteGuard.Check(request.ConfirmationToken, claims)
+
+	categories := cs.CategoryRepository.DeleteAll(ctx, tx, filter)
+	var change domain.CategoryChange
+	for _, category := range categories {
+		change = cs.recordChange(ctx, tx, domain.CategoryDeleted, category)
+	}
+
+	if filter == (domain.CategoryDeleteFilter{}) {
+		if len(categories) > 0 {
+			cs.publish(ctx, tx, event.CategoriesPurged{
+				TenantId:   change.TenantId,
+				Categories: categories,
+				OccurredAt: change.ChangedAt,
+				Actor:      change.ChangedBy,
+			})
+		}
+		return web.CategoryDeleteAllResponse{Count: len(categories)}
+	}
+
+	// A filtered delete leaves the rest of the tree in place, so it closes
+	// the gaps among the surviving siblings and is published category by
+	// category rather than as a purge of the tenant.
+	deleted := map[int]bool{}
+	for _, category := range categories {
+		deleted[category.Id] = true
+	}
+	compacted := map[int]bool{}
+	for _, category := range categories {
+		if category.ParentId != nil && deleted[*category.ParentId] {
+			continue
+		}
+		parentKey := 0
+		if category.ParentId != nil {
+			parentKey = *category.ParentId
+		}
+		if !compacted[parentKey] {
+			cs.compactSiblings(ctx, tx, category.ParentId)
+			compacted[parentKey] = true
+		}
+	}
+	for _, category := range categories {
+		cs.publish(ctx, tx, event.CategoryDeleted{
+			TenantId:   change.TenantId,
+			Category:   category,
+			OccurredAt: change.ChangedAt,
+			Actor:      change.ChangedBy,
+		})
+	}
+	return web.CategoryDeleteAllResponse{Count: len(categories)}
 }
 
 func (cs *CategoryServiceImpl) UpdateById(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse {
 	err := cs.Validate.Struct(request)
 	helper.PanicIfError(err)
+	attributeSchema := parseAttributeSchema(request.AttributeSchema)
 
 	tx, err := cs.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
 	category, err := cs.CategoryRepository.FindById(ctx, tx, request.Id)
 	if err != nil {
 		panic(exception.NewNotFoundError(err.Error()))
 	}
 
+	if existing, err := cs.CategoryRepository.FindByName(ctx, tx, request.Name); err == nil && existing.Id != category.Id {
+		panic(exception.NewConflictError("category name is already used"))
+	}
+	// The category can be renamed to one of its own aliases, which then
+	// becomes its name instead.
+	alias, err := cs.CategoryAliasRepository.FindByAlias(ctx, tx, request.Name)
+	ownAlias := err == nil && alias.CategoryId == category.Id
+	if err == nil && !ownAlias {
+		panic(exception.NewConflictError("category name is already used as an alias"))
+	}
+
+	slug := category.Slug
+	if request.Slug != "" {
+		if cs.slugTaken(ctx, tx, request.Slug, category.Id) {
+			panic(exception.NewConflictError("category slug is already used"))
+		}
+		slug = request.Slug
+	} else if request.Name != category.Name {
+		slug = cs.uniqueSlug(ctx, tx, helper.Slugify(request.Name), category.Id)
+	}
+
+	if slug != category.Slug {
+		// The old slug keeps resolving to this category so existing links can be redirected.
+		cs.CategoryRepository.SaveSlugHistory(ctx, tx, category, category.Slug)
+		cs.CategoryRepository.DeleteSlugHistory(ctx, tx, slug)
+	}
+	if ownAlias {
+		cs.CategoryAliasRepository.DeleteById(ctx, tx, alias)
+		if alias.Slug != slug {
+			// Like any old slug, the slug of the alias keeps resolving.
+			cs.CategoryRepository.SaveSlugHistory(ctx, tx, category, alias.Slug)
+		}
+	}
+
+	oldName, oldSlug := category.Name, category.Slug
 	category.Name = request.Name
+	category.Slug = slug
+	if request.Attributes != nil {
+		category.Attributes = request.Attributes
+	}
+	if request.AttributeSchema != nil {
+		category.AttributeSchema = attributeSchema
+	}
 
 	category = cs.CategoryRepository.UpdateById(ctx, tx, category)
+	if request.AttributeSchema != nil {
+		// A new schema also applies to the descendants.
+		cs.validateSubtree(ctx, tx, category.Id)
+	} else if request.Attributes != nil {
+		validateAttributes(category, cs.attributeSchemas(ctx, tx, category.ParentId))
+	}
+	change := cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+	if category.Name != oldName || category.Slug != oldSlug {
+		cs.publish(ctx, tx, event.CategoryRenamed{
+			TenantId:   change.TenantId,
+			Category:   category,
+			OldName:    oldName,
+			OldSlug:    oldSlug,
+			OccurredAt: change.ChangedAt,
+			Actor:      change.ChangedBy,
+		})
+	}
 
-	return (web.CategoryResponse)(category)
+	return (web.CategoryResponse)(change.Category)
 }
 
 func (cs *CategoryServiceImpl) FindById(ctx context.Context, categoryId int) web.CategoryResponse {
@@ -104,7 +396,48 @@ func (cs *CategoryServiceImpl) FindById(ctx context.Context, categoryId int) web
 		panic(exception.NewNotFoundError(err.Error()))
 	}
 
-	return (web.CategoryResponse)(category)
+	return (web.CategoryResponse)(cs.decorate(ctx, tx, []domain.Category{category})[0])
+}
+
+func (cs *CategoryServiceImpl) FindByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	categories := cs.decorate(ctx, tx, cs.CategoryRepository.FindAllByIds(ctx, tx, categoryIds))
+
+	var categoriesResponse []web.CategoryResponse
+	for _, category := range categories {
+		categoriesResponse = append(categoriesResponse, (web.CategoryResponse)(category))
+	}
+	return categoriesResponse
+}
+
+func (cs *CategoryServiceImpl) FindBySlug(ctx context.Context, slug string) web.CategorySlugResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if category, err := cs.CategoryRepository.FindBySlug(ctx, tx, slug); err == nil {
+		return web.CategorySlugResponse{
+			Category: (web.CategoryResponse)(cs.decorate(ctx, tx, []domain.Category{category})[0]),
+		}
+	}
+
+	category, err := cs.CategoryRepository.FindBySlugHistory(ctx, tx, slug)
+	if err != nil {
+		alias, aliasErr := cs.CategoryAliasRepository.FindBySlug(ctx, tx, slug)
+		if aliasErr != nil {
+			panic(exception.NewNotFoundError(err.Error()))
+		}
+		category, err = cs.CategoryRepository.FindById(ctx, tx, alias.CategoryId)
+		helper.PanicIfError(err)
+	}
+
+	return web.CategorySlugResponse{
+		Category: (web.CategoryResponse)(cs.decorate(ctx, tx, []domain.Category{category})[0]),
+		Moved:    true,
+	}
 }
 
 func (cs *CategoryServiceImpl) DeleteById(ctx context.Context, categoryId int) {
@@ -112,10 +445,698 @@ func (cs *CategoryServiceImpl) DeleteById(ctx context.Context, categoryId int) {
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
 	category, err := cs.CategoryRepository.FindById(ctx, tx, categoryId)
 	if err != nil {
 		panic(exception.NewNotFoundError(err.Error()))
 	}
 
+	if cs.CategoryRepository.HasChildren(ctx, tx, category) {
+		panic(exception.NewFailedPreconditionError("category has child categories"))
+	}
+
 	cs.CategoryRepository.DeleteById(ctx, tx, category)
+	change := cs.recordChange(ctx, tx, domain.CategoryDeleted, category)
+	cs.compactSiblings(ctx, tx, category.ParentId)
+	cs.publish(ctx, tx, event.CategoryDeleted{
+		TenantId:   change.TenantId,
+		Category:   category,
+		OccurredAt: change.ChangedAt,
+		Actor:      change.ChangedBy,
+	})
+}
+
+func (cs *CategoryServiceImpl) Merge(ctx context.Context, request web.CategoryMergeRequest) web.CategoryResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if request.SourceId == request.TargetId {
+		panic(exception.NewBadRequestError("a category cannot be merged into itself"))
+	}
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	target, err := cs.CategoryRepository.FindById(ctx, tx, request.TargetId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	// Categories of other tenants are not found, so they cannot be merged.
+	source, err := cs.CategoryRepository.FindById(ctx, tx, request.SourceId)
+	if err != nil {
+		panic(exception.NewBadRequestError("source category is not found"))
+	}
+	if cs.CategoryRepository.InSubtree(ctx, tx, source.Id, target.Id) {
+		panic(exception.NewUnprocessableEntityError("a category cannot be merged into its own descendant"))
+	}
+
+	for _, child := range cs.CategoryRepository.MoveChildren(ctx, tx, source, target) {
+		cs.recordChange(ctx, tx, domain.CategoryUpdated, child)
+		cs.validateSubtree(ctx, tx, child.Id)
+	}
+	cs.CategoryAliasRepository.MoveAll(ctx, tx, source.Id, target.Id)
+	cs.ItemRepository.MoveAssignments(ctx, tx, source, target)
+	cs.CategoryRepository.SoftDeleteById(ctx, tx, source)
+	cs.compactSiblings(ctx, tx, source.ParentId)
+
+	cs.CategoryRepository.MoveSlugHistory(ctx, tx, source, target)
+	cs.CategoryRepository.SaveSlugHistory(ctx, tx, target, source.Slug)
+	if !cs.CategoryAliasRepository.NameTaken(ctx, tx, source.Name) {
+		cs.CategoryAliasRepository.Save(ctx, tx, domain.CategoryAlias{
+			CategoryId: target.Id,
+			Alias:      source.Name,
+			Slug:       source.Slug,
+		})
+	}
+
+	// The target gained the aliases, items and children of the source.
+	targetChange := cs.recordChange(ctx, tx, domain.CategoryUpdated, target)
+	change := cs.emitChange(ctx, tx, cs.CategoryChangeRepository.SaveMerge(ctx, tx, source, target.Id))
+	cs.publish(ctx, tx, event.CategoryMerged{
+		TenantId:   change.TenantId,
+		Source:     source,
+		Target:     target,
+		OccurredAt: change.ChangedAt,
+		Actor:      change.ChangedBy,
+	})
+
+	return (web.CategoryResponse)(targetChange.Category)
+}
+
+func (cs *CategoryServiceImpl) Move(ctx context.Context, request web.CategoryMoveRequest) web.CategoryResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	category, err := cs.CategoryRepository.FindById(ctx, tx, request.Id)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	if request.ParentId != nil {
+		if _, err := cs.CategoryRepository.FindById(ctx, tx, *request.ParentId); err != nil {
+			panic(exception.NewBadRequestError("parent category is not found"))
+		}
+		if cs.CategoryRepository.InSubtree(ctx, tx, category.Id, *request.ParentId) {
+			panic(exception.NewUnprocessableEntityError("a category cannot be moved below itself"))
+		}
+	}
+
+	var categoryIds []int
+	for _, sibling := range cs.CategoryRepository.FindChildren(ctx, tx, request.ParentId) {
+		if sibling.Id != category.Id {
+			categoryIds = append(categoryIds, sibling.Id)
+		}
+	}
+	// A position past the last sibling moves the category to the end.
+	position := request.Position
+	if position > len(categoryIds) {
+		position = len(categoryIds)
+	}
+	categoryIds = append(categoryIds[:position], append([]int{category.Id}, categoryIds[position:]...)...)
+
+	cs.reorder(ctx, tx, request.ParentId, categoryIds)
+	if !sameParent(category.ParentId, request.ParentId) {
+		cs.compactSiblings(ctx, tx, category.ParentId)
+		// The subtree inherits the schemas of its new ancestors.
+		cs.validateSubtree(ctx, tx, category.Id)
+	}
+
+	category, err = cs.CategoryRepository.FindById(ctx, tx, category.Id)
+	helper.PanicIfError(err)
+	return (web.CategoryResponse)(cs.details(ctx, tx, []domain.Category{category})[0])
+}
+
+// Reorder takes the new order of every child of the parent, so a client
+// working from a stale list is refused instead of losing a category.
+func (cs *CategoryServiceImpl) Reorder(ctx context.Context, request web.CategoryReorderRequest) []web.CategoryResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	if request.ParentId != nil {
+		if _, err := cs.CategoryRepository.FindById(ctx, tx, *request.ParentId); err != nil {
+			panic(exception.NewNotFoundError(err.Error()))
+		}
+	}
+
+	children := cs.CategoryRepository.FindChildren(ctx, tx, request.ParentId)
+	listed := map[int]bool{}
+	for _, categoryId := range request.CategoryIds {
+		listed[categoryId] = true
+	}
+	if len(listed) != len(request.CategoryIds) || len(listed) != len(children) {
+		panic(exception.NewUnprocessableEntityError("category_ids must list every child exactly once"))
+	}
+	for _, child := range children {
+		if !listed[child.Id] {
+			panic(exception.NewUnprocessableEntityError("category_ids must list every child exactly once"))
+		}
+	}
+
+	cs.reorder(ctx, tx, request.ParentId, request.CategoryIds)
+
+	categoriesResponse := []web.CategoryResponse{}
+	for _, category := range cs.details(ctx, tx, cs.CategoryRepository.FindChildren(ctx, tx, request.ParentId)) {
+		categoriesResponse = append(categoriesResponse, (web.CategoryResponse)(category))
+	}
+	return categoriesResponse
+}
+
+func (cs *CategoryServiceImpl) FindChildren(ctx context.Context, categoryId int) []web.CategoryResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := cs.CategoryRepository.FindById(ctx, tx, categoryId); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	categoriesResponse := []web.CategoryResponse{}
+	for _, category := range cs.decorate(ctx, tx, cs.CategoryRepository.FindChildren(ctx, tx, &categoryId)) {
+		categoriesResponse = append(categoriesResponse, (web.CategoryResponse)(category))
+	}
+	return categoriesResponse
+}
+
+func (cs *CategoryServiceImpl) FindChildrenByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	categoriesResponse := []web.CategoryResponse{}
+	for _, category := range cs.decorate(ctx, tx, cs.CategoryRepository.FindAllByParentIds(ctx, tx, categoryIds)) {
+		categoriesResponse = append(categoriesResponse, (web.CategoryResponse)(category))
+	}
+	return categoriesResponse
+}
+
+func (cs *CategoryServiceImpl) FindSubtree(ctx context.Context, categoryId int) []web.CategoryResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	categories := cs.CategoryRepository.FindSubtree(ctx, tx, categoryId)
+	if len(categories) == 0 {
+		panic(exception.NewNotFoundError("category is not found"))
+	}
+	categories = cs.decorate(ctx, tx, categories)
+
+	categoriesResponse := []web.CategoryResponse{}
+	for _, category := range categories {
+		categoriesResponse = append(categoriesResponse, (web.CategoryResponse)(category))
+	}
+	return categoriesResponse
+}
+
+func (cs *CategoryServiceImpl) Search(ctx context.Context, request web.CategorySearchRequest) []web.CategorySearchResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	principal := helper.MustPrincipalFromContext(ctx)
+	tenantId := principal.TenantId
+	if request.TenantId != 0 && request.TenantId != tenantId {
+		if !principal.Admin {
+			panic(exception.NewForbiddenError("only the admin can search other tenants"))
+		}
+		tenantId = request.TenantId
+	}
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	results := cs.CategoryRepository.Search(ctx, tx, domain.CategorySearch{
+		TenantId:  tenantId,
+		Query:     request.Query,
+		SubtreeId: request.SubtreeId,
+		Limit:     request.Limit,
+	})
+
+	// Search may read another tenant, whose translations and items are not
+	// resolved.
+	if tenantId == principal.TenantId {
+		categories := make([]domain.Category, 0, len(results))
+		for _, result := range results {
+			categories = append(categories, result.Category)
+		}
+		for i, category := range cs.decorate(ctx, tx, categories) {
+			results[i].Category = category
+		}
+	}
+
+	searchResponses := []web.CategorySearchResponse{}
+	for _, result := range results {
+		searchResponses = append(searchResponses, web.CategorySearchResponse{
+			Category:  (web.CategoryResponse)(result.Category),
+			Score:     result.Score,
+			Highlight: result.Highlight,
+			Alias:     result.Alias,
+		})
+	}
+	return searchResponses
+}
+
+func (cs *CategoryServiceImpl) Suggest(ctx context.Context, request web.CategorySuggestRequest) []web.CategorySuggestResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	categories := cs.SuggestIndex.Suggest(helper.TenantIdFromContext(ctx), request.Prefix, request.Limit)
+
+	suggestResponses := []web.CategorySuggestResponse{}
+	for _, category := range categories {
+		suggestResponses = append(suggestResponses, web.CategorySuggestResponse{
+			Id:   category.Id,
+			Name: category.Name,
+			Slug: category.Slug,
+		})
+	}
+	return suggestResponses
+}
+
+func (cs *CategoryServiceImpl) FindAliases(ctx context.Context, categoryId int) []web.CategoryAliasResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := cs.CategoryRepository.FindById(ctx, tx, categoryId); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	aliasResponses := []web.CategoryAliasResponse{}
+	for _, alias := range cs.CategoryAliasRepository.FindAllByCategory(ctx, tx, categoryId) {
+		aliasResponses = append(aliasResponses, toCategoryAliasResponse(alias))
+	}
+	return aliasResponses
+}
+
+func (cs *CategoryServiceImpl) FindAliasesByIds(ctx context.Context, categoryIds []int) []web.CategoryAliasResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	aliasResponses := []web.CategoryAliasResponse{}
+	for _, alias := range cs.CategoryAliasRepository.FindAllByCategories(ctx, tx, categoryIds) {
+		aliasResponses = append(aliasResponses, toCategoryAliasResponse(alias))
+	}
+	return aliasResponses
+}
+
+// CreateAlias adds an alias that is neither the name nor the alias of any
+// category of the tenant, ignoring case. Its slug resolves to the category
+// like an old slug, so it must not be used by another category either. The
+// category is recorded as updated, as its aliases are part of it.
+func (cs *CategoryServiceImpl) CreateAlias(ctx context.Context, request web.CategoryAliasCreateRequest) web.CategoryAliasResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	category, err := cs.CategoryRepository.FindById(ctx, tx, request.CategoryId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	alias := cs.saveAlias(ctx, tx, category, request.Alias)
+	cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+	return toCategoryAliasResponse(alias)
+}
+
+// saveAlias adds alias to category after the checks of CreateAlias.
+func (cs *CategoryServiceImpl) saveAlias(ctx context.Context, tx *sql.Tx, category domain.Category, alias string) domain.CategoryAlias {
+	if cs.CategoryAliasRepository.NameTaken(ctx, tx, alias) {
+		panic(exception.NewConflictError("category alias is already used as a name"))
+	}
+	if _, err := cs.CategoryAliasRepository.FindByAlias(ctx, tx, alias); err == nil {
+		panic(exception.NewConflictError("category alias is already used"))
+	}
+	slug := helper.Slugify(alias)
+	if cs.slugTaken(ctx, tx, slug, category.Id) {
+		panic(exception.NewConflictError("category alias slug is already used"))
+	}
+
+	return cs.CategoryAliasRepository.Save(ctx, tx, domain.CategoryAlias{
+		CategoryId: category.Id,
+		Alias:      alias,
+		Slug:       slug,
+	})
+}
+
+func (cs *CategoryServiceImpl) DeleteAlias(ctx context.Context, categoryId int, aliasId int) {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	category, err := cs.CategoryRepository.FindById(ctx, tx, categoryId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	alias, err := cs.CategoryAliasRepository.FindById(ctx, tx, aliasId)
+	if err != nil || alias.CategoryId != category.Id {
+		panic(exception.NewNotFoundError("category alias is not found"))
+	}
+
+	cs.CategoryAliasRepository.DeleteById(ctx, tx, alias)
+	cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+}
+
+func (cs *CategoryServiceImpl) FindTranslations(ctx context.Context, categoryId int) []web.CategoryTranslationResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := cs.CategoryRepository.FindById(ctx, tx, categoryId); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	translationResponses := []web.CategoryTranslationResponse{}
+	for _, translation := range cs.CategoryTranslationRepository.FindAllByCategory(ctx, tx, categoryId) {
+		translationResponses = append(translationResponses, web.CategoryTranslationResponse(translation))
+	}
+	return translationResponses
+}
+
+// SetTranslation records the category as updated, as its name changed in a
+// locale. That moves the ETag of the list and notifies subscribers.
+func (cs *CategoryServiceImpl) SetTranslation(ctx context.Context, request web.CategoryTranslationRequest) web.CategoryTranslationResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+	locale, _ := helper.CanonicalLocale(request.Locale)
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	category, err := cs.CategoryRepository.FindById(ctx, tx, request.CategoryId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	translation := cs.CategoryTranslationRepository.Save(ctx, tx, domain.CategoryTranslation{
+		CategoryId: category.Id,
+		Locale:     locale,
+		Name:       request.Name,
+	})
+	cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+	return web.CategoryTranslationResponse(translation)
+}
+
+func (cs *CategoryServiceImpl) DeleteTranslation(ctx context.Context, categoryId int, locale string) {
+	locale, ok := helper.CanonicalLocale(locale)
+	if !ok {
+		panic(exception.NewNotFoundError("category translation is not found"))
+	}
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cs.CategoryChangeRepository.Lock(ctx, tx)
+
+	category, err := cs.CategoryRepository.FindById(ctx, tx, categoryId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	if !cs.CategoryTranslationRepository.Delete(ctx, tx, category.Id, locale) {
+		panic(exception.NewNotFoundError("category translation is not found"))
+	}
+	cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+}
+
+func (cs *CategoryServiceImpl) FindLatestChange(ctx context.Context) web.CategoryChangeResponse {
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	change, err := cs.CategoryChangeRepository.FindLatest(ctx, tx)
+	if err != nil {
+		return web.CategoryChangeResponse{}
+	}
+	return toCategoryChangeResponse(change)
+}
+
+func (cs *CategoryServiceImpl) FindChanges(ctx context.Context, request web.CategoryChangesRequest) web.CategoryChangesResponse {
+	err := cs.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := cs.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	// One extra change is read to tell whether another page follows.
+	changes := cs.CategoryChangeRepository.FindAllAfter(ctx, tx, request.Cursor, request.Limit+1)
+
+	changesResponse := web.CategoryChangesResponse{
+		Changes:    []web.CategoryChangeResponse{},
+		NextCursor: request.Cursor,
+	}
+	if len(changes) > request.Limit {
+		changes = changes[:request.Limit]
+		changesResponse.HasMore = true
+	}
+	for _, change := range changes {
+		changesResponse.Changes = append(changesResponse.Changes, toCategoryChangeResponse(change))
+		changesResponse.NextCursor = change.Sequence
+	}
+	return changesResponse
+}
+
+func (cs *CategoryServiceImpl) Subscribe(ctx context.Context) (<-chan web.CategoryChangeResponse, func()) {
+	changes, unsubscribe := cs.CategoryChangeBroker.Subscribe(helper.TenantIdFromContext(ctx))
+
+	changesResponse := make(chan web.CategoryChangeResponse)
+	done := make(chan struct{})
+	go func() {
+		defer close(changesResponse)
+		for change := range changes {
+			select {
+			case changesResponse <- toCategoryChangeResponse(change):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return changesResponse, func() {
+		once.Do(func() {
+			unsubscribe()
+			close(done)
+		})
+	}
+}
+
+func (cs *CategoryServiceImpl) TouchCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) {
+	for _, category := range cs.CategoryRepository.FindAllByIds(ctx, tx, categoryIds) {
+		cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+	}
+}
+
+// recordChange saves the change of category with its current item count and
+// aliases and emits it.
+func (cs *CategoryServiceImpl) recordChange(ctx context.Context, tx *sql.Tx, operation string, category domain.Category) domain.CategoryChange {
+	if operation != domain.CategoryDeleted {
+		category = cs.details(ctx, tx, []domain.Category{category})[0]
+	}
+	return cs.emitChange(ctx, tx, cs.CategoryChangeRepository.Save(ctx, tx, operation, category))
+}
+
+// emitChange queues the webhook event of a saved change and hands the change
+// to CategoryChangeBroker once tx has committed.
+func (cs *CategoryServiceImpl) emitChange(ctx context.Context, tx *sql.Tx, change domain.CategoryChange) domain.CategoryChange {
+	eventType := webhookEventTypes[change.Operation]
+	payload, err := json.Marshal(web.WebhookEventPayload{
+		EventType:  eventType,
+		TenantId:   change.TenantId,
+		Sequence:   change.Sequence,
+		OccurredAt: change.ChangedAt,
+		Data:       toCategoryChangeResponse(change),
+	})
+	helper.PanicIfError(err)
+	cs.WebhookRepository.SaveEvent(ctx, tx, eventType, payload)
+
+	helper.OnCommit(tx, func() {
+		cs.CategoryChangeBroker.Publish(change)
+	})
+	return change
+}
+
+// publish hands e to the EventBus once tx has committed; it is dropped if tx
+// is rolled back.
+func (cs *CategoryServiceImpl) publish(ctx context.Context, tx *sql.Tx, e event.Event) {
+	helper.OnCommit(tx, func() {
+		cs.EventBus.Publish(ctx, e)
+	})
+}
+
+// decorate adds what responses carry besides the category itself: the names
+// in the locales of ctx, the item counts and the aliases.
+func (cs *CategoryServiceImpl) decorate(ctx context.Context, tx *sql.Tx, categories []domain.Category) []domain.Category {
+	return cs.details(ctx, tx, cs.localize(ctx, tx, categories))
+}
+
+// details adds the item counts and the aliases, which do not depend on the
+// locale.
+func (cs *CategoryServiceImpl) details(ctx context.Context, tx *sql.Tx, categories []domain.Category) []domain.Category {
+	return cs.findAliases(ctx, tx, cs.countItems(ctx, tx, categories))
+}
+
+// localize resolves the names of categories in the first locale of ctx that
+// has a translation, keeping the untranslated name when none has.
+func (cs *CategoryServiceImpl) localize(ctx context.Context, tx *sql.Tx, categories []domain.Category) []domain.Category {
+	locales := helper.LocalesFromContext(ctx)
+	if len(locales) == 0 || len(categories) == 0 {
+		return categories
+	}
+
+	categoryIds := make([]int, 0, len(categories))
+	for _, category := range categories {
+		categoryIds = append(categoryIds, category.Id)
+	}
+	names := cs.CategoryTranslationRepository.FindNames(ctx, tx, categoryIds, locales)
+	for i := range categories {
+		for _, locale := range locales {
+			if name, ok := names[categories[i].Id][locale]; ok {
+				categories[i].Name = name
+				categories[i].Locale = locale
+				break
+			}
+		}
+	}
+	return categories
+}
+
+func (cs *CategoryServiceImpl) countItems(ctx context.Context, tx *sql.Tx, categories []domain.Category) []domain.Category {
+	categoryIds := make([]int, 0, len(categories))
+	for _, category := range categories {
+		categoryIds = append(categoryIds, category.Id)
+	}
+	counts := cs.ItemRepository.CountByCategories(ctx, tx, categoryIds)
+	for i := range categories {
+		categories[i].ItemCount = counts[categories[i].Id]
+	}
+	return categories
+}
+
+func (cs *CategoryServiceImpl) findAliases(ctx context.Context, tx *sql.Tx, categories []domain.Category) []domain.Category {
+	categoryIds := make([]int, 0, len(categories))
+	for _, category := range categories {
+		categoryIds = append(categoryIds, category.Id)
+	}
+	aliases := map[int][]string{}
+	for _, alias := range cs.CategoryAliasRepository.FindAllByCategories(ctx, tx, categoryIds) {
+		aliases[alias.CategoryId] = append(aliases[alias.CategoryId], alias.Alias)
+	}
+	for i := range categories {
+		categories[i].Aliases = append([]string{}, aliases[categories[i].Id]...)
+	}
+	return categories
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reorder renumbers the children of parentId in the order of categoryIds and
+// records every category whose parent or position changed.
+func (cs *CategoryServiceImpl) reorder(ctx context.Context, tx *sql.Tx, parentId *int, categoryIds []int) {
+	for _, category := range cs.CategoryRepository.Renumber(ctx, tx, parentId, categoryIds) {
+		cs.recordChange(ctx, tx, domain.CategoryUpdated, category)
+	}
+}
+
+// compactSiblings closes the gap a category leaves among the children of
+// parentId, so the positions stay 0 to n-1.
+func (cs *CategoryServiceImpl) compactSiblings(ctx context.Context, tx *sql.Tx, parentId *int) {
+	var categoryIds []int
+	for _, sibling := range cs.CategoryRepository.FindChildren(ctx, tx, parentId) {
+		categoryIds = append(categoryIds, sibling.Id)
+	}
+	cs.reorder(ctx, tx, parentId, categoryIds)
+}
+
+func sameParent(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// uniqueSlug returns base, or base with the first free numeric suffix when
+// base is already used by another category than categoryId.
+func (cs *CategoryServiceImpl) uniqueSlug(ctx context.Context, tx *sql.Tx, base string, categoryId int) string {
+	slug := base
+	for i := 2; cs.slugTaken(ctx, tx, slug, categoryId); i++ {
+		slug = base + "-" + strconv.Itoa(i)
+	}
+	return slug
+}
+
+// slugTaken reports whether slug is the current or an old slug, or the slug
+// of an alias, of another category than categoryId.
+func (cs *CategoryServiceImpl) slugTaken(ctx context.Context, tx *sql.Tx, slug string, categoryId int) bool {
+	if category, err := cs.CategoryRepository.FindBySlug(ctx, tx, slug); err == nil && category.Id != categoryId {
+		return true
+	}
+	if category, err := cs.CategoryRepository.FindBySlugHistory(ctx, tx, slug); err == nil && category.Id != categoryId {
+		return true
+	}
+	if alias, err := cs.CategoryAliasRepository.FindBySlug(ctx, tx, slug); err == nil && alias.CategoryId != categoryId {
+		return true
+	}
+	return false
+}
+
+func toCategoryAliasResponse(alias domain.CategoryAlias) web.CategoryAliasResponse {
+	return web.CategoryAliasResponse{
+		Id:         alias.Id,
+		CategoryId: alias.CategoryId,
+		Alias:      alias.Alias,
+		Slug:       alias.Slug,
+		CreatedAt:  alias.CreatedAt,
+		CreatedBy:  alias.CreatedBy,
+	}
+}
+
+func toCategoryChangeResponse(change domain.CategoryChange) web.CategoryChangeResponse {
+	changeResponse := web.CategoryChangeResponse{
+		Sequence:     change.Sequence,
+		Operation:    change.Operation,
+		CategoryId:   change.Category.Id,
+		MergedIntoId: change.MergedIntoId,
+		ChangedAt:    change.ChangedAt,
+		ChangedBy:    change.ChangedBy,
+	}
+	if change.Operation != domain.CategoryDeleted && change.Operation != domain.CategoryMerged {
+		categoryResponse := (web.CategoryResponse)(change.Category)
+		changeResponse.Category = &categoryResponse
+	}
+	return changeResponse
 }
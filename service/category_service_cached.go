@@ -0,0 +1,348 @@
+package service
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type CategoryCacheConfig struct {
+	Enabled bool
+	Size    int
+	TTL     time.Duration
+	// MaxLocales bounds the locale chains entries are cached for. They come
+	// from lang and Accept-Language, so clients could send any number of
+	// them; requests in further chains are passed through.
+	MaxLocales int
+}
+
+// CategoryServiceCached is a read-through cache in front of another
+// CategoryService. Concurrent misses for the same key share one load, and
+// every write invalidates the entries it may have changed.
+type CategoryServiceCached struct {
+	CategoryService CategoryService
+	Config          CategoryCacheConfig
+
+	cache      *helper.LRUCache
+	group      singleflight.Group
+	generation uint64
+	hits       uint64
+	misses     uint64
+	// localeKeys are the localesCacheKey suffixes entries were cached with,
+	// at most Config.MaxLocales of them.
+	localeMutex sync.Mutex
+	localeKeys  map[string]struct{}
+}
+
+func NewCategoryServiceCached(categoryService CategoryService, config CategoryCacheConfig) *CategoryServiceCached {
+	return &CategoryServiceCached{
+		CategoryService: categoryService,
+		Config:          config,
+		cache:           helper.NewLRUCache(config.Size, config.TTL),
+		localeKeys:      map[string]struct{}{},
+	}
+}
+
+func (cs *CategoryServiceCached) Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse {
+	categoryResponse := cs.CategoryService.Create(ctx, request)
+	cs.invalidate(categoriesCacheKey(ctx))
+	return categoryResponse
+}
+
+// FindAll only caches the unfiltered list, filtered lists and pages are
+// passed through.
+func (cs *CategoryServiceCached) FindAll(ctx context.Context, request web.CategoryFindAllRequest) []web.CategoryResponse {
+	if !cs.Config.Enabled || !request.UpdatedSince.IsZero() || len(request.Attributes) > 0 || request.Limit > 0 {
+		return cs.CategoryService.FindAll(ctx, request)
+	}
+
+	key, ok := cs.localizedKey(ctx, categoriesCacheKey(ctx))
+	if !ok {
+		return cs.CategoryService.FindAll(ctx, request)
+	}
+	value := cs.load(ctx, key, func(ctx context.Context) interface{} {
+		return cs.CategoryService.FindAll(ctx, request)
+	})
+	categoriesResponse := value.([]web.CategoryResponse)
+	if categoriesResponse == nil {
+		return nil
+	}
+	return append([]web.CategoryResponse(nil), categoriesResponse...)
+}
+
+func (cs *CategoryServiceCached) DeleteAll(ctx context.Context, request web.CategoryDeleteAllRequest) web.CategoryDeleteAllResponse {
+	deleteAllResponse := cs.CategoryService.DeleteAll(ctx, request)
+	if !request.DryRun {
+		atomic.AddUint64(&cs.generation, 1)
+		cs.cache.Purge()
+	}
+	return deleteAllResponse
+}
+
+func (cs *CategoryServiceCached) UpdateById(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse {
+	categoryResponse := cs.CategoryService.UpdateById(ctx, request)
+	cs.invalidate(categoriesCacheKey(ctx), categoryCacheKey(ctx, request.Id))
+	return categoryResponse
+}
+
+func (cs *CategoryServiceCached) FindById(ctx context.Context, categoryId int) web.CategoryResponse {
+	if !cs.Config.Enabled {
+		return cs.CategoryService.FindById(ctx, categoryId)
+	}
+
+	key, ok := cs.localizedKey(ctx, categoryCacheKey(ctx, categoryId))
+	if !ok {
+		return cs.CategoryService.FindById(ctx, categoryId)
+	}
+	value := cs.load(ctx, key, func(ctx context.Context) interface{} {
+		return cs.CategoryService.FindById(ctx, categoryId)
+	})
+	return value.(web.CategoryResponse)
+}
+
+func (cs *CategoryServiceCached) FindByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse {
+	return cs.CategoryService.FindByIds(ctx, categoryIds)
+}
+
+func (cs *CategoryServiceCached) FindBySlug(ctx context.Context, slug string) web.CategorySlugResponse {
+	return cs.CategoryService.FindBySlug(ctx, slug)
+}
+
+func (cs *CategoryServiceCached) DeleteById(ctx context.Context, categoryId int) {
+	cs.CategoryService.DeleteById(ctx, categoryId)
+	// The siblings were renumbered too.
+	atomic.AddUint64(&cs.generation, 1)
+	cs.cache.Purge()
+}
+
+func (cs *CategoryServiceCached) FindAliases(ctx context.Context, categoryId int) []web.CategoryAliasResponse {
+	return cs.CategoryService.FindAliases(ctx, categoryId)
+}
+
+func (cs *CategoryServiceCached) FindAliasesByIds(ctx context.Context, categoryIds []int) []web.CategoryAliasResponse {
+	return cs.CategoryService.FindAliasesByIds(ctx, categoryIds)
+}
+
+func (cs *CategoryServiceCached) CreateAlias(ctx context.Context, request web.CategoryAliasCreateRequest) web.CategoryAliasResponse {
+	aliasResponse := cs.CategoryService.CreateAlias(ctx, request)
+	cs.invalidate(categoriesCacheKey(ctx), categoryCacheKey(ctx, request.CategoryId))
+	return aliasResponse
+}
+
+func (cs *CategoryServiceCached) DeleteAlias(ctx context.Context, categoryId int, aliasId int) {
+	cs.CategoryService.DeleteAlias(ctx, categoryId, aliasId)
+	cs.invalidate(categoriesCacheKey(ctx), categoryCacheKey(ctx, categoryId))
+}
+
+func (cs *CategoryServiceCached) Merge(ctx context.Context, request web.CategoryMergeRequest) web.CategoryResponse {
+	categoryResponse := cs.CategoryService.Merge(ctx, request)
+	// Moved children changed too, so the whole generation is dropped.
+	atomic.AddUint64(&cs.generation, 1)
+	cs.cache.Purge()
+	return categoryResponse
+}
+
+func (cs *CategoryServiceCached) Move(ctx context.Context, request web.CategoryMoveRequest) web.CategoryResponse {
+	categoryResponse := cs.CategoryService.Move(ctx, request)
+	// The siblings were renumbered too.
+	atomic.AddUint64(&cs.generation, 1)
+	cs.cache.Purge()
+	return categoryResponse
+}
+
+func (cs *CategoryServiceCached) Reorder(ctx context.Context, request web.CategoryReorderRequest) []web.CategoryResponse {
+	categoriesResponse := cs.CategoryService.Reorder(ctx, request)
+	atomic.AddUint64(&cs.generation, 1)
+	cs.cache.Purge()
+	return categoriesResponse
+}
+
+func (cs *CategoryServiceCached) FindChildren(ctx context.Context, categoryId int) []web.CategoryResponse {
+	return cs.CategoryService.FindChildren(ctx, categoryId)
+}
+
+func (cs *CategoryServiceCached) FindChildrenByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse {
+	return cs.CategoryService.FindChildrenByIds(ctx, categoryIds)
+}
+
+func (cs *CategoryServiceCached) FindSubtree(ctx context.Context, categoryId int) []web.CategoryResponse {
+	return cs.CategoryService.FindSubtree(ctx, categoryId)
+}
+
+func (cs *CategoryServiceCached) FindTranslations(ctx context.Context, categoryId int) []web.CategoryTranslationResponse {
+	return cs.CategoryService.FindTranslations(ctx, categoryId)
+}
+
+func (cs *CategoryServiceCached) SetTranslation(ctx context.Context, request web.CategoryTranslationRequest) web.CategoryTranslationResponse {
+	translationResponse := cs.CategoryService.SetTranslation(ctx, request)
+	cs.invalidate(categoriesCacheKey(ctx), categoryCacheKey(ctx, request.CategoryId))
+	return translationResponse
+}
+
+func (cs *CategoryServiceCached) DeleteTranslation(ctx context.Context, categoryId int, locale string) {
+	cs.CategoryService.DeleteTranslation(ctx, categoryId, locale)
+	cs.invalidate(categoriesCacheKey(ctx), categoryCacheKey(ctx, categoryId))
+}
+
+func (cs *CategoryServiceCached) FindChanges(ctx context.Context, request web.CategoryChangesRequest) web.CategoryChangesResponse {
+	return cs.CategoryService.FindChanges(ctx, request)
+}
+
+func (cs *CategoryServiceCached) Search(ctx context.Context, request web.CategorySearchRequest) []web.CategorySearchResponse {
+	return cs.CategoryService.Search(ctx, request)
+}
+
+func (cs *CategoryServiceCached) Suggest(ctx context.Context, request web.CategorySuggestRequest) []web.CategorySuggestResponse {
+	return cs.CategoryService.Suggest(ctx, request)
+}
+
+func (cs *CategoryServiceCached) FindLatestChange(ctx context.Context) web.CategoryChangeResponse {
+	return cs.CategoryService.FindLatestChange(ctx)
+}
+
+func (cs *CategoryServiceCached) Subscribe(ctx context.Context) (<-chan web.CategoryChangeResponse, func()) {
+	return cs.CategoryService.Subscribe(ctx)
+}
+
+func (cs *CategoryServiceCached) Stats() web.CacheStatsResponse {
+	return web.CacheStatsResponse{
+		Enabled: cs.Config.Enabled,
+		Hits:    atomic.LoadUint64(&cs.hits),
+		Misses:  atomic.LoadUint64(&cs.misses),
+		Size:    cs.cache.Len(),
+	}
+}
+
+// load returns the cached value for key or calls fn once for all concurrent
+// callers. The result is only cached if no write happened during the load,
+// otherwise it might already be stale. fn gets the values of ctx but not its
+// cancellation, as the callers that share the load must not fail because
+// the first one went away.
+func (cs *CategoryServiceCached) load(ctx context.Context, key string, fn func(ctx context.Context) interface{}) interface{} {
+	if value, ok := cs.cache.Get(key); ok {
+		atomic.AddUint64(&cs.hits, 1)
+		return value
+	}
+	atomic.AddUint64(&cs.misses, 1)
+
+	generation := atomic.LoadUint64(&cs.generation)
+	flightKey := key + "@" + strconv.FormatUint(generation, 10)
+	value, err, _ := cs.group.Do(flightKey, func() (result interface{}, err error) {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				err = recoveredPanic{value: rvr}
+			}
+		}()
+
+		result = fn(detachedContext{values: ctx})
+		if atomic.LoadUint64(&cs.generation) == generation {
+			cs.cache.Set(key, result)
+		}
+		return result, nil
+	})
+	if rp, ok := err.(recoveredPanic); ok {
+		// Every caller re-panics with the original value so exception.ErrorHandler
+		// can still map it, e.g. to 404 for exception.NotFoundError.
+		panic(rp.value)
+	}
+	return value
+}
+
+// Invalidate drops the cached list and the cached categoryIds of the tenant
+// in ctx after a write that changed them outside of CategoryService, like
+// assigning items.
+func (cs *CategoryServiceCached) Invalidate(ctx context.Context, categoryIds ...int) {
+	keys := []string{categoriesCacheKey(ctx)}
+	for _, categoryId := range categoryIds {
+		keys = append(keys, categoryCacheKey(ctx, categoryId))
+	}
+	cs.invalidate(keys...)
+}
+
+// invalidate deletes the entries of keys in every locale.
+func (cs *CategoryServiceCached) invalidate(keys ...string) {
+	atomic.AddUint64(&cs.generation, 1)
+
+	cs.localeMutex.Lock()
+	defer cs.localeMutex.Unlock()
+	for _, key := range keys {
+		cs.cache.Delete(key)
+		for localeKey := range cs.localeKeys {
+			cs.cache.Delete(key + localeKey)
+		}
+	}
+}
+
+// localizedKey is key for the locales of ctx. It returns false when the
+// locales of ctx are not cached because MaxLocales chains are already.
+func (cs *CategoryServiceCached) localizedKey(ctx context.Context, key string) (string, bool) {
+	localeKey := localesCacheKey(ctx)
+	if localeKey == "" {
+		return key, true
+	}
+
+	cs.localeMutex.Lock()
+	defer cs.localeMutex.Unlock()
+	if _, ok := cs.localeKeys[localeKey]; !ok {
+		if len(cs.localeKeys) >= cs.Config.MaxLocales {
+			return "", false
+		}
+		cs.localeKeys[localeKey] = struct{}{}
+	}
+	return key + localeKey, true
+}
+
+// Cache keys carry the tenant so one tenant can never be served another
+// tenant's cached categories.
+func categoriesCacheKey(ctx context.Context) string {
+	return strconv.Itoa(helper.TenantIdFromContext(ctx)) + ":all"
+}
+
+func categoryCacheKey(ctx context.Context, categoryId int) string {
+	return strconv.Itoa(helper.TenantIdFromContext(ctx)) + ":id:" + strconv.Itoa(categoryId)
+}
+
+func localesCacheKey(ctx context.Context) string {
+	locales := helper.LocalesFromContext(ctx)
+	if len(locales) == 0 {
+		return ""
+	}
+	return "@" + strings.Join(locales, ",")
+}
+
+// detachedContext keeps the values of a context, like the principal and the
+// locales, without its deadline and cancellation.
+type detachedContext struct {
+	values context.Context
+}
+
+func (dc detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (dc detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (dc detachedContext) Err() error {
+	return nil
+}
+
+func (dc detachedContext) Value(key interface{}) interface{} {
+	return dc.values.Value(key)
+}
+
+type recoveredPanic struct {
+	value interface{}
+}
+
+func (rp recoveredPanic) Error() string {
+	return "panic during cache load"
+}
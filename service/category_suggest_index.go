@@ -0,0 +1,293 @@
+package service
+
+import (
+	"Data-Category/event"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/repository"
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// suggestFeedPageSize is the page size in which the change feed is read.
+const suggestFeedPageSize = 1000
+
+// suggestEntry is the folded name of a category from one of its word starts
+// on, so "Mobile Phones" is found by both "mob" and "pho".
+type suggestEntry struct {
+	key      string
+	category domain.Category
+}
+
+type CategorySuggestConfig struct {
+	// PollInterval is how often the change feed is read for the writes of
+	// other instances.
+	PollInterval time.Duration
+}
+
+// CategorySuggestIndex answers category name prefixes from memory. It keeps
+// the entries of each tenant sorted by key, so the matches of a prefix are
+// one contiguous run found by binary search.
+//
+// The index is built once at startup and then follows the change feed of
+// CategoryChangeRepository by sequence, so it applies the changes of a tenant
+// in commit order and sees the writes of every instance. The category events
+// of EventBus only make it read the feed right away; the writes of other
+// instances show up within PollInterval.
+type CategorySuggestIndex struct {
+	CategoryRepository       repository.CategoryRepository
+	CategoryChangeRepository repository.CategoryChangeRepository
+	TenantRepository         repository.TenantRepository
+	DB                       *sql.DB
+	Config                   CategorySuggestConfig
+
+	mutex   sync.RWMutex
+	entries map[int][]suggestEntry
+
+	// feedMutex serializes reading the feed. cursors are the sequences of
+	// the last changes applied per tenant.
+	feedMutex sync.Mutex
+	cursors   map[int]int64
+}
+
+func NewCategorySuggestIndex(categoryRepository repository.CategoryRepository, categoryChangeRepository repository.CategoryChangeRepository, tenantRepository repository.TenantRepository, db *sql.DB, config CategorySuggestConfig) *CategorySuggestIndex {
+	return &CategorySuggestIndex{
+		CategoryRepository:       categoryRepository,
+		CategoryChangeRepository: categoryChangeRepository,
+		TenantRepository:         tenantRepository,
+		DB:                       db,
+		Config:                   config,
+		entries:                  map[int][]suggestEntry{},
+		cursors:                  map[int]int64{},
+	}
+}
+
+// Build loads the categories of every tenant into the index. The feed cursor
+// is read before the categories, so changes committed in between are read
+// again by the next Follow; applying a change twice is harmless.
+func (si *CategorySuggestIndex) Build(ctx context.Context) {
+	si.feedMutex.Lock()
+	defer si.feedMutex.Unlock()
+
+	tx, err := si.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	for _, tenant := range si.TenantRepository.FindAll(ctx, tx) {
+		tenantCtx := helper.WithPrincipal(ctx, domain.Principal{TenantId: tenant.Id, Subject: "system"})
+		if change, err := si.CategoryChangeRepository.FindLatest(tenantCtx, tx); err == nil {
+			si.cursors[tenant.Id] = change.Sequence
+		}
+		si.Load(tenant.Id, si.CategoryRepository.FindAll(tenantCtx, tx, domain.CategoryFilter{}))
+	}
+}
+
+// Run follows the change feed every PollInterval until ctx is done.
+func (si *CategorySuggestIndex) Run(ctx context.Context) {
+	ticker := time.NewTicker(si.Config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			si.FollowAll(ctx)
+		}
+	}
+}
+
+// FollowAll applies the new changes of every tenant.
+func (si *CategorySuggestIndex) FollowAll(ctx context.Context) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			log.Printf("suggest index: %v", rvr)
+		}
+	}()
+
+	tx, err := si.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	for _, tenant := range si.TenantRepository.FindAll(ctx, tx) {
+		si.Follow(ctx, tenant.Id)
+	}
+}
+
+// Follow applies the changes of the tenant after its cursor.
+func (si *CategorySuggestIndex) Follow(ctx context.Context, tenantId int) {
+	si.feedMutex.Lock()
+	defer si.feedMutex.Unlock()
+
+	tx, err := si.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tenantCtx := helper.WithPrincipal(ctx, domain.Principal{TenantId: tenantId, Subject: "system"})
+	for {
+		changes := si.CategoryChangeRepository.FindAllAfter(tenantCtx, tx, si.cursors[tenantId], suggestFeedPageSize)
+		si.apply(tenantId, changes)
+		if len(changes) < suggestFeedPageSize {
+			return
+		}
+	}
+}
+
+// Apply applies changes of the tenant read from the change feed, skipping
+// those that were applied already.
+func (si *CategorySuggestIndex) Apply(tenantId int, changes []domain.CategoryChange) {
+	si.feedMutex.Lock()
+	defer si.feedMutex.Unlock()
+
+	si.apply(tenantId, changes)
+}
+
+func (si *CategorySuggestIndex) apply(tenantId int, changes []domain.CategoryChange) {
+	for _, change := range changes {
+		if change.Sequence <= si.cursors[tenantId] {
+			continue
+		}
+		switch change.Operation {
+		case domain.CategoryCreated, domain.CategoryUpdated:
+			si.Put(tenantId, change.Category)
+		case domain.CategoryDeleted, domain.CategoryMerged:
+			si.Remove(tenantId, change.Category.Id)
+		}
+		si.cursors[tenantId] = change.Sequence
+	}
+}
+
+// Handle reads the feed of the tenant of a category event, which follows the
+// commit of its changes, instead of applying the event itself: the events of
+// an asynchronous EventBus can arrive out of order. The feed is read without
+// the context of the request, which may be over by now.
+func (si *CategorySuggestIndex) Handle(ctx context.Context, e event.Event) {
+	switch e := e.(type) {
+	case event.CategoryCreated:
+		si.Follow(context.Background(), e.TenantId)
+	case event.CategoryRenamed:
+		si.Follow(context.Background(), e.TenantId)
+	case event.CategoryDeleted:
+		si.Follow(context.Background(), e.TenantId)
+	case event.CategoryMerged:
+		si.Follow(context.Background(), e.TenantId)
+	case event.CategoriesPurged:
+		si.Follow(context.Background(), e.TenantId)
+	}
+}
+
+// Load replaces the entries of the tenant with those of categories, sorting
+// them once instead of inserting them one by one like Put.
+func (si *CategorySuggestIndex) Load(tenantId int, categories []domain.Category) {
+	var entries []suggestEntry
+	for _, category := range categories {
+		for _, key := range suggestKeys(category.Name) {
+			entries = append(entries, suggestEntry{key: key, category: category})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	si.mutex.Lock()
+	defer si.mutex.Unlock()
+
+	si.entries[tenantId] = entries
+}
+
+// Put adds the category to the index or replaces its previous name.
+func (si *CategorySuggestIndex) Put(tenantId int, category domain.Category) {
+	si.mutex.Lock()
+	defer si.mutex.Unlock()
+
+	entries := removeSuggestEntries(si.entries[tenantId], category.Id)
+	for _, key := range suggestKeys(category.Name) {
+		i := sort.Search(len(entries), func(i int) bool {
+			return entries[i].key >= key
+		})
+		entries = append(entries, suggestEntry{})
+		copy(entries[i+1:], entries[i:])
+		entries[i] = suggestEntry{key: key, category: category}
+	}
+	si.entries[tenantId] = entries
+}
+
+func (si *CategorySuggestIndex) Remove(tenantId int, categoryId int) {
+	si.mutex.Lock()
+	defer si.mutex.Unlock()
+
+	si.entries[tenantId] = removeSuggestEntries(si.entries[tenantId], categoryId)
+}
+
+// Suggest returns up to limit categories of the tenant with a word starting
+// with prefix. Names starting with prefix come first, then by name.
+func (si *CategorySuggestIndex) Suggest(tenantId int, prefix string, limit int) []domain.Category {
+	prefix = helper.Fold(prefix)
+	if prefix == "" {
+		return nil
+	}
+
+	si.mutex.RLock()
+	entries := si.entries[tenantId]
+	var matches []suggestEntry
+	seen := map[int]bool{}
+	for i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].key >= prefix
+	}); i < len(entries) && strings.HasPrefix(entries[i].key, prefix); i++ {
+		if !seen[entries[i].category.Id] {
+			seen[entries[i].category.Id] = true
+			matches = append(matches, entries[i])
+		}
+	}
+	si.mutex.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		nameI, nameJ := helper.Fold(matches[i].category.Name), helper.Fold(matches[j].category.Name)
+		startsI, startsJ := strings.HasPrefix(nameI, prefix), strings.HasPrefix(nameJ, prefix)
+		if startsI != startsJ {
+			return startsI
+		}
+		if nameI != nameJ {
+			return nameI < nameJ
+		}
+		return matches[i].category.Id < matches[j].category.Id
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	categories := make([]domain.Category, 0, len(matches))
+	for _, match := range matches {
+		categories = append(categories, match.category)
+	}
+	return categories
+}
+
+func suggestKeys(name string) []string {
+	folded := helper.Fold(name)
+	if folded == "" {
+		return nil
+	}
+	keys := []string{folded}
+	for i := range folded {
+		if folded[i] == ' ' {
+			keys = append(keys, folded[i+1:])
+		}
+	}
+	return keys
+}
+
+func removeSuggestEntries(entries []suggestEntry, categoryId int) []suggestEntry {
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.category.Id != categoryId {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
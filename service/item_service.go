@@ -0,0 +1,234 @@
+package service
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ItemService interface {
+	Create(ctx context.Context, request web.ItemCreateRequest) web.ItemResponse
+	FindAll(ctx context.Context) []web.ItemResponse
+	FindById(ctx context.Context, itemId int) web.ItemResponse
+	UpdateById(ctx context.Context, request web.ItemUpdateRequest) web.ItemResponse
+	DeleteById(ctx context.Context, itemId int)
+	// FindByCategory returns the items assigned to the category, or to the
+	// category and its descendants when request.IncludeDescendants is set.
+	FindByCategory(ctx context.Context, request web.ItemFindByCategoryRequest) []web.ItemResponse
+	// FindByCategoryIds returns the items assigned to any of categoryIds in
+	// one query.
+	FindByCategoryIds(ctx context.Context, categoryIds []int) []web.ItemResponse
+}
+
+// ItemServiceImpl takes the change feed lock of CategoryChangeRepository for
+// every write, like CategoryService. Assigning or unassigning an item changes
+// the item count of the category, so it is recorded as an update of the
+// category by CategoryToucher and dropped from the cache by
+// CategoryInvalidator.
+type ItemServiceImpl struct {
+	ItemRepository           repository.ItemRepository
+	CategoryRepository       repository.CategoryRepository
+	CategoryChangeRepository repository.CategoryChangeRepository
+	CategoryToucher          CategoryToucher
+	CategoryInvalidator      CategoryInvalidator
+	DB                       *sql.DB
+	Validate                 *validator.Validate
+}
+
+func NewItemService(itemRepository repository.ItemRepository, categoryRepository repository.CategoryRepository, categoryChangeRepository repository.CategoryChangeRepository, categoryToucher CategoryToucher, categoryInvalidator CategoryInvalidator, db *sql.DB, validate *validator.Validate) *ItemServiceImpl {
+	return &ItemServiceImpl{
+		ItemRepository:           itemRepository,
+		CategoryRepository:       categoryRepository,
+		CategoryChangeRepository: categoryChangeRepository,
+		CategoryToucher:          categoryToucher,
+		CategoryInvalidator:      categoryInvalidator,
+		DB:                       db,
+		Validate:                 validate,
+	}
+}
+
+func (is *ItemServiceImpl) Create(ctx context.Context, request web.ItemCreateRequest) web.ItemResponse {
+	err := is.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	is.CategoryChangeRepository.Lock(ctx, tx)
+
+	item := is.ItemRepository.Save(ctx, tx, domain.Item{
+		Name:        request.Name,
+		CategoryIds: is.categoryIds(ctx, tx, request.CategoryIds),
+	})
+	is.touchCategories(ctx, tx, item.CategoryIds)
+
+	return web.ItemResponse(item)
+}
+
+func (is *ItemServiceImpl) FindAll(ctx context.Context) []web.ItemResponse {
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	return toItemResponses(is.ItemRepository.FindAll(ctx, tx))
+}
+
+func (is *ItemServiceImpl) FindById(ctx context.Context, itemId int) web.ItemResponse {
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	item, err := is.ItemRepository.FindById(ctx, tx, itemId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	return web.ItemResponse(item)
+}
+
+func (is *ItemServiceImpl) UpdateById(ctx context.Context, request web.ItemUpdateRequest) web.ItemResponse {
+	err := is.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	is.CategoryChangeRepository.Lock(ctx, tx)
+
+	item, err := is.ItemRepository.FindById(ctx, tx, request.Id)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	categoryIds := is.categoryIds(ctx, tx, request.CategoryIds)
+	changed := symmetricDifference(item.CategoryIds, categoryIds)
+	item.Name = request.Name
+	item.CategoryIds = categoryIds
+
+	item = is.ItemRepository.UpdateById(ctx, tx, item)
+	is.touchCategories(ctx, tx, changed)
+
+	return web.ItemResponse(item)
+}
+
+func (is *ItemServiceImpl) DeleteById(ctx context.Context, itemId int) {
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	is.CategoryChangeRepository.Lock(ctx, tx)
+
+	item, err := is.ItemRepository.FindById(ctx, tx, itemId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	is.ItemRepository.DeleteById(ctx, tx, item)
+	is.touchCategories(ctx, tx, item.CategoryIds)
+}
+
+func (is *ItemServiceImpl) FindByCategory(ctx context.Context, request web.ItemFindByCategoryRequest) []web.ItemResponse {
+	err := is.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	categoryIds := []int{request.CategoryId}
+	if request.IncludeDescendants {
+		categoryIds = nil
+		for _, category := range is.CategoryRepository.FindSubtree(ctx, tx, request.CategoryId) {
+			categoryIds = append(categoryIds, category.Id)
+		}
+	} else if _, err := is.CategoryRepository.FindById(ctx, tx, request.CategoryId); err != nil {
+		categoryIds = nil
+	}
+	if len(categoryIds) == 0 {
+		panic(exception.NewNotFoundError("category is not found"))
+	}
+
+	return toItemResponses(is.ItemRepository.FindByCategories(ctx, tx, categoryIds))
+}
+
+func (is *ItemServiceImpl) FindByCategoryIds(ctx context.Context, categoryIds []int) []web.ItemResponse {
+	tx, err := is.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	return toItemResponses(is.ItemRepository.FindByCategories(ctx, tx, categoryIds))
+}
+
+// categoryIds returns requested sorted and without duplicates. It panics
+// with 400 if one of them is not a category of the tenant in ctx.
+func (is *ItemServiceImpl) categoryIds(ctx context.Context, tx *sql.Tx, requested []int) []int {
+	found := map[int]bool{}
+	for _, category := range is.CategoryRepository.FindAllByIds(ctx, tx, requested) {
+		found[category.Id] = true
+	}
+
+	categoryIds := []int{}
+	seen := map[int]bool{}
+	for _, categoryId := range requested {
+		if !found[categoryId] {
+			panic(exception.NewBadRequestError("category " + strconv.Itoa(categoryId) + " is not found"))
+		}
+		if !seen[categoryId] {
+			categoryIds = append(categoryIds, categoryId)
+			seen[categoryId] = true
+		}
+	}
+	sort.Ints(categoryIds)
+	return categoryIds
+}
+
+// touchCategories records an update of every category in categoryIds, whose
+// item counts changed, and invalidates their cache entries once tx has
+// committed.
+func (is *ItemServiceImpl) touchCategories(ctx context.Context, tx *sql.Tx, categoryIds []int) {
+	if len(categoryIds) == 0 {
+		return
+	}
+	is.CategoryToucher.TouchCategories(ctx, tx, categoryIds)
+	helper.OnCommit(tx, func() {
+		is.CategoryInvalidator.Invalidate(ctx, categoryIds...)
+	})
+}
+
+// symmetricDifference returns the ids in exactly one of a and b.
+func symmetricDifference(a []int, b []int) []int {
+	count := map[int]int{}
+	for _, id := range a {
+		count[id]++
+	}
+	for _, id := range b {
+		count[id]--
+	}
+
+	var ids []int
+	for id, c := range count {
+		if c != 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func toItemResponses(items []domain.Item) []web.ItemResponse {
+	itemsResponse := []web.ItemResponse{}
+	for _, item := range items {
+		itemsResponse = append(itemsResponse, web.ItemResponse(item))
+	}
+	return itemsResponse
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type TenantConfig struct {
+	AdminApiKey string
+}
+
+type TenantService interface {
+	Create(ctx context.Context, request web.TenantCreateRequest) web.TenantResponse
+	FindAll(ctx context.Context) []web.TenantResponse
+	DisableById(ctx context.Context, tenantId int) web.TenantResponse
+	// Authenticate resolves an API key to the principal it belongs to. The
+	// admin API key acts on the default tenant and may manage tenants.
+	Authenticate(ctx context.Context, apiKey string) (domain.Principal, bool)
+}
+
+type TenantServiceImpl struct {
+	TenantRepository repository.TenantRepository
+	DB               *sql.DB
+	Validate         *validator.Validate
+	Config           TenantConfig
+}
+
+func NewTenantService(tenantRepository repository.TenantRepository, db *sql.DB, validate *validator.Validate, config TenantConfig) *TenantServiceImpl {
+	return &TenantServiceImpl{
+		TenantRepository: tenantRepository,
+		DB:               db,
+		Validate:         validate,
+		Config:           config,
+	}
+}
+
+func (ts *TenantServiceImpl) Create(ctx context.Context, request web.TenantCreateRequest) web.TenantResponse {
+	err := ts.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := ts.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := ts.TenantRepository.FindByName(ctx, tx, request.Name); err == nil {
+		panic(exception.NewConflictError("tenant name is already used"))
+	}
+
+	apiKey := generateApiKey()
+	tenant := domain.Tenant{
+		Name:       request.Name,
+		ApiKeyHash: hashApiKey(apiKey),
+	}
+
+	tenant = ts.TenantRepository.Save(ctx, tx, tenant)
+
+	// The API key is only ever shown in the response to the create request.
+	tenantResponse := toTenantResponse(tenant)
+	tenantResponse.ApiKey = apiKey
+	return tenantResponse
+}
+
+func (ts *TenantServiceImpl) FindAll(ctx context.Context) []web.TenantResponse {
+	tx, err := ts.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tenants := ts.TenantRepository.FindAll(ctx, tx)
+
+	var tenantsResponse []web.TenantResponse
+	for _, tenant := range tenants {
+		tenantsResponse = append(tenantsResponse, toTenantResponse(tenant))
+	}
+	return tenantsResponse
+}
+
+func (ts *TenantServiceImpl) DisableById(ctx context.Context, tenantId int) web.TenantResponse {
+	tx, err := ts.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tenant, err := ts.TenantRepository.FindById(ctx, tx, tenantId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	tenant.Disabled = true
+
+	tenant = ts.TenantRepository.UpdateById(ctx, tx, tenant)
+
+	return toTenantResponse(tenant)
+}
+
+func (ts *TenantServiceImpl) Authenticate(ctx context.Context, apiKey string) (domain.Principal, bool) {
+	if apiKey == "" {
+		return domain.Principal{}, false
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(ts.Config.AdminApiKey)) == 1 {
+		return domain.Principal{TenantId: domain.DefaultTenantId, Subject: "admin", Admin: true}, true
+	}
+
+	tx, err := ts.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tenant, err := ts.TenantRepository.FindByApiKeyHash(ctx, tx, hashApiKey(apiKey))
+	if err != nil || tenant.Disabled {
+		return domain.Principal{}, false
+	}
+	return domain.Principal{TenantId: tenant.Id, Subject: "tenant/" + tenant.Name}, true
+}
+
+func toTenantResponse(tenant domain.Tenant) web.TenantResponse {
+	return web.TenantResponse{
+		Id:       tenant.Id,
+		Name:     tenant.Name,
+		Disabled: tenant.Disabled,
+	}
+}
+
+func generateApiKey() string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	helper.PanicIfError(err)
+	return hex.EncodeToString(key)
+}
+
+func hashApiKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"context"
+	"database/sql"
+	"net/url"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const webhookDeliveryLogSize = 100
+
+type WebhookService interface {
+	Create(ctx context.Context, request web.WebhookCreateRequest) web.WebhookResponse
+	FindAll(ctx context.Context) []web.WebhookResponse
+	FindById(ctx context.Context, webhookId int) web.WebhookResponse
+	DeleteById(ctx context.Context, webhookId int)
+	// FindDeliveries returns the latest delivery attempts of the webhook,
+	// newest first.
+	FindDeliveries(ctx context.Context, webhookId int) []web.WebhookDeliveryResponse
+}
+
+type WebhookServiceImpl struct {
+	WebhookRepository repository.WebhookRepository
+	DB                *sql.DB
+	Validate          *validator.Validate
+	Config            WebhookConfig
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository, db *sql.DB, validate *validator.Validate, config WebhookConfig) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		WebhookRepository: webhookRepository,
+		DB:                db,
+		Validate:          validate,
+		Config:            config,
+	}
+}
+
+func (ws *WebhookServiceImpl) Create(ctx context.Context, request web.WebhookCreateRequest) web.WebhookResponse {
+	err := ws.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	u, err := url.Parse(request.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		panic(exception.NewBadRequestError("webhook url must be an http or https url"))
+	}
+	// WebhookWorker checks the addresses again as it dials them.
+	if !ws.Config.AllowPrivateTargets {
+		if err := checkWebhookHost(ctx, u.Hostname()); err == errWebhookTargetNotPublic {
+			panic(exception.NewBadRequestError("webhook url must not point to a loopback, private or link-local address"))
+		} else if err != nil {
+			panic(exception.NewBadRequestError("webhook url host cannot be resolved"))
+		}
+	}
+
+	tx, err := ws.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	subscription := domain.WebhookSubscription{
+		Url:        request.Url,
+		EventTypes: request.EventTypes,
+		Secret:     request.Secret,
+	}
+
+	subscription = ws.WebhookRepository.Save(ctx, tx, subscription)
+
+	return toWebhookResponse(subscription)
+}
+
+func (ws *WebhookServiceImpl) FindAll(ctx context.Context) []web.WebhookResponse {
+	tx, err := ws.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	subscriptions := ws.WebhookRepository.FindAll(ctx, tx)
+
+	var webhooksResponse []web.WebhookResponse
+	for _, subscription := range subscriptions {
+		webhooksResponse = append(webhooksResponse, toWebhookResponse(subscription))
+	}
+	return webhooksResponse
+}
+
+func (ws *WebhookServiceImpl) FindById(ctx context.Context, webhookId int) web.WebhookResponse {
+	tx, err := ws.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	subscription, err := ws.WebhookRepository.FindById(ctx, tx, webhookId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	return toWebhookResponse(subscription)
+}
+
+func (ws *WebhookServiceImpl) DeleteById(ctx context.Context, webhookId int) {
+	tx, err := ws.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	subscription, err := ws.WebhookRepository.FindById(ctx, tx, webhookId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	ws.WebhookRepository.DeleteById(ctx, tx, subscription)
+}
+
+func (ws *WebhookServiceImpl) FindDeliveries(ctx context.Context, webhookId int) []web.WebhookDeliveryResponse {
+	tx, err := ws.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := ws.WebhookRepository.FindById(ctx, tx, webhookId); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	deliveries := ws.WebhookRepository.FindDeliveries(ctx, tx, webhookId, webhookDeliveryLogSize)
+
+	deliveriesResponse := []web.WebhookDeliveryResponse{}
+	for _, delivery := range deliveries {
+		deliveriesResponse = append(deliveriesResponse, web.WebhookDeliveryResponse{
+			Id:          delivery.Id,
+			OutboxId:    delivery.OutboxId,
+			EventType:   delivery.EventType,
+			Attempt:     delivery.Attempt,
+			StatusCode:  delivery.StatusCode,
+			Error:       delivery.Error,
+			DurationMs:  delivery.Duration.Milliseconds(),
+			Outcome:     delivery.Outcome,
+			AttemptedAt: delivery.AttemptedAt,
+		})
+	}
+	return deliveriesResponse
+}
+
+func toWebhookResponse(subscription domain.WebhookSubscription) web.WebhookResponse {
+	return web.WebhookResponse{
+		Id:         subscription.Id,
+		Url:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
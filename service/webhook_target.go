@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// reservedNetworks are the non-public ranges the net.IP predicates do not
+// cover.
+var reservedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+}
+
+var errWebhookTargetNotPublic = errors.New("webhook target is not a public address")
+
+// publicIp reports whether ip may receive webhooks. Loopback, private,
+// link-local, unspecified, multicast and reserved addresses are refused, so
+// tenants cannot make the service call its own network, such as the cloud
+// metadata endpoint.
+func publicIp(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkWebhookHost resolves host and fails unless all of its addresses are
+// public.
+func checkWebhookHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !publicIp(addr.IP) {
+			return errWebhookTargetNotPublic
+		}
+	}
+	return nil
+}
+
+// newWebhookTransport dials webhook targets. Unless private targets are
+// allowed, every address is checked as it is dialed, after resolution, so a
+// host that resolved to a public address at registration cannot be rebound
+// to a private one later. Proxies are not used, since the check would then
+// apply to the proxy instead of the target.
+func newWebhookTransport(config WebhookConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   config.Timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !config.AllowPrivateTargets {
+		dialer.Control = func(network string, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIp(ip) {
+				return errWebhookTargetNotPublic
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
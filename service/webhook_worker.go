@@ -0,0 +1,214 @@
+package service
+
+import (
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/repository"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	WebhookIdHeader        = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+type WebhookConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+	// AllowPrivateTargets lets webhooks target loopback and private
+	// addresses, for development and tests only.
+	AllowPrivateTargets bool
+}
+
+// WebhookWorker drains the webhook outbox. Events are claimed with a lease so
+// several workers can run side by side, POSTed to their subscription and
+// retried with exponential backoff until MaxAttempts, after which they are
+// dead-lettered. Every attempt is written to the delivery log.
+type WebhookWorker struct {
+	WebhookRepository repository.WebhookRepository
+	DB                *sql.DB
+	Client            *http.Client
+	Clock             helper.Clock
+	Config            WebhookConfig
+}
+
+func NewWebhookWorker(webhookRepository repository.WebhookRepository, db *sql.DB, clock helper.Clock, config WebhookConfig) *WebhookWorker {
+	return &WebhookWorker{
+		WebhookRepository: webhookRepository,
+		DB:                db,
+		Client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: newWebhookTransport(config),
+			// A redirect is reported as a failed delivery instead of
+			// following it to an address the subscriber did not register.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		Clock:  clock,
+		Config: config,
+	}
+}
+
+// Run delivers due events every PollInterval until ctx is done.
+func (ww *WebhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(ww.Config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ww.RunOnce(ctx) == ww.Config.BatchSize && ctx.Err() == nil {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce delivers one batch of due events and returns how many were claimed.
+func (ww *WebhookWorker) RunOnce(ctx context.Context) (claimed int) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			log.Printf("webhook worker: %v", rvr)
+		}
+	}()
+
+	events := ww.claim(ctx)
+
+	var wg sync.WaitGroup
+	for _, event := range events {
+		wg.Add(1)
+		go func(event domain.WebhookOutbox) {
+			defer wg.Done()
+			defer func() {
+				if rvr := recover(); rvr != nil {
+					log.Printf("webhook worker: event %d: %v", event.Id, rvr)
+				}
+			}()
+			ww.record(ctx, event, ww.Deliver(ctx, event))
+		}(event)
+	}
+	wg.Wait()
+	return len(events)
+}
+
+// Deliver POSTs the payload of event to its subscription once. The returned
+// delivery is WebhookDelivered for 2xx responses and WebhookFailed otherwise.
+func (ww *WebhookWorker) Deliver(ctx context.Context, event domain.WebhookOutbox) domain.WebhookDelivery {
+	delivery := domain.WebhookDelivery{
+		OutboxId:       event.Id,
+		SubscriptionId: event.SubscriptionId,
+		EventType:      event.EventType,
+		Attempt:        event.Attempts + 1,
+		Outcome:        domain.WebhookFailed,
+		AttemptedAt:    ww.Clock().UTC().Truncate(time.Microsecond),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, event.Url, bytes.NewReader(event.Payload))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	timestamp := strconv.FormatInt(delivery.AttemptedAt.Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookIdHeader, strconv.FormatInt(event.Id, 10))
+	request.Header.Set(WebhookEventHeader, event.EventType)
+	request.Header.Set(WebhookTimestampHeader, timestamp)
+	request.Header.Set(WebhookSignatureHeader, SignWebhookPayload(event.Secret, timestamp, event.Payload))
+
+	start := time.Now()
+	response, err := ww.Client.Do(request)
+	delivery.Duration = time.Since(start)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer response.Body.Close()
+	// Draining a bounded part of the body lets the connection be reused.
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	delivery.StatusCode = response.StatusCode
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		delivery.Outcome = domain.WebhookDelivered
+	} else {
+		delivery.Error = "unexpected status " + response.Status
+	}
+	return delivery
+}
+
+// Backoff returns the delay before the retry following the given attempt:
+// BaseBackoff doubled per attempt, capped at MaxBackoff, with up to half of it
+// taken off at random so failing events do not retry in lockstep.
+func (ww *WebhookWorker) Backoff(attempt int) time.Duration {
+	backoff := ww.Config.BaseBackoff
+	for i := 1; i < attempt && backoff < ww.Config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > ww.Config.MaxBackoff {
+		backoff = ww.Config.MaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// SignWebhookPayload returns the value of the signature header: the hex
+// HMAC-SHA256 of timestamp, a dot and the body, keyed with the secret of the
+// subscription.
+func SignWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (ww *WebhookWorker) claim(ctx context.Context) []domain.WebhookOutbox {
+	tx, err := ww.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	// The lease outlives the delivery, so an event is only claimed again if
+	// the worker holding it died before recording the result.
+	leaseUntil := ww.Clock().Add(ww.Config.Timeout + time.Minute)
+	return ww.WebhookRepository.ClaimEvents(ctx, tx, ww.Config.BatchSize, leaseUntil)
+}
+
+func (ww *WebhookWorker) record(ctx context.Context, event domain.WebhookOutbox, delivery domain.WebhookDelivery) {
+	tx, err := ww.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	event.Attempts = delivery.Attempt
+	event.LastError = delivery.Error
+	switch {
+	case delivery.Outcome == domain.WebhookDelivered:
+		event.Status = domain.WebhookDelivered
+	case event.Attempts >= ww.Config.MaxAttempts:
+		event.Status = domain.WebhookDead
+		delivery.Outcome = domain.WebhookDead
+	default:
+		event.Status = domain.WebhookPending
+		event.NextAttemptAt = ww.Clock().Add(ww.Backoff(event.Attempts))
+	}
+
+	ww.WebhookRepository.SaveDelivery(ctx, tx, delivery)
+	ww.WebhookRepository.UpdateEvent(ctx, tx, event)
+}
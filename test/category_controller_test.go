@@ -1,8 +1,11 @@
 package test
 
 import (
+	"Data-Category/api"
 	"Data-Category/app"
 	"Data-Category/controller"
+	"Data-Category/event"
+	"Data-Category/graph"
 	"Data-Category/helper"
 	"Data-Category/middleware"
 	"Data-Category/model/domain"
@@ -20,7 +23,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,21 +48,53 @@ func setupNewDB() *sql.DB {
 	return db
 }
 
+// adminContext is the context of a request made with the admin API key,
+// which acts on the default tenant.
+func adminContext() context.Context {
+	return helper.WithPrincipal(context.Background(), domain.Principal{TenantId: domain.DefaultTenantId, Subject: "admin", Admin: true})
+}
+
+func newCategoryDeleteGuard() *service.CategoryDeleteGuard {
+	return service.NewCategoryDeleteGuard(repository.NewTenantRepository(), helper.NewClock(), service.CategoryDeleteConfig{
+		TokenSecret: []byte("secret"),
+		TokenTTL:    time.Minute,
+	})
+}
+
 func truncateDataCategory(db *sql.DB) {
-	db.Query("TRUNCATE data_category")
+	db.Exec("TRUNCATE data_category, category_change, category_change_sequence, webhook_subscription, item CASCADE")
 }
 
 func setupRouter(db *sql.DB) http.Handler {
-	validate := validator.New()
-	categoryRepository := repository.NewCategoryRepository()
-	categoryService := service.NewCategoryService(categoryRepository, db, validate)
+	validate := app.NewValidator()
+	categoryRepository := repository.NewCategoryRepository(helper.NewClock())
+	webhookRepository := repository.NewWebhookRepository(helper.NewClock())
+	categoryChangeRepository := repository.NewCategoryChangeRepository(helper.NewClock())
+	suggestIndex := service.NewCategorySuggestIndex(categoryRepository, categoryChangeRepository, repository.NewTenantRepository(), db, app.NewCategorySuggestConfig())
+	itemRepository := repository.NewItemRepository(helper.NewClock())
+	categoryServiceImpl := service.NewCategoryService(categoryRepository, repository.NewCategoryAliasRepository(helper.NewClock()), repository.NewCategoryTranslationRepository(helper.NewClock()), itemRepository, categoryChangeRepository, webhookRepository, service.NewCategoryChangeBroker(), event.NewSyncEventBus([]event.Subscriber{suggestIndex}), suggestIndex, newCategoryDeleteGuard(), db, validate)
+	categoryService := service.NewCategoryServiceCached(categoryServiceImpl, app.NewCategoryCacheConfig())
 	CategoryController := controller.NewCategoryController(categoryService)
+	cacheController := controller.NewCacheController(categoryService)
+	tenantService := service.NewTenantService(repository.NewTenantRepository(), db, validate, app.NewTenantConfig())
+	tenantController := controller.NewTenantController(tenantService)
+	webhookController := controller.NewWebhookController(service.NewWebhookService(webhookRepository, db, validate, setupWebhookConfig(3)))
+	itemService := service.NewItemService(itemRepository, categoryRepository, categoryChangeRepository, categoryServiceImpl, categoryService, db, validate)
+	itemController := controller.NewItemController(itemService)
+	graphqlController := controller.NewGraphqlController(graph.NewSchema(categoryService), categoryService, itemService)
+	openApiController := controller.NewOpenApiController()
+
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(repository.NewIdempotencyRepositoryInMemory(), app.NewIdempotencyConfig())
+
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(repository.NewRateLimitRepositoryInMemory(), app.NewRateLimitConfig())
+
+	openApiMiddleware := middleware.NewOpenApiMiddleware(api.NewDocument(), app.NewOpenApiConfig())
 
-	r := app.NewRouter(CategoryController)
+	r := app.NewRouter(CategoryController, cacheController, tenantController, webhookController, itemController, graphqlController, openApiController, middleware.NewCompressionMiddleware(app.NewCompressionConfig()), middleware.NewContentNegotiationMiddleware(helper.NewCodecRegistry()), idempotencyMiddleware, rateLimitMiddleware, openApiMiddleware, middleware.NewLocaleMiddleware(app.NewLocaleConfig()))
 
 	server := http.Server{
 		Addr:    "localhost:3000",
-		Handler: middleware.NewAuthMiddleware(r),
+		Handler: rateLimitMiddleware.WrapIp(middleware.NewAuthMiddleware(r, tenantService)),
 	}
 
 	return server.Handler
@@ -121,8 +155,8 @@ func TestFindAllCategoriesSuccess(t *testing.T) {
 	truncateDataCategory(db)
 
 	tx, _ := db.Begin()
-	categoryRespository := repository.NewCategoryRepository()
-	category := categoryRespository.Save(context.Background(), tx, domain.Category{
+	categoryRespository := repository.NewCategoryRepository(helper.NewClock())
+	category := categoryRespository.Save(adminContext(), tx, domain.Category{
 		Name: "Gadget",
 	})
 	tx.Commit()
@@ -157,15 +191,16 @@ func TestDeleteALlCategoriesSuccess(t *testing.T) {
 	truncateDataCategory(db)
 
 	tx, _ := db.Begin()
-	categoryRespository := repository.NewCategoryRepository()
-	categoryRespository.Save(context.Background(), tx, domain.Category{
+	categoryRespository := repository.NewCategoryRepository(helper.NewClock())
+	categoryRespository.Save(adminContext(), tx, domain.Category{
 		Name: "Gadget",
 	})
 	tx.Commit()
 
 	r := setupRouter(db)
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/", nil)
+	// Deleting takes the confirmation token of a dry run.
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/?dry_run=true", nil)
 	request.Header.Add("X-API-KEY", "RAHASIA")
 
 	recorder := httptest.NewRecorder()
@@ -179,8 +214,42 @@ func TestDeleteALlCategoriesSuccess(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
+	dryRun := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), dryRun["count"])
+	token := dryRun["confirmation_token"].(string)
+
+	request = httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/?confirmation_token="+token, nil)
+	request.Header.Add("X-API-KEY", "RAHASIA")
+
+	recorder = httptest.NewRecorder()
+
+	r.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, 200, response.StatusCode)
+
+	body, _ = io.ReadAll(response.Body)
+	responseBody = map[string]interface{}{}
+	json.Unmarshal(body, &responseBody)
+
 	assert.Equal(t, 200, int(responseBody["code"].(float64)))
 	assert.Equal(t, "OK", responseBody["status"])
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["count"])
+}
+
+func TestDeleteAllCategoriesWithoutConfirmationToken(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+
+	response, responseBody := sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/", "", "RAHASIA")
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, "confirmation_token is required, request one with dry_run=true", responseBody["data"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", "RAHASIA")
+	assert.Equal(t, []string{"Gadget"}, categoryNames(responseBody))
 }
 
 func TestUpdateCategoryByIdSuccess(t *testing.T) {
@@ -188,8 +257,8 @@ func TestUpdateCategoryByIdSuccess(t *testing.T) {
 	truncateDataCategory(db)
 
 	tx, _ := db.Begin()
-	categoryRespository := repository.NewCategoryRepository()
-	category := categoryRespository.Save(context.Background(), tx, domain.Category{
+	categoryRespository := repository.NewCategoryRepository(helper.NewClock())
+	category := categoryRespository.Save(adminContext(), tx, domain.Category{
 		Name: "Gadget",
 	})
 	tx.Commit()
@@ -223,8 +292,8 @@ func TestUpdateCategoryByIdFailed(t *testing.T) {
 	truncateDataCategory(db)
 
 	tx, _ := db.Begin()
-	categoryRespository := repository.NewCategoryRepository()
-	category := categoryRespository.Save(context.Background(), tx, domain.Category{
+	categoryRespository := repository.NewCategoryRepository(helper.NewClock())
+	category := categoryRespository.Save(adminContext(), tx, domain.Category{
 		Name: "Gadget",
 	})
 	tx.Commit()
@@ -256,8 +325,8 @@ func TestFindCategoryByIdSuccess(t *testing.T) {
 	truncateDataCategory(db)
 
 	tx, _ := db.Begin()
-	categoryRespository := repository.NewCategoryRepository()
-	category := categoryRespository.Save(context.Background(), tx, domain.Category{
+	categoryRespository := repository.NewCategoryRepository(helper.NewClock())
+	category := categoryRespository.Save(adminContext(), tx, domain.Category{
 		Name: "Gadget",
 	})
 	tx.Commit()
@@ -313,8 +382,8 @@ func TestDeleteCategoryByIdSuccess(t *testing.T) {
 	truncateDataCategory(db)
 
 	tx, _ := db.Begin()
-	categoryRespository := repository.NewCategoryRepository()
-	category := categoryRespository.Save(context.Background(), tx, domain.Category{
+	categoryRespository := repository.NewCategoryRepository(helper.NewClock())
+	category := categoryRespository.Save(adminContext(), tx, domain.Category{
 		Name: "Gadget",
 	})
 	tx.Commit()
@@ -385,3 +454,548 @@ func TestUnauthorized(t *testing.T) {
 	assert.Equal(t, 401, int(responseBody["code"].(float64)))
 	assert.Equal(t, "Unauthorized", responseBody["status"])
 }
+
+func TestCreateCategoryGeneratesUniqueSlug(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget!"}`, "RAHASIA")
+	assert.Equal(t, "gadget", responseBody["data"].(map[string]interface{})["slug"])
+
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget?"}`, "RAHASIA")
+	assert.Equal(t, "gadget-2", responseBody["data"].(map[string]interface{})["slug"])
+
+	response, _ := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Laptop","slug":"gadget"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+}
+
+func TestCategoryNamesAreUniqueIgnoringCase(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+	categoryId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Laptop"}`, "RAHASIA")
+	laptopId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	response, _ := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"GADGET"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+laptopId, `{"name":"gadget"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+categoryId, `{"name":"GADGET"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "GADGET", responseBody["data"].(map[string]interface{})["name"])
+}
+
+func TestFindCategoryBySlugSuccess(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget","slug":"gadget-murah"}`, "RAHASIA")
+
+	response, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/by-slug/gadget-murah", "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "Gadget", responseBody["data"].(map[string]interface{})["name"])
+}
+
+func TestFindCategoryByOldSlugRedirects(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+	categoryId := int(responseBody["data"].(map[string]interface{})["id"].(float64))
+
+	_, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+strconv.Itoa(categoryId), `{"name":"Gadget Elektronik"}`, "RAHASIA")
+	assert.Equal(t, "gadget-elektronik", responseBody["data"].(map[string]interface{})["slug"])
+
+	response, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/by-slug/gadget", "", "RAHASIA")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/api/categories/by-slug/gadget-elektronik", response.Header.Get("Location"))
+	assert.Equal(t, "gadget-elektronik", responseBody["data"].(map[string]interface{})["slug"])
+
+	response, _ = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/by-slug/unknown", "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+func TestCategoryAliases(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Mobile Phones"}`, "RAHASIA")
+	categoryId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Laptop"}`, "RAHASIA")
+
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+categoryId+"/aliases", `{"alias":"Cellphone"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	aliasId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	assert.Equal(t, "cellphone", responseBody["data"].(map[string]interface{})["slug"])
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+categoryId+"/aliases", `{"alias":"CELLPHONE"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+categoryId+"/aliases", `{"alias":"laptop"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Cellphone"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/by-slug/cellphone", "", "RAHASIA")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/api/categories/by-slug/mobile-phones", response.Header.Get("Location"))
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/search?q=cellfone", "", "RAHASIA")
+	results := responseBody["data"].([]interface{})
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Cellphone", results[0].(map[string]interface{})["alias"])
+
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/"+categoryId+"/aliases/"+aliasId, "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+categoryId+"/aliases", "", "RAHASIA")
+	assert.Empty(t, responseBody["data"])
+}
+
+func TestRenameCategoryToOwnAlias(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Mobile Phones","aliases":["Cellphone","Handphone"]}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	categoryId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	assert.Equal(t, []interface{}{"Cellphone", "Handphone"}, responseBody["data"].(map[string]interface{})["aliases"])
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Laptop"}`, "RAHASIA")
+	laptopId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+laptopId, `{"name":"Cellphone"}`, "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+categoryId, `{"name":"Cellphone"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "cellphone", responseBody["data"].(map[string]interface{})["slug"])
+	assert.Equal(t, []interface{}{"Handphone"}, responseBody["data"].(map[string]interface{})["aliases"])
+
+	response, _ = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/by-slug/mobile-phones", "", "RAHASIA")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/api/categories/by-slug/cellphone", response.Header.Get("Location"))
+}
+
+func TestMergeCategories(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+	targetId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadgets"}`, "RAHASIA")
+	sourceId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Tablet","parent_id":`+sourceId+`}`, "RAHASIA")
+	childId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	response, _ := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+childId+"/merge", `{"source_id":`+sourceId+`}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+targetId+"/merge", `{"source_id":`+sourceId+`}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+sourceId, "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+childId, "", "RAHASIA")
+	assert.Equal(t, targetId, strconv.Itoa(int(responseBody["data"].(map[string]interface{})["parent_id"].(float64))))
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+targetId+"/aliases", "", "RAHASIA")
+	aliases := responseBody["data"].([]interface{})
+	assert.Len(t, aliases, 1)
+	assert.Equal(t, "Gadgets", aliases[0].(map[string]interface{})["alias"])
+
+	response, _ = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/by-slug/gadgets", "", "RAHASIA")
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/api/categories/by-slug/gadget", response.Header.Get("Location"))
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/changes", "", "RAHASIA")
+	changes := responseBody["data"].(map[string]interface{})["changes"].([]interface{})
+	merged := changes[len(changes)-1].(map[string]interface{})
+	assert.Equal(t, "merged", merged["operation"])
+	assert.Equal(t, targetId, strconv.Itoa(int(merged["merged_into_id"].(float64))))
+	updated := changes[len(changes)-2].(map[string]interface{})
+	assert.Equal(t, "updated", updated["operation"])
+	assert.Equal(t, []interface{}{"Gadgets"}, updated["category"].(map[string]interface{})["aliases"])
+
+	// Once it is no alias either, the name of the soft-deleted source is free
+	// again. Its slug still redirects to the target.
+	aliasId := strconv.Itoa(int(aliases[0].(map[string]interface{})["id"].(float64)))
+	sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/"+targetId+"/aliases/"+aliasId, "", "RAHASIA")
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadgets","slug":"gadgets-baru"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func categoryNames(responseBody map[string]interface{}) []string {
+	var names []string
+	for _, category := range responseBody["data"].([]interface{}) {
+		names = append(names, category.(map[string]interface{})["name"].(string))
+	}
+	return names
+}
+
+func TestMoveAndReorderCategories(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+	gadgetId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	ids := map[string]string{}
+	for _, name := range []string{"Phone", "Tablet", "Laptop"} {
+		_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"`+name+`","parent_id":`+gadgetId+`}`, "RAHASIA")
+		ids[name] = strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	}
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["position"])
+
+	response, responseBody := sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+gadgetId+"/children",
+		`{"category_ids":[`+ids["Laptop"]+`,`+ids["Phone"]+`,`+ids["Tablet"]+`]}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"Laptop", "Phone", "Tablet"}, categoryNames(responseBody))
+
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+gadgetId+"/children",
+		`{"category_ids":[`+ids["Laptop"]+`,`+ids["Phone"]+`]}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+gadgetId+"/move", `{"parent_id":`+ids["Phone"]+`}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+ids["Tablet"]+"/move", `{"parent_id":`+ids["Laptop"]+`,"position":5}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, float64(0), responseBody["data"].(map[string]interface{})["position"])
+
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+ids["Phone"]+"/move", `{"parent_id":null,"position":0}`, "RAHASIA")
+	assert.Nil(t, responseBody["data"].(map[string]interface{})["parent_id"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", "RAHASIA")
+	assert.Equal(t, []string{"Phone", "Gadget", "Laptop", "Tablet"}, categoryNames(responseBody))
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+gadgetId+"/subtree", "", "RAHASIA")
+	assert.Equal(t, []string{"Gadget", "Laptop", "Tablet"}, categoryNames(responseBody))
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+gadgetId+"/children", "", "RAHASIA")
+	assert.Equal(t, []string{"Laptop"}, categoryNames(responseBody))
+	assert.Equal(t, float64(0), responseBody["data"].([]interface{})[0].(map[string]interface{})["position"])
+
+	response, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/children",
+		`{"category_ids":[`+gadgetId+`,`+ids["Phone"]+`]}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"Gadget", "Phone"}, categoryNames(responseBody))
+
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/children",
+		`{"category_ids":[`+gadgetId+`]}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", "RAHASIA")
+	assert.Equal(t, []string{"Gadget", "Laptop", "Tablet", "Phone"}, categoryNames(responseBody))
+}
+
+func TestCategoryAttributes(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	schema := `{"type":"object","required":["tax_class"],"properties":{"tax_class":{"type":"string","enum":["standard","reduced"]}}}`
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Food","attributes":{"tax_class":"reduced"},"attribute_schema":`+schema+`}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	foodId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Fruit","parent_id":`+foodId+`,"attributes":{"color":"red"}}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Fruit","parent_id":`+foodId+`,"attributes":{"color":"red","tax_class":"reduced"}}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	fruitId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Toy","attributes":{"color":"red"}}`, "RAHASIA")
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories?attributes[color]=red&attributes[tax_class]=reduced", "", "RAHASIA")
+	assert.Equal(t, []string{"Fruit"}, categoryNames(responseBody))
+
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+foodId,
+		`{"name":"Food","attribute_schema":{"type":"object","properties":{"tax_class":{"type":"string","enum":["standard"]}}}}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+fruitId, `{"name":"Fruits"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "red", responseBody["data"].(map[string]interface{})["attributes"].(map[string]interface{})["color"])
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Drink","attribute_schema":{"type":"object","properties":{"x":{"$ref":"#/components/schemas/Category"}}}}`, "RAHASIA")
+	assert.Equal(t, 400, response.StatusCode)
+
+	response, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Drink","attribute_schema":{"type":"object","properties":{"sizes":{"type":"array","minItems":1}}}}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+	assert.Equal(t, "attribute_schema properties.sizes.minItems is not supported", responseBody["data"])
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Drink","attribute_schema":{"type":"object","allOf":[{"required":["size"]}]}}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+	response, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Drink","attribute_schema":{"type":"object","properties":{"size":{"type":["string","null"]}}}}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+	assert.Equal(t, "attribute_schema properties.size.type must be a single type, with nullable for null", responseBody["data"])
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Drink","attributes":{"sizes":["s","m"]},"attribute_schema":{"type":"object","properties":{"sizes":{"type":"array","enum":[["s","m"],["l"]]}}}}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestCategoryTranslations(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories",
+		`{"name":"Gadget","translations":{"id":"Gawai","en":"Gadgets"}}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	categoryId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+categoryId+"?lang=id", "", "RAHASIA")
+	assert.Equal(t, "Gawai", responseBody["data"].(map[string]interface{})["name"])
+	assert.Equal(t, "id", responseBody["data"].(map[string]interface{})["locale"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories?lang=en-GB", "", "RAHASIA")
+	assert.Equal(t, []string{"Gadgets"}, categoryNames(responseBody))
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+categoryId+"?lang=fr", "", "RAHASIA")
+	assert.Equal(t, "Gadget", responseBody["data"].(map[string]interface{})["name"])
+	assert.Nil(t, responseBody["data"].(map[string]interface{})["locale"])
+
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+categoryId+"/translations/fr", `{"name":"Gadgets"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	response, _ = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+categoryId+"/translations/!!", `{"name":"Gadgets"}`, "RAHASIA")
+	assert.Equal(t, 400, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/"+categoryId+"/translations/id", "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/"+categoryId+"/translations/id", "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+categoryId+"/translations", "", "RAHASIA")
+	translations := responseBody["data"].([]interface{})
+	assert.Equal(t, 2, len(translations))
+	assert.Equal(t, "en", translations[0].(map[string]interface{})["locale"])
+	assert.Equal(t, "fr", translations[1].(map[string]interface{})["locale"])
+}
+
+func TestItems(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Electronics"}`, "RAHASIA")
+	electronicsId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Phones","parent_id":`+electronicsId+`}`, "RAHASIA")
+	phonesId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/items",
+		`{"name":"Pixel","category_ids":[`+phonesId+`,`+phonesId+`]}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	item := responseBody["data"].(map[string]interface{})
+	itemId := strconv.Itoa(int(item["id"].(float64)))
+	assert.Equal(t, []interface{}{float64(mustAtoi(phonesId))}, item["category_ids"])
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/items", `{"name":"Pixel","category_ids":[999999]}`, "RAHASIA")
+	assert.Equal(t, 400, response.StatusCode)
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+phonesId, "", "RAHASIA")
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["item_count"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+electronicsId+"/items", "", "RAHASIA")
+	assert.Equal(t, 0, len(responseBody["data"].([]interface{})))
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+electronicsId+"/items?include_descendants=true", "", "RAHASIA")
+	assert.Equal(t, 1, len(responseBody["data"].([]interface{})))
+
+	response, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/items/"+itemId,
+		`{"name":"Pixel 9","category_ids":[`+electronicsId+`]}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "Pixel 9", responseBody["data"].(map[string]interface{})["name"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", "RAHASIA")
+	for _, category := range responseBody["data"].([]interface{}) {
+		category := category.(map[string]interface{})
+		if category["name"] == "Electronics" {
+			assert.Equal(t, float64(1), category["item_count"])
+		} else {
+			assert.Equal(t, float64(0), category["item_count"])
+		}
+	}
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+phonesId+"/merge", `{"source_id":`+electronicsId+`}`, "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+	_, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadgets"}`, "RAHASIA")
+	gadgetsId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	response, responseBody = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories/"+gadgetsId+"/merge", `{"source_id":`+electronicsId+`}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["item_count"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/items/"+itemId, "", "RAHASIA")
+	assert.Equal(t, []interface{}{float64(mustAtoi(gadgetsId))}, responseBody["data"].(map[string]interface{})["category_ids"])
+
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/items/"+itemId, "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+gadgetsId, "", "RAHASIA")
+	assert.Equal(t, float64(0), responseBody["data"].(map[string]interface{})["item_count"])
+	response, _ = sendRequest(r, http.MethodGet, "http://localhost:3000/api/items/"+itemId, "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+func TestDeleteAllCategories(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	ids := map[string]string{}
+	for _, category := range [][2]string{{"Electronics", ""}, {"Phones", "Electronics"}, {"Android", "Phones"}, {"Books", ""}, {"Gadget", ""}} {
+		body := `{"name":"` + category[0] + `"}`
+		if category[1] != "" {
+			body = `{"name":"` + category[0] + `","parent_id":` + ids[category[1]] + `}`
+		}
+		_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", body, "RAHASIA")
+		ids[category[0]] = strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	}
+
+	response, _ := sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories", "", "RAHASIA")
+	assert.Equal(t, 400, response.StatusCode)
+
+	response, responseBody := sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?dry_run=true&name=PHONE*", "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	dryRun := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, true, dryRun["dry_run"])
+	assert.Equal(t, float64(2), dryRun["count"])
+	token := dryRun["confirmation_token"].(string)
+
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?name=book*&confirmation_token="+token, "", "RAHASIA")
+	assert.Equal(t, 422, response.StatusCode)
+
+	sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+ids["Gadget"], `{"name":"Gadgets"}`, "RAHASIA")
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?name=PHONE*&confirmation_token="+token, "", "RAHASIA")
+	assert.Equal(t, 409, response.StatusCode)
+
+	_, responseBody = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?dry_run=true&name=PHONE*", "", "RAHASIA")
+	token = responseBody["data"].(map[string]interface{})["confirmation_token"].(string)
+	response, responseBody = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?name=PHONE*&confirmation_token="+token, "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["count"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", "RAHASIA")
+	assert.Equal(t, []string{"Electronics", "Books", "Gadgets"}, categoryNames(responseBody))
+
+	_, responseBody = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?dry_run=true&subtree_id="+ids["Books"], "", "RAHASIA")
+	token = responseBody["data"].(map[string]interface{})["confirmation_token"].(string)
+	sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?subtree_id="+ids["Books"]+"&confirmation_token="+token, "", "RAHASIA")
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+ids["Gadget"], "", "RAHASIA")
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["position"])
+
+	_, responseBody = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?dry_run=true", "", "RAHASIA")
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["count"])
+	token = responseBody["data"].(map[string]interface{})["confirmation_token"].(string)
+	response, responseBody = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?confirmation_token="+token, "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["count"])
+
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories?dry_run=true&tenant_id=999999", "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+func mustAtoi(s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+func TestCategoryAuditFields(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	tx, _ := db.Begin()
+	categoryRespository := repository.NewCategoryRepository(func() time.Time { return createdAt })
+	category := categoryRespository.Save(adminContext(), tx, domain.Category{
+		Name: "Gadget",
+		Slug: "gadget",
+	})
+	tx.Commit()
+
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+strconv.Itoa(category.Id), "", "RAHASIA")
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "2022-01-02T03:04:05Z", data["created_at"])
+	assert.Equal(t, "admin", data["created_by"])
+
+	_, responseBody = sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+strconv.Itoa(category.Id), `{"name":"Gadgetin"}`, "RAHASIA")
+	data = responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "2022-01-02T03:04:05Z", data["created_at"])
+	assert.NotEqual(t, "2022-01-02T03:04:05Z", data["updated_at"])
+	assert.Equal(t, "admin", data["updated_by"])
+}
+
+func TestFindAllCategoriesUpdatedSince(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+
+	tx, _ := db.Begin()
+	oldRepository := repository.NewCategoryRepository(func() time.Time { return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) })
+	oldRepository.Save(adminContext(), tx, domain.Category{Name: "Gadget", Slug: "gadget"})
+	newRepository := repository.NewCategoryRepository(func() time.Time { return time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC) })
+	newRepository.Save(adminContext(), tx, domain.Category{Name: "Laptop", Slug: "laptop"})
+	tx.Commit()
+
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories?updated_since=2022-03-01T00:00:00Z", "", "RAHASIA")
+	categories := responseBody["data"].([]interface{})
+	assert.Len(t, categories, 1)
+	assert.Equal(t, "Laptop", categories[0].(map[string]interface{})["name"])
+}
+
+func TestFindAllCategoriesInvalidUpdatedSince(t *testing.T) {
+	db := setupNewDB()
+	r := setupRouter(db)
+
+	response, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories?updated_since=yesterday", "", "RAHASIA")
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, "Bad Request", responseBody["status"])
+}
+
+func TestFindCategoryChangesSuccess(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+	categoryId := int(responseBody["data"].(map[string]interface{})["id"].(float64))
+	sendRequest(r, http.MethodPut, "http://localhost:3000/api/categories/"+strconv.Itoa(categoryId), `{"name":"Gadgetin"}`, "RAHASIA")
+	sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/"+strconv.Itoa(categoryId), "", "RAHASIA")
+
+	response, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/changes?limit=2", "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+
+	data := responseBody["data"].(map[string]interface{})
+	changes := data["changes"].([]interface{})
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "created", changes[0].(map[string]interface{})["operation"])
+	assert.Equal(t, "updated", changes[1].(map[string]interface{})["operation"])
+	assert.Equal(t, true, data["has_more"])
+
+	nextCursor := int(data["next_cursor"].(float64))
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/changes?cursor="+strconv.Itoa(nextCursor), "", "RAHASIA")
+
+	data = responseBody["data"].(map[string]interface{})
+	changes = data["changes"].([]interface{})
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "deleted", changes[0].(map[string]interface{})["operation"])
+	assert.Equal(t, categoryId, int(changes[0].(map[string]interface{})["category_id"].(float64)))
+	assert.Nil(t, changes[0].(map[string]interface{})["category"])
+	assert.Equal(t, false, data["has_more"])
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/service"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryDeleteGuardTokens(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	guard := service.NewCategoryDeleteGuard(nil, func() time.Time { return now }, service.CategoryDeleteConfig{
+		TokenSecret: []byte("secret"),
+		TokenTTL:    5 * time.Minute,
+	})
+	claims := service.CategoryDeleteClaims{TenantId: 1, SubtreeId: 2, Name: "gad*", Sequence: 7}
+
+	token, expiresAt := guard.Issue(claims)
+	assert.Equal(t, now.Add(5*time.Minute), expiresAt)
+	guard.Check(token, claims)
+
+	assert.PanicsWithValue(t, exception.NewUnprocessableEntityError("confirmation_token is invalid"), func() {
+		guard.Check(token+"x", claims)
+	})
+	assert.PanicsWithValue(t, exception.NewUnprocessableEntityError("confirmation_token is invalid"), func() {
+		guard.Check("token", claims)
+	})
+	other := service.NewCategoryDeleteGuard(nil, guard.Clock, service.CategoryDeleteConfig{TokenSecret: []byte("other"), TokenTTL: time.Minute})
+	assert.PanicsWithValue(t, exception.NewUnprocessableEntityError("confirmation_token is invalid"), func() {
+		other.Check(token, claims)
+	})
+
+	otherFilter := claims
+	otherFilter.Name = "*"
+	assert.PanicsWithValue(t, exception.NewUnprocessableEntityError("confirmation_token was issued for another filter"), func() {
+		guard.Check(token, otherFilter)
+	})
+	changed := claims
+	changed.Sequence = 8
+	assert.PanicsWithValue(t, exception.NewAbortedError("categories changed since the dry run"), func() {
+		guard.Check(token, changed)
+	})
+
+	now = now.Add(5 * time.Minute)
+	assert.PanicsWithValue(t, exception.NewUnprocessableEntityError("confirmation_token has expired"), func() {
+		guard.Check(token, claims)
+	})
+}
+
+func TestCategoryDeleteGuardTenantContext(t *testing.T) {
+	guard := service.NewCategoryDeleteGuard(nil, helper.NewClock(), service.CategoryDeleteConfig{})
+	ctx := helper.WithPrincipal(context.Background(), domain.Principal{TenantId: 2, Subject: "shop"})
+
+	assert.Equal(t, ctx, guard.TenantContext(ctx, nil, 0))
+	assert.Equal(t, ctx, guard.TenantContext(ctx, nil, 2))
+	assert.PanicsWithValue(t, exception.NewForbiddenError("only the admin can delete the categories of other tenants"), func() {
+		guard.TenantContext(ctx, nil, 1)
+	})
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"Data-Category/controller"
+	"Data-Category/exception"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type categoryEventsStub struct {
+	service.CategoryService
+	changes chan web.CategoryChangeResponse
+	log     []web.CategoryChangeResponse
+}
+
+func (cs *categoryEventsStub) FindChanges(ctx context.Context, request web.CategoryChangesRequest) web.CategoryChangesResponse {
+	categoryChangesResponse := web.CategoryChangesResponse{NextCursor: request.Cursor}
+	for _, change := range cs.log {
+		if change.Sequence > request.Cursor {
+			categoryChangesResponse.Changes = append(categoryChangesResponse.Changes, change)
+			categoryChangesResponse.NextCursor = change.Sequence
+		}
+	}
+	return categoryChangesResponse
+}
+
+func (cs *categoryEventsStub) FindLatestChange(ctx context.Context) web.CategoryChangeResponse {
+	if len(cs.log) == 0 {
+		return web.CategoryChangeResponse{}
+	}
+	return cs.log[len(cs.log)-1]
+}
+
+func (cs *categoryEventsStub) Subscribe(ctx context.Context) (<-chan web.CategoryChangeResponse, func()) {
+	return cs.changes, func() {}
+}
+
+func TestCategoryEventsResumeFromLastEventId(t *testing.T) {
+	stub := &categoryEventsStub{
+		changes: make(chan web.CategoryChangeResponse, 2),
+		log: []web.CategoryChangeResponse{
+			{Sequence: 1, Operation: "created", CategoryId: 1},
+			{Sequence: 2, Operation: "updated", CategoryId: 1},
+			{Sequence: 3, Operation: "created", CategoryId: 2},
+		},
+	}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 3, Operation: "created", CategoryId: 2}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 4, Operation: "deleted", CategoryId: 1}
+	categoryController := controller.NewCategoryController(stub)
+
+	ctx, cancel := context.WithTimeout(adminContext(), 100*time.Millisecond)
+	defer cancel()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/events", nil).WithContext(ctx)
+	request.Header.Add("Last-Event-ID", "1")
+
+	recorder := httptest.NewRecorder()
+	categoryController.Events(recorder, request)
+
+	body := recorder.Body.String()
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	assert.NotContains(t, body, "id: 1\n")
+	assert.Contains(t, body, "id: 2\nevent: updated\n")
+	assert.Equal(t, 1, strings.Count(body, "id: 3\n"))
+	assert.Contains(t, body, "id: 4\nevent: deleted\n")
+}
+
+// reorderedEventsStub subscribes after change 1, and then receives change 3
+// before change 2, as when the transaction of change 3 runs its commit hooks
+// first.
+type reorderedEventsStub struct {
+	categoryEventsStub
+}
+
+func newReorderedEventsStub() *reorderedEventsStub {
+	stub := &reorderedEventsStub{categoryEventsStub{
+		changes: make(chan web.CategoryChangeResponse, 3),
+		log: []web.CategoryChangeResponse{
+			{Sequence: 1, Operation: "created", CategoryId: 1},
+			{Sequence: 2, Operation: "updated", CategoryId: 1},
+			{Sequence: 3, Operation: "created", CategoryId: 2},
+		},
+	}}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 3, Operation: "created", CategoryId: 2}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 2, Operation: "updated", CategoryId: 1}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 4, Operation: "deleted", CategoryId: 1}
+	return stub
+}
+
+func (cs *reorderedEventsStub) FindLatestChange(ctx context.Context) web.CategoryChangeResponse {
+	return cs.log[0]
+}
+
+func TestCategoryEventsOutOfOrderChanges(t *testing.T) {
+	categoryController := controller.NewCategoryController(newReorderedEventsStub())
+
+	ctx, cancel := context.WithTimeout(adminContext(), 100*time.Millisecond)
+	defer cancel()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/events", nil).WithContext(ctx)
+
+	recorder := httptest.NewRecorder()
+	categoryController.Events(recorder, request)
+
+	body := recorder.Body.String()
+	assert.NotContains(t, body, "id: 1\n")
+	for _, id := range []string{"id: 2\n", "id: 3\n", "id: 4\n"} {
+		assert.Equal(t, 1, strings.Count(body, id))
+	}
+	assert.Less(t, strings.Index(body, "id: 2\n"), strings.Index(body, "id: 3\n"))
+	assert.Less(t, strings.Index(body, "id: 3\n"), strings.Index(body, "id: 4\n"))
+}
+
+func TestCategoryEventsHeartbeat(t *testing.T) {
+	stub := &categoryEventsStub{changes: make(chan web.CategoryChangeResponse)}
+	categoryController := controller.NewCategoryController(stub)
+	categoryController.HeartbeatInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(adminContext(), 50*time.Millisecond)
+	defer cancel()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/events", nil).WithContext(ctx)
+
+	recorder := httptest.NewRecorder()
+	categoryController.Events(recorder, request)
+
+	assert.Contains(t, recorder.Body.String(), ": heartbeat\n\n")
+}
+
+// failingEventsStub cannot read the change feed.
+type failingEventsStub struct {
+	categoryEventsStub
+}
+
+func (cs *failingEventsStub) FindChanges(ctx context.Context, request web.CategoryChangesRequest) web.CategoryChangesResponse {
+	panic(errors.New("pq: connection refused"))
+}
+
+func TestCategoryEventsReplayFailureEndsStream(t *testing.T) {
+	stub := &failingEventsStub{categoryEventsStub{changes: make(chan web.CategoryChangeResponse)}}
+	h := exception.ErrorHandler(http.HandlerFunc(controller.NewCategoryController(stub).Events))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/events", nil).WithContext(adminContext())
+	request.Header.Add("Last-Event-ID", "1")
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "event: error\ndata: internal error\n\n", recorder.Body.String())
+}
+
+func TestCategoryEventsInvalidLastEventId(t *testing.T) {
+	stub := &categoryEventsStub{changes: make(chan web.CategoryChangeResponse)}
+	h := exception.ErrorHandler(http.HandlerFunc(controller.NewCategoryController(stub).Events))
+
+	for _, lastEventId := range []string{"x", "-1"} {
+		request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/events", nil).WithContext(adminContext())
+		request.Header.Add("Last-Event-ID", lastEventId)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, request)
+
+		assert.Equal(t, 400, recorder.Code, lastEventId)
+		assert.NotEqual(t, "text/event-stream", recorder.Header().Get("Content-Type"), lastEventId)
+	}
+}
+
+func TestCategoryChangeBrokerPerTenant(t *testing.T) {
+	broker := service.NewCategoryChangeBroker()
+	changes, unsubscribe := broker.Subscribe(1)
+	otherChanges, otherUnsubscribe := broker.Subscribe(2)
+	defer otherUnsubscribe()
+
+	broker.Publish(domain.CategoryChange{TenantId: 1, Sequence: 1})
+
+	assert.Equal(t, int64(1), (<-changes).Sequence)
+	assert.Len(t, otherChanges, 0)
+
+	unsubscribe()
+	_, ok := <-changes
+	assert.False(t, ok)
+}
+
+func TestCategoryChangeBrokerDropsSlowSubscriber(t *testing.T) {
+	broker := service.NewCategoryChangeBroker()
+	changes, unsubscribe := broker.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i < 100; i++ {
+		broker.Publish(domain.CategoryChange{TenantId: 1, Sequence: int64(i + 1)})
+	}
+
+	received := 0
+	for range changes {
+		received++
+	}
+	assert.Less(t, received, 100)
+}
@@ -0,0 +1,256 @@
+package test
+
+import (
+	"Data-Category/app"
+	"Data-Category/controller"
+	"Data-Category/exception"
+	"Data-Category/middleware"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/pb"
+	"Data-Category/service"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type tenantServiceStub struct {
+	service.TenantService
+}
+
+func (ts *tenantServiceStub) Authenticate(ctx context.Context, apiKey string) (domain.Principal, bool) {
+	if apiKey != "RAHASIA" {
+		return domain.Principal{}, false
+	}
+	return domain.Principal{TenantId: domain.DefaultTenantId, Subject: "admin", Admin: true}, true
+}
+
+type categoryGrpcStub struct {
+	categoryEventsStub
+	categories        []web.CategoryResponse
+	findAllRequests   []web.CategoryFindAllRequest
+	deleteAllRequests []web.CategoryDeleteAllRequest
+}
+
+func (cs *categoryGrpcStub) Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	if request.Name == "Taken" {
+		panic(exception.NewConflictError("category name is already used"))
+	}
+	return web.CategoryResponse{Id: 1, Name: request.Name}
+}
+
+// FindAll pages like the repository does.
+func (cs *categoryGrpcStub) FindAll(ctx context.Context, request web.CategoryFindAllRequest) []web.CategoryResponse {
+	cs.findAllRequests = append(cs.findAllRequests, request)
+	if request.Limit == 0 {
+		return cs.categories
+	}
+	categoryResponses := append([]web.CategoryResponse(nil), cs.categories...)
+	sort.Slice(categoryResponses, func(i, j int) bool {
+		return categoryResponses[i].Id < categoryResponses[j].Id
+	})
+	var page []web.CategoryResponse
+	for _, categoryResponse := range categoryResponses {
+		if categoryResponse.Id > request.AfterId && len(page) < request.Limit {
+			page = append(page, categoryResponse)
+		}
+	}
+	return page
+}
+
+func (cs *categoryGrpcStub) DeleteAll(ctx context.Context, request web.CategoryDeleteAllRequest) web.CategoryDeleteAllResponse {
+	cs.deleteAllRequests = append(cs.deleteAllRequests, request)
+	if request.DryRun {
+		expiresAt := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+		return web.CategoryDeleteAllResponse{DryRun: true, Count: 2, ConfirmationToken: "token", ExpiresAt: &expiresAt}
+	}
+	if request.ConfirmationToken == "stale" {
+		panic(exception.NewAbortedError("categories changed since the dry run"))
+	}
+	if request.ConfirmationToken != "token" {
+		panic(exception.NewUnprocessableEntityError("confirmation_token is invalid"))
+	}
+	return web.CategoryDeleteAllResponse{Count: 2}
+}
+
+func (cs *categoryGrpcStub) UpdateById(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse {
+	panic(errors.New("pq: duplicate key value violates unique constraint \"data_category_pkey\""))
+}
+
+func (cs *categoryGrpcStub) DeleteById(ctx context.Context, categoryId int) {
+	panic(exception.NewFailedPreconditionError("category has child categories"))
+}
+
+func (cs *categoryGrpcStub) FindById(ctx context.Context, categoryId int) web.CategoryResponse {
+	panic(exception.NewNotFoundError("category is not found"))
+}
+
+func setupGrpcClient(t *testing.T, categoryService service.CategoryService) pb.CategoryServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	s := app.NewGrpcServer(controller.NewCategoryGrpcServer(categoryService), middleware.NewAuthInterceptor(&tenantServiceStub{}))
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewCategoryServiceClient(conn)
+}
+
+func grpcAdminContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), middleware.ApiKeyMetadata, "RAHASIA")
+}
+
+func TestGrpcUnauthenticated(t *testing.T) {
+	client := setupGrpcClient(t, &categoryGrpcStub{})
+
+	_, err := client.Get(context.Background(), &pb.GetCategoryRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestGrpcStatusCodes(t *testing.T) {
+	client := setupGrpcClient(t, &categoryGrpcStub{})
+
+	_, err := client.Get(grpcAdminContext(), &pb.GetCategoryRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Create(grpcAdminContext(), &pb.CreateCategoryRequest{Name: ""})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Create(grpcAdminContext(), &pb.CreateCategoryRequest{Name: "Taken"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.Delete(grpcAdminContext(), &pb.DeleteCategoryRequest{Id: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, "category has child categories", status.Convert(err).Message())
+
+	_, err = client.DeleteAll(grpcAdminContext(), &pb.DeleteAllCategoriesRequest{ConfirmationToken: "stale"})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	category, err := client.Create(grpcAdminContext(), &pb.CreateCategoryRequest{Name: "Gadget"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Gadget", category.Name)
+}
+
+func TestGrpcInternalErrorsAreNotExposed(t *testing.T) {
+	client := setupGrpcClient(t, &categoryGrpcStub{})
+
+	_, err := client.Update(grpcAdminContext(), &pb.UpdateCategoryRequest{Id: 1, Name: "Gadget"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+}
+
+func TestGrpcListPagination(t *testing.T) {
+	stub := &categoryGrpcStub{categories: []web.CategoryResponse{{Id: 3}, {Id: 1}, {Id: 2}}}
+	client := setupGrpcClient(t, stub)
+
+	page, err := client.List(grpcAdminContext(), &pb.ListCategoriesRequest{PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Categories, 2)
+	assert.Equal(t, int64(1), page.Categories[0].Id)
+	assert.Equal(t, "2", page.NextPageToken)
+
+	page, err = client.List(grpcAdminContext(), &pb.ListCategoriesRequest{PageSize: 2, PageToken: page.NextPageToken})
+	assert.NoError(t, err)
+	assert.Len(t, page.Categories, 1)
+	assert.Equal(t, int64(3), page.Categories[0].Id)
+	assert.Empty(t, page.NextPageToken)
+
+	_, err = client.List(grpcAdminContext(), &pb.ListCategoriesRequest{PageToken: "x"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.Equal(t, []web.CategoryFindAllRequest{{Limit: 3}, {AfterId: 2, Limit: 3}}, stub.findAllRequests)
+}
+
+func TestGrpcDeleteAllTwoSteps(t *testing.T) {
+	stub := &categoryGrpcStub{}
+	client := setupGrpcClient(t, stub)
+
+	dryRun, err := client.DeleteAll(grpcAdminContext(), &pb.DeleteAllCategoriesRequest{DryRun: true, TenantId: 2, SubtreeId: 3, Name: "gad*"})
+	assert.NoError(t, err)
+	assert.True(t, dryRun.DryRun)
+	assert.Equal(t, int64(2), dryRun.Count)
+	assert.Equal(t, "token", dryRun.ConfirmationToken)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC), dryRun.ExpiresAt.AsTime())
+
+	_, err = client.DeleteAll(grpcAdminContext(), &pb.DeleteAllCategoriesRequest{TenantId: 2, SubtreeId: 3, Name: "gad*"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	deleted, err := client.DeleteAll(grpcAdminContext(), &pb.DeleteAllCategoriesRequest{ConfirmationToken: dryRun.ConfirmationToken, TenantId: 2, SubtreeId: 3, Name: "gad*"})
+	assert.NoError(t, err)
+	assert.False(t, deleted.DryRun)
+	assert.Equal(t, int64(2), deleted.Count)
+	assert.Nil(t, deleted.ExpiresAt)
+
+	assert.Equal(t, web.CategoryDeleteAllRequest{TenantId: 2, SubtreeId: 3, Name: "gad*", DryRun: true}, stub.deleteAllRequests[0])
+	assert.Equal(t, web.CategoryDeleteAllRequest{TenantId: 2, SubtreeId: 3, Name: "gad*", ConfirmationToken: "token"}, stub.deleteAllRequests[2])
+}
+
+func TestGrpcWatchResumes(t *testing.T) {
+	stub := &categoryGrpcStub{categoryEventsStub: categoryEventsStub{
+		changes: make(chan web.CategoryChangeResponse, 2),
+		log: []web.CategoryChangeResponse{
+			{Sequence: 1, Operation: "created", CategoryId: 1},
+			{Sequence: 2, Operation: "updated", CategoryId: 1},
+		},
+	}}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 2, Operation: "updated", CategoryId: 1}
+	stub.changes <- web.CategoryChangeResponse{Sequence: 3, Operation: "deleted", CategoryId: 1}
+	close(stub.changes)
+	client := setupGrpcClient(t, stub)
+
+	lastSequence := int64(1)
+	stream, err := client.Watch(grpcAdminContext(), &pb.WatchCategoriesRequest{LastSequence: &lastSequence})
+	assert.NoError(t, err)
+
+	var sequences []int64
+	for {
+		change, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		sequences = append(sequences, change.Sequence)
+	}
+	assert.Equal(t, []int64{2, 3}, sequences)
+}
+
+func TestGrpcWatchOutOfOrderChanges(t *testing.T) {
+	stub := newReorderedEventsStub()
+	close(stub.changes)
+	client := setupGrpcClient(t, stub)
+
+	stream, err := client.Watch(grpcAdminContext(), &pb.WatchCategoriesRequest{})
+	assert.NoError(t, err)
+
+	var sequences []int64
+	for {
+		change, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		sequences = append(sequences, change.Sequence)
+	}
+	assert.Equal(t, []int64{2, 3, 4}, sequences)
+}
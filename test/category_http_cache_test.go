@@ -0,0 +1,66 @@
+package test
+
+import (
+	"Data-Category/middleware"
+	"Data-Category/model/web"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveConditional(target string, header string, value string) *http.Response {
+	stub := &categoryOpenApiStub{categoryEventsStub{log: []web.CategoryChangeResponse{
+		{Sequence: 1, Operation: "created", CategoryId: 1, ChangedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Sequence: 2, Operation: "updated", CategoryId: 1, ChangedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}}}
+	r := setupStubRouter(stub, middleware.OpenApiConfig{})
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000"+target, nil).WithContext(adminContext())
+	if header != "" {
+		request.Header.Add(header, value)
+	}
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func TestFindAllCachingHeaders(t *testing.T) {
+	response := serveConditional("/api/categories", "", "")
+
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, `W/"1-2"`, response.Header.Get("ETag"))
+	assert.Equal(t, "Tue, 02 Jan 2024 00:00:00 GMT", response.Header.Get("Last-Modified"))
+	assert.Equal(t, "no-cache", response.Header.Get("Cache-Control"))
+
+	filtered := serveConditional("/api/categories?updated_since=2024-01-01T00:00:00Z", "", "")
+	assert.NotEqual(t, response.Header.Get("ETag"), filtered.Header.Get("ETag"))
+}
+
+func TestFindAllNotModified(t *testing.T) {
+	tests := []struct {
+		header string
+		value  string
+		status int
+	}{
+		{"If-None-Match", `W/"1-2"`, 304},
+		{"If-None-Match", `"1-2"`, 304},
+		{"If-None-Match", `W/"1-1", W/"1-2"`, 304},
+		{"If-None-Match", `W/"1-1"`, 200},
+		{"If-Modified-Since", "Tue, 02 Jan 2024 00:00:00 GMT", 304},
+		{"If-Modified-Since", "Mon, 01 Jan 2024 00:00:00 GMT", 200},
+	}
+
+	for _, test := range tests {
+		response := serveConditional("/api/categories", test.header, test.value)
+
+		assert.Equal(t, test.status, response.StatusCode, test.header+": "+test.value)
+		if test.status == 304 {
+			body, _ := io.ReadAll(response.Body)
+			assert.Empty(t, body)
+			assert.Equal(t, `W/"1-2"`, response.Header.Get("ETag"))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"Data-Category/model/domain"
+	"Data-Category/repository"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCategorySearcher() *repository.CategorySearcherInMemory {
+	electronics, phones := 1, 2
+	searcher := repository.NewCategorySearcherInMemory()
+	searcher.Add(1,
+		domain.Category{Id: 1, Name: "Electronics", Slug: "electronics"},
+		domain.Category{Id: 2, Name: "Mobile Phones", Slug: "mobile-phones", ParentId: &electronics},
+		domain.Category{Id: 3, Name: "Phone Cases", Slug: "phone-cases", ParentId: &phones},
+		domain.Category{Id: 4, Name: "Garden", Slug: "garden"},
+		domain.Category{Id: 5, Name: "Phone Stands", Slug: "phone-stands"},
+	)
+	searcher.Add(2, domain.Category{Id: 6, Name: "Phone Cases", Slug: "phone-cases"})
+	searcher.AddAliases(2, "Cellphone", "Handphone")
+	return searcher
+}
+
+func TestCategorySearchRanksAndHighlights(t *testing.T) {
+	results := setupCategorySearcher().Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "phone cases", Limit: 10})
+
+	assert.NotEmpty(t, results)
+	assert.Equal(t, 3, results[0].Category.Id)
+	assert.Equal(t, "<mark>Phone</mark> <mark>Cases</mark>", results[0].Highlight)
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+	}
+}
+
+func TestCategorySearchEscapesHighlight(t *testing.T) {
+	searcher := repository.NewCategorySearcherInMemory()
+	searcher.Add(1, domain.Category{Id: 1, Name: `<img src=x onerror="alert(1)"> Phone & Co`, Slug: "phone"})
+
+	results := searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "phone", Limit: 10})
+	assert.Len(t, results, 1)
+	assert.Equal(t, `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>Phone</mark> &amp; Co`, results[0].Highlight)
+}
+
+func TestCategorySearchToleratesTypos(t *testing.T) {
+	results := setupCategorySearcher().Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "electronis", Limit: 10})
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Category.Id)
+	assert.Equal(t, "Electronics", results[0].Highlight)
+	assert.Greater(t, results[0].Score, 0.3)
+}
+
+func TestCategorySearchSubtree(t *testing.T) {
+	results := setupCategorySearcher().Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "phone", SubtreeId: 1, Limit: 10})
+
+	var ids []int
+	for _, result := range results {
+		ids = append(ids, result.Category.Id)
+	}
+	assert.ElementsMatch(t, []int{2, 3}, ids)
+}
+
+func TestCategorySearchTenantAndLimit(t *testing.T) {
+	searcher := setupCategorySearcher()
+
+	results := searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 2, Query: "phone", Limit: 10})
+	assert.Len(t, results, 1)
+	assert.Equal(t, 6, results[0].Category.Id)
+
+	results = searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "phone", Limit: 2})
+	assert.Len(t, results, 2)
+
+	results = searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 3, Query: "phone", Limit: 10})
+	assert.Empty(t, results)
+}
+
+func TestCategorySearchResolvesAliases(t *testing.T) {
+	searcher := setupCategorySearcher()
+
+	results := searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "cellphone", Limit: 10})
+	assert.Len(t, results, 1)
+	assert.Equal(t, 2, results[0].Category.Id)
+	assert.Equal(t, "Cellphone", results[0].Alias)
+	assert.Equal(t, "Mobile Phones", results[0].Highlight)
+
+	results = searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "handfone", Limit: 10})
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Handphone", results[0].Alias)
+
+	results = searcher.Search(context.Background(), nil, domain.CategorySearch{TenantId: 1, Query: "mobile", Limit: 10})
+	assert.Equal(t, 2, results[0].Category.Id)
+	assert.Empty(t, results[0].Alias)
+}
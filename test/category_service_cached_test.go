@@ -0,0 +1,228 @@
+package test
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type categoryServiceStub struct {
+	service.CategoryService
+	findByIdCalls int32
+	findAllCalls  int32
+	delay         time.Duration
+	name          string
+}
+
+func (cs *categoryServiceStub) FindById(ctx context.Context, categoryId int) web.CategoryResponse {
+	atomic.AddInt32(&cs.findByIdCalls, 1)
+	time.Sleep(cs.delay)
+	helper.MustPrincipalFromContext(ctx)
+	helper.PanicIfError(ctx.Err())
+	if categoryId == 404 {
+		panic(exception.NewNotFoundError("category is not found"))
+	}
+	return web.CategoryResponse{Id: categoryId, Name: cs.name}
+}
+
+func (cs *categoryServiceStub) FindAll(ctx context.Context, request web.CategoryFindAllRequest) []web.CategoryResponse {
+	atomic.AddInt32(&cs.findAllCalls, 1)
+	return []web.CategoryResponse{{Id: 1, Name: cs.name}}
+}
+
+func (cs *categoryServiceStub) UpdateById(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse {
+	cs.name = request.Name
+	return web.CategoryResponse{Id: request.Id, Name: request.Name}
+}
+
+func (cs *categoryServiceStub) DeleteById(ctx context.Context, categoryId int) {
+}
+
+func setupCategoryServiceCached(stub *categoryServiceStub, enabled bool) *service.CategoryServiceCached {
+	return service.NewCategoryServiceCached(stub, service.CategoryCacheConfig{
+		Enabled:    enabled,
+		Size:       10,
+		TTL:        time.Minute,
+		MaxLocales: 2,
+	})
+}
+
+func TestCategoryCacheHit(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+
+	categoryService.FindById(adminContext(), 1)
+	categoryResponse := categoryService.FindById(adminContext(), 1)
+	categoryService.FindAll(adminContext(), web.CategoryFindAllRequest{})
+	categoryService.FindAll(adminContext(), web.CategoryFindAllRequest{})
+
+	assert.Equal(t, "Gadget", categoryResponse.Name)
+	assert.Equal(t, int32(1), stub.findByIdCalls)
+	assert.Equal(t, int32(1), stub.findAllCalls)
+
+	stats := categoryService.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, 2, stats.Size)
+}
+
+func TestCategoryCacheInvalidateOnUpdate(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindAll(adminContext(), web.CategoryFindAllRequest{})
+	categoryService.UpdateById(adminContext(), web.CategoryUpdateRequest{Id: 1, Name: "Gadgetin"})
+
+	assert.Equal(t, "Gadgetin", categoryService.FindById(adminContext(), 1).Name)
+	assert.Equal(t, "Gadgetin", categoryService.FindAll(adminContext(), web.CategoryFindAllRequest{})[0].Name)
+	assert.Equal(t, int32(2), stub.findByIdCalls)
+	assert.Equal(t, int32(2), stub.findAllCalls)
+}
+
+func TestCategoryCachePurgedOnDelete(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+
+	// Deleting a category renumbers its siblings, so they are loaded again.
+	categoryService.FindById(adminContext(), 2)
+	categoryService.DeleteById(adminContext(), 1)
+	categoryService.FindById(adminContext(), 2)
+
+	assert.Equal(t, int32(2), stub.findByIdCalls)
+}
+
+func TestCategoryCacheWrapsAnyCategoryService(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	inner := setupCategoryServiceCached(stub, true)
+	outer := service.NewCategoryServiceCached(inner, service.CategoryCacheConfig{Enabled: true, Size: 10, TTL: time.Minute})
+
+	outer.FindById(adminContext(), 1)
+	outer.UpdateById(adminContext(), web.CategoryUpdateRequest{Id: 1, Name: "Gadgetin"})
+
+	assert.Equal(t, "Gadgetin", outer.FindById(adminContext(), 1).Name)
+	assert.Equal(t, int32(2), stub.findByIdCalls)
+}
+
+func TestCategoryCacheInvalidate(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+	localeContext := helper.WithLocales(adminContext(), []string{"id"})
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindById(localeContext, 1)
+	categoryService.FindById(adminContext(), 2)
+	categoryService.Invalidate(adminContext(), 1)
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindById(localeContext, 1)
+	categoryService.FindById(adminContext(), 2)
+	assert.Equal(t, int32(5), stub.findByIdCalls)
+}
+
+func TestCategoryCacheKeyedByLocale(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+	localeContext := helper.WithLocales(adminContext(), []string{"id"})
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindById(localeContext, 1)
+	categoryService.FindById(localeContext, 1)
+	assert.Equal(t, int32(2), stub.findByIdCalls)
+
+	categoryService.UpdateById(adminContext(), web.CategoryUpdateRequest{Id: 1, Name: "Gadgetin"})
+	assert.Equal(t, "Gadgetin", categoryService.FindById(localeContext, 1).Name)
+	assert.Equal(t, int32(3), stub.findByIdCalls)
+}
+
+func TestCategoryCacheBoundsLocales(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+
+	for _, locale := range []string{"id", "en", "fr", "id", "en", "fr"} {
+		categoryService.FindById(helper.WithLocales(adminContext(), []string{locale}), 1)
+	}
+	// id and en are cached, fr is passed through every time.
+	assert.Equal(t, int32(4), stub.findByIdCalls)
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindById(adminContext(), 1)
+	assert.Equal(t, int32(5), stub.findByIdCalls)
+}
+
+func TestCategoryCacheCoalescesMisses(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget", delay: 50 * time.Millisecond}
+	categoryService := setupCategoryServiceCached(stub, true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			categoryService.FindById(adminContext(), 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), stub.findByIdCalls)
+}
+
+func TestCategoryCacheSharedLoadOutlivesCancelledCaller(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget", delay: 50 * time.Millisecond}
+	categoryService := setupCategoryServiceCached(stub, true)
+	ctx, cancel := context.WithCancel(adminContext())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() { recover() }()
+		categoryService.FindById(ctx, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	assert.Equal(t, "Gadget", categoryService.FindById(adminContext(), 1).Name)
+	wg.Wait()
+	assert.Equal(t, int32(1), stub.findByIdCalls)
+}
+
+func TestCategoryCacheNotFound(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+
+	assert.PanicsWithValue(t, exception.NewNotFoundError("category is not found"), func() {
+		categoryService.FindById(adminContext(), 404)
+	})
+}
+
+func TestCategoryCacheDisabled(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, false)
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindById(adminContext(), 1)
+
+	assert.Equal(t, int32(2), stub.findByIdCalls)
+	assert.Equal(t, 0, categoryService.Stats().Size)
+}
+
+func TestCategoryCacheIsolatedPerTenant(t *testing.T) {
+	stub := &categoryServiceStub{name: "Gadget"}
+	categoryService := setupCategoryServiceCached(stub, true)
+	otherTenantContext := helper.WithPrincipal(context.Background(), domain.Principal{TenantId: 2})
+
+	categoryService.FindById(adminContext(), 1)
+	categoryService.FindById(otherTenantContext, 1)
+
+	assert.Equal(t, int32(2), stub.findByIdCalls)
+}
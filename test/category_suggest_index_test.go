@@ -0,0 +1,93 @@
+package test
+
+import (
+	"Data-Category/model/domain"
+	"Data-Category/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func suggestNames(categories []domain.Category) []string {
+	names := []string{}
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+	return names
+}
+
+func setupSuggestIndex() *service.CategorySuggestIndex {
+	index := service.NewCategorySuggestIndex(nil, nil, nil, nil, service.CategorySuggestConfig{})
+	index.Put(1, domain.Category{Id: 1, Name: "Électronique", Slug: "electronique"})
+	index.Put(1, domain.Category{Id: 2, Name: "Mobile Phones", Slug: "mobile-phones"})
+	index.Put(1, domain.Category{Id: 3, Name: "Phone Cases", Slug: "phone-cases"})
+	index.Put(1, domain.Category{Id: 4, Name: "Photography", Slug: "photography"})
+	index.Put(2, domain.Category{Id: 5, Name: "Phone Stands", Slug: "phone-stands"})
+	return index
+}
+
+func TestCategorySuggestIgnoresCaseAndAccents(t *testing.T) {
+	index := setupSuggestIndex()
+
+	assert.Equal(t, []string{"Électronique"}, suggestNames(index.Suggest(1, "ELEC", 10)))
+	assert.Equal(t, []string{"Électronique"}, suggestNames(index.Suggest(1, "élec", 10)))
+	assert.Empty(t, index.Suggest(1, "  ", 10))
+}
+
+func TestCategorySuggestMatchesWordStartsAndRanksNameStarts(t *testing.T) {
+	index := setupSuggestIndex()
+
+	assert.Equal(t, []string{"Phone Cases", "Photography", "Mobile Phones"}, suggestNames(index.Suggest(1, "pho", 10)))
+	assert.Equal(t, []string{"Phone Cases", "Mobile Phones"}, suggestNames(index.Suggest(1, "phone", 10)))
+	assert.Equal(t, []string{"Phone Cases"}, suggestNames(index.Suggest(1, "phone c", 10)))
+	assert.Equal(t, []string{"Phone Cases", "Photography"}, suggestNames(index.Suggest(1, "pho", 2)))
+	assert.Equal(t, []string{"Phone Stands"}, suggestNames(index.Suggest(2, "pho", 10)))
+}
+
+func TestCategorySuggestFollowsChangeFeed(t *testing.T) {
+	index := setupSuggestIndex()
+
+	index.Apply(1, []domain.CategoryChange{
+		{Sequence: 1, Operation: domain.CategoryCreated, Category: domain.Category{Id: 6, Name: "Phono Cartridges"}},
+		{Sequence: 2, Operation: domain.CategoryUpdated, Category: domain.Category{Id: 3, Name: "Cases"}},
+	})
+	assert.Equal(t, []string{"Phono Cartridges"}, suggestNames(index.Suggest(1, "phono", 10)))
+	assert.Equal(t, []string{"Mobile Phones"}, suggestNames(index.Suggest(1, "phone", 10)))
+	assert.Equal(t, []string{"Cases"}, suggestNames(index.Suggest(1, "cas", 10)))
+
+	// Changes that were applied already are skipped, so a stale state read
+	// again cannot undo a later one.
+	index.Apply(1, []domain.CategoryChange{
+		{Sequence: 2, Operation: domain.CategoryUpdated, Category: domain.Category{Id: 3, Name: "Phone Cases"}},
+		{Sequence: 3, Operation: domain.CategoryDeleted, Category: domain.Category{Id: 2, Name: "Mobile Phones"}},
+		{Sequence: 4, Operation: domain.CategoryMerged, Category: domain.Category{Id: 6, Name: "Phono Cartridges"}, MergedIntoId: 4},
+	})
+	assert.Equal(t, []string{"Cases"}, suggestNames(index.Suggest(1, "cas", 10)))
+	assert.Empty(t, index.Suggest(1, "mob", 10))
+	assert.Empty(t, index.Suggest(1, "phono", 10))
+
+	// The cursors are kept per tenant.
+	index.Apply(2, []domain.CategoryChange{
+		{Sequence: 1, Operation: domain.CategoryDeleted, Category: domain.Category{Id: 5, Name: "Phone Stands"}},
+	})
+	assert.Empty(t, index.Suggest(2, "phone", 10))
+}
+
+func TestCategorySuggestLoadMatchesPut(t *testing.T) {
+	index := service.NewCategorySuggestIndex(nil, nil, nil, nil, service.CategorySuggestConfig{})
+	index.Put(1, domain.Category{Id: 7, Name: "Phonograph"})
+	index.Load(1, []domain.Category{
+		{Id: 4, Name: "Photography", Slug: "photography"},
+		{Id: 3, Name: "Phone Cases", Slug: "phone-cases"},
+		{Id: 2, Name: "Mobile Phones", Slug: "mobile-phones"},
+		{Id: 1, Name: "Électronique", Slug: "electronique"},
+	})
+	put := setupSuggestIndex()
+
+	for _, prefix := range []string{"pho", "phone", "phone c", "mob", "elec", "cas"} {
+		assert.Equal(t, suggestNames(put.Suggest(1, prefix, 10)), suggestNames(index.Suggest(1, prefix, 10)), prefix)
+	}
+
+	index.Put(1, domain.Category{Id: 3, Name: "Cases"})
+	assert.Equal(t, []string{"Mobile Phones"}, suggestNames(index.Suggest(1, "phone", 10)))
+}
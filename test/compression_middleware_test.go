@@ -0,0 +1,113 @@
+package test
+
+import (
+	"Data-Category/middleware"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+var largeBody = strings.Repeat(`{"id":1,"name":"Gadget","slug":"gadget"},`, 100)
+
+func serveCompressed(acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	h := middleware.NewCompressionMiddleware(middleware.CompressionConfig{MinSize: 1024}).Wrap(handler)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories", nil)
+	request.Header.Add("Accept-Encoding", acceptEncoding)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func writeBody(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func TestCompressionNegotiatesEncoding(t *testing.T) {
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"zstd": func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, zstd", "zstd"},
+		{"*", "br"},
+		{"br;q=0, *;q=0.1", "zstd"},
+	}
+
+	for _, test := range tests {
+		recorder := serveCompressed(test.acceptEncoding, writeBody(largeBody))
+
+		assert.Equal(t, test.encoding, recorder.Header().Get("Content-Encoding"), test.acceptEncoding)
+		assert.Contains(t, recorder.Header().Values("Vary"), "Accept-Encoding")
+		assert.Less(t, recorder.Body.Len(), len(largeBody))
+
+		reader, err := decoders[test.encoding](recorder.Body)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, largeBody, string(body))
+	}
+}
+
+func TestCompressionIdentity(t *testing.T) {
+	for _, acceptEncoding := range []string{"", "identity", "gzip;q=0"} {
+		recorder := serveCompressed(acceptEncoding, writeBody(largeBody))
+
+		assert.Empty(t, recorder.Header().Get("Content-Encoding"), acceptEncoding)
+		assert.Equal(t, largeBody, recorder.Body.String())
+	}
+}
+
+func TestCompressionSkipsSmallBodies(t *testing.T) {
+	recorder := serveCompressed("gzip", writeBody(`{"code":200}`))
+
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"code":200}`, recorder.Body.String())
+}
+
+func TestCompressionKeepsStatusCode(t *testing.T) {
+	recorder := serveCompressed("gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, largeBody)
+	})
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+}
+
+func TestCompressionDoesNotBufferStreams(t *testing.T) {
+	recorder := serveCompressed("gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, largeBody)
+		w.(http.Flusher).Flush()
+	})
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.True(t, recorder.Flushed)
+
+	var flushed bytes.Buffer
+	recorder = serveCompressed("gzip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "id: 1\n\n")
+		w.(http.Flusher).Flush()
+		flushed.WriteString(w.Header().Get("Content-Encoding"))
+		_, _ = io.WriteString(w, largeBody)
+	})
+	assert.Empty(t, flushed.String())
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "id: 1\n\n"+largeBody, recorder.Body.String())
+}
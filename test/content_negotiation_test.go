@@ -0,0 +1,164 @@
+package test
+
+import (
+	"Data-Category/helper"
+	"Data-Category/middleware"
+	"Data-Category/model/web"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveNegotiated(method string, target string, contentType string, accept string, body io.Reader) *http.Response {
+	r := setupStubRouter(&categoryOpenApiStub{}, middleware.OpenApiConfig{})
+	request := httptest.NewRequest(method, "http://localhost:3000"+target, body).WithContext(adminContext())
+	if contentType != "" {
+		request.Header.Add("Content-Type", contentType)
+	}
+	request.Header.Add("Accept", accept)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func TestCodecNegotiateQualityValues(t *testing.T) {
+	registry := helper.NewCodecRegistry()
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/xml;q=0.5, text/csv", "text/csv"},
+		{"*/*;q=0.1, application/msgpack;q=0.2", "application/msgpack"},
+		{"application/json;q=0, */*", "application/xml"},
+		{"text/*, application/json;q=0.9", "text/csv"},
+	}
+	for _, test := range tests {
+		codec, ok := registry.Negotiate(test.accept)
+		assert.True(t, ok, test.accept)
+		assert.Equal(t, test.contentType, codec.ContentType(), test.accept)
+	}
+
+	_, ok := registry.Negotiate("text/html, application/json;q=0")
+	assert.False(t, ok)
+}
+
+func TestXmlResponse(t *testing.T) {
+	response := serveNegotiated(http.MethodGet, "/api/categories/1", "", "application/xml", nil)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "application/xml", response.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "<response><code>200</code><status>OK</status><data><id>1</id><name>Gadget</name><slug>gadget</slug>")
+}
+
+func TestXmlErrorResponse(t *testing.T) {
+	response := serveNegotiated(http.MethodGet, "/api/categories/2", "", "application/xml", nil)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, "application/xml", response.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "<response><code>404</code><status>Not Found</status><data>category is not found</data></response>")
+}
+
+func TestCsvResponse(t *testing.T) {
+	response := serveNegotiated(http.MethodGet, "/api/categories", "", "text/csv", nil)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "text/csv", response.Header.Get("Content-Type"))
+	assert.Equal(t, "id,name,slug,parent_id,position,attributes.icon,item_count,aliases,created_at,updated_at,created_by,updated_by\n"+
+		"1,Gadget,gadget,,0,gadget,0,\"[\"\"Gizmo\"\"]\",2024-01-01T00:00:00Z,2024-01-01T00:00:00Z,admin,admin\n", string(body))
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	codec := helper.MsgpackCodec{}
+	var body bytes.Buffer
+	assert.NoError(t, codec.Encode(&body, web.CategoryCreateRequest{Name: "Gadget"}))
+
+	response := serveNegotiated(http.MethodPost, "/api/categories", "application/msgpack", "application/msgpack", &body)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "application/msgpack", response.Header.Get("Content-Type"))
+
+	webResponse := struct {
+		Code int                  `json:"code"`
+		Data web.CategoryResponse `json:"data"`
+	}{}
+	assert.NoError(t, codec.Decode(response.Body, &webResponse))
+	assert.Equal(t, 200, webResponse.Code)
+	assert.Equal(t, "Gadget", webResponse.Data.Name)
+	assert.Equal(t, 1, webResponse.Data.Id)
+}
+
+func TestDecodeRequestBodyByContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/xml", "<category><name>Gadget</name></category>"},
+		{"text/csv", "name\nGadget\n"},
+		{"application/json; charset=utf-8", `{"name":"Gadget"}`},
+	}
+	for _, test := range tests {
+		response := serveNegotiated(http.MethodPost, "/api/categories", test.contentType, "application/json", strings.NewReader(test.body))
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, 200, response.StatusCode, test.contentType)
+		assert.Contains(t, string(body), `"name":"Gadget"`, test.contentType)
+	}
+}
+
+func TestImportCategoryAliases(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/xml", "<category><name>Gadget</name><aliases><item>Gizmo</item><item>Gawai</item></aliases></category>"},
+		{"text/csv", "name,aliases\nGadget,\"[\"\"Gizmo\"\",\"\"Gawai\"\"]\"\n"},
+		{"application/json", `{"name":"Gadget","aliases":["Gizmo","Gawai"]}`},
+	}
+	for _, test := range tests {
+		response := serveNegotiated(http.MethodPost, "/api/categories", test.contentType, "application/json", strings.NewReader(test.body))
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, 200, response.StatusCode, test.contentType)
+		assert.Contains(t, string(body), `"aliases":["Gizmo","Gawai"]`, test.contentType)
+	}
+}
+
+func TestNotAcceptable(t *testing.T) {
+	response := serveNegotiated(http.MethodGet, "/api/categories", "", "text/html", nil)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, 406, response.StatusCode)
+	assert.Contains(t, string(body), "application/xml")
+}
+
+func TestUnsupportedMediaType(t *testing.T) {
+	response := serveNegotiated(http.MethodPost, "/api/categories", "text/plain", "", strings.NewReader("Gadget"))
+
+	assert.Equal(t, 415, response.StatusCode)
+}
+
+func TestEventStreamIsAcceptable(t *testing.T) {
+	handler := middleware.NewContentNegotiationMiddleware(helper.NewCodecRegistry()).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := w.(http.Flusher)
+		assert.True(t, ok)
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/events", nil)
+	request.Header.Add("Accept", "text/event-stream")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"Data-Category/app"
+	"Data-Category/event"
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"Data-Category/service"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type eventRecorder struct {
+	mutex  sync.Mutex
+	events []event.Event
+}
+
+func (er *eventRecorder) Handle(ctx context.Context, e event.Event) {
+	er.mutex.Lock()
+	defer er.mutex.Unlock()
+	er.events = append(er.events, e)
+}
+
+func TestSyncEventBusRecoversSubscriberPanic(t *testing.T) {
+	recorder := &eventRecorder{}
+	bus := event.NewSyncEventBus([]event.Subscriber{
+		event.SubscriberFunc(func(ctx context.Context, e event.Event) {
+			panic("subscriber failed")
+		}),
+		recorder,
+	})
+
+	bus.Publish(context.Background(), event.CategoryCreated{TenantId: 1})
+
+	assert.Equal(t, []event.Event{event.CategoryCreated{TenantId: 1}}, recorder.events)
+}
+
+func TestAsyncEventBusDeliversInOrder(t *testing.T) {
+	recorder := &eventRecorder{}
+	bus := event.NewAsyncEventBus([]event.Subscriber{recorder}, 4)
+
+	for i := 1; i <= 10; i++ {
+		bus.Publish(context.Background(), event.CategoryDeleted{TenantId: i})
+	}
+	bus.Close()
+
+	assert.Len(t, recorder.events, 10)
+	for i, e := range recorder.events {
+		assert.Equal(t, i+1, e.(event.CategoryDeleted).TenantId)
+	}
+}
+
+func TestCategoryServicePublishesEventsAfterCommit(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+
+	recorder := &eventRecorder{}
+	categoryService := service.NewCategoryService(repository.NewCategoryRepository(helper.NewClock()), repository.NewCategoryAliasRepository(helper.NewClock()), repository.NewCategoryTranslationRepository(helper.NewClock()), repository.NewItemRepository(helper.NewClock()), repository.NewCategoryChangeRepository(helper.NewClock()),
+		repository.NewWebhookRepository(helper.NewClock()), service.NewCategoryChangeBroker(), event.NewSyncEventBus([]event.Subscriber{recorder}), service.NewCategorySuggestIndex(nil, nil, nil, db, service.CategorySuggestConfig{}), newCategoryDeleteGuard(), db, app.NewValidator())
+	ctx := adminContext()
+
+	category := categoryService.Create(ctx, web.CategoryCreateRequest{Name: "Gadget"})
+	assert.Panics(t, func() {
+		categoryService.Create(ctx, web.CategoryCreateRequest{Name: "Gadget"})
+	})
+	categoryService.UpdateById(ctx, web.CategoryUpdateRequest{Id: category.Id, Name: "Laptop"})
+	categoryService.DeleteById(ctx, category.Id)
+	categoryService.Create(ctx, web.CategoryCreateRequest{Name: "Phone"})
+	dryRun := categoryService.DeleteAll(ctx, web.CategoryDeleteAllRequest{DryRun: true})
+	categoryService.DeleteAll(ctx, web.CategoryDeleteAllRequest{ConfirmationToken: dryRun.ConfirmationToken})
+
+	assert.Len(t, recorder.events, 5)
+	assert.Equal(t, "Gadget", recorder.events[0].(event.CategoryCreated).Category.Name)
+	renamed := recorder.events[1].(event.CategoryRenamed)
+	assert.Equal(t, "Gadget", renamed.OldName)
+	assert.Equal(t, "laptop", renamed.Category.Slug)
+	assert.Equal(t, category.Id, recorder.events[2].(event.CategoryDeleted).Category.Id)
+	assert.Len(t, recorder.events[4].(event.CategoriesPurged).Categories, 1)
+}
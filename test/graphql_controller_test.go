@@ -0,0 +1,161 @@
+package test
+
+import (
+	"Data-Category/controller"
+	"Data-Category/graph"
+	"Data-Category/model/web"
+	"Data-Category/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type categoryGraphqlStub struct {
+	categoryGrpcStub
+	findByIdsCalls         int32
+	findChildrenByIdsCalls int32
+	findAliasesByIdsCalls  int32
+}
+
+func (cs *categoryGraphqlStub) FindByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse {
+	atomic.AddInt32(&cs.findByIdsCalls, 1)
+	var categoryResponses []web.CategoryResponse
+	for _, categoryId := range categoryIds {
+		categoryResponses = append(categoryResponses, web.CategoryResponse{Id: categoryId, Name: "Category"})
+	}
+	return categoryResponses
+}
+
+func (cs *categoryGraphqlStub) FindChildrenByIds(ctx context.Context, categoryIds []int) []web.CategoryResponse {
+	atomic.AddInt32(&cs.findChildrenByIdsCalls, 1)
+	var categoryResponses []web.CategoryResponse
+	for _, categoryResponse := range cs.categories {
+		for _, categoryId := range categoryIds {
+			if categoryResponse.ParentId != nil && *categoryResponse.ParentId == categoryId {
+				categoryResponses = append(categoryResponses, categoryResponse)
+			}
+		}
+	}
+	return categoryResponses
+}
+
+func (cs *categoryGraphqlStub) FindAliasesByIds(ctx context.Context, categoryIds []int) []web.CategoryAliasResponse {
+	atomic.AddInt32(&cs.findAliasesByIdsCalls, 1)
+	var aliasResponses []web.CategoryAliasResponse
+	for _, categoryId := range categoryIds {
+		aliasResponses = append(aliasResponses, web.CategoryAliasResponse{Id: categoryId, CategoryId: categoryId, Alias: "Alias " + strconv.Itoa(categoryId)})
+	}
+	return aliasResponses
+}
+
+type itemGraphqlStub struct {
+	service.ItemService
+	findByCategoryIdsCalls int32
+}
+
+func (is *itemGraphqlStub) FindByCategoryIds(ctx context.Context, categoryIds []int) []web.ItemResponse {
+	atomic.AddInt32(&is.findByCategoryIdsCalls, 1)
+	return []web.ItemResponse{{Id: 1, Name: "Phone", CategoryIds: []int{1, 2}}}
+}
+
+func graphqlQuery(t *testing.T, stub *categoryGraphqlStub, query string) map[string]interface{} {
+	return graphqlQueryWithItems(t, stub, &itemGraphqlStub{}, query)
+}
+
+func graphqlQueryWithItems(t *testing.T, stub *categoryGraphqlStub, itemStub *itemGraphqlStub, query string) map[string]interface{} {
+	graphqlController := controller.NewGraphqlController(graph.NewSchema(stub), stub, itemStub)
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/graphql", strings.NewReader(string(body))).WithContext(adminContext())
+	recorder := httptest.NewRecorder()
+	graphqlController.Query(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, 200, response.StatusCode)
+
+	var responseBody map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+	return responseBody
+}
+
+func TestGraphqlChangesLoadCategoriesInOneBatch(t *testing.T) {
+	stub := &categoryGraphqlStub{}
+	for i := 1; i <= 20; i++ {
+		stub.log = append(stub.log, web.CategoryChangeResponse{Sequence: int64(i), Operation: "updated", CategoryId: i % 5})
+	}
+
+	responseBody := graphqlQuery(t, stub, `{ categoryChanges(first: 20) { nodes { sequence category { id name } } } }`)
+
+	assert.Nil(t, responseBody["errors"])
+	nodes := responseBody["data"].(map[string]interface{})["categoryChanges"].(map[string]interface{})["nodes"].([]interface{})
+	assert.Len(t, nodes, 20)
+	assert.Equal(t, "Category", nodes[0].(map[string]interface{})["category"].(map[string]interface{})["name"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.findByIdsCalls))
+}
+
+func TestGraphqlCategoriesPagination(t *testing.T) {
+	stub := &categoryGraphqlStub{categoryGrpcStub: categoryGrpcStub{categories: []web.CategoryResponse{{Id: 3}, {Id: 1}, {Id: 2}}}}
+
+	responseBody := graphqlQuery(t, stub, `{ categories(first: 2, after: "1") { nodes { id } pageInfo { endCursor hasNextPage } } }`)
+
+	categories := responseBody["data"].(map[string]interface{})["categories"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "2"}, map[string]interface{}{"id": "3"}}, categories["nodes"])
+	assert.Equal(t, map[string]interface{}{"endCursor": "3", "hasNextPage": false}, categories["pageInfo"])
+	assert.Equal(t, []web.CategoryFindAllRequest{{AfterId: 1, Limit: 3}}, stub.findAllRequests)
+}
+
+func TestGraphqlCategoryRelationsLoadInBatches(t *testing.T) {
+	one, two := 1, 2
+	stub := &categoryGraphqlStub{categoryGrpcStub: categoryGrpcStub{categories: []web.CategoryResponse{
+		{Id: 1}, {Id: 2, ParentId: &one}, {Id: 3, ParentId: &one}, {Id: 4, ParentId: &two},
+	}}}
+	itemStub := &itemGraphqlStub{}
+
+	responseBody := graphqlQueryWithItems(t, stub, itemStub, `{ categories(first: 10) { nodes {
+		id parent { name } children { id children { id } } aliases { alias } items { name }
+	} } }`)
+
+	assert.Nil(t, responseBody["errors"])
+	nodes := responseBody["data"].(map[string]interface{})["categories"].(map[string]interface{})["nodes"].([]interface{})
+	assert.Len(t, nodes, 4)
+	first, second := nodes[0].(map[string]interface{}), nodes[1].(map[string]interface{})
+	assert.Nil(t, first["parent"])
+	assert.Equal(t, map[string]interface{}{"name": "Category"}, second["parent"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "2", "children": []interface{}{map[string]interface{}{"id": "4"}}},
+		map[string]interface{}{"id": "3", "children": []interface{}{}},
+	}, first["children"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"alias": "Alias 2"}}, second["aliases"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Phone"}}, second["items"])
+	assert.Equal(t, []interface{}{}, nodes[2].(map[string]interface{})["items"])
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.findByIdsCalls))
+	// The nested children are on the page as well, so one batch loads both levels.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.findChildrenByIdsCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.findAliasesByIdsCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&itemStub.findByCategoryIdsCalls))
+}
+
+func TestGraphqlErrorCodes(t *testing.T) {
+	stub := &categoryGraphqlStub{}
+
+	responseBody := graphqlQuery(t, stub, `{ category(id: "1") { id } }`)
+	extensions := responseBody["errors"].([]interface{})[0].(map[string]interface{})["extensions"]
+	assert.Equal(t, map[string]interface{}{"code": float64(404), "status": "Not Found"}, extensions)
+
+	responseBody = graphqlQuery(t, stub, `mutation { createCategory(input: {name: ""}) { id } }`)
+	extensions = responseBody["errors"].([]interface{})[0].(map[string]interface{})["extensions"]
+	assert.Equal(t, map[string]interface{}{"code": float64(400), "status": "Bad Request"}, extensions)
+
+	responseBody = graphqlQuery(t, stub, `mutation { updateCategory(id: "1", input: {name: "Gadget"}) { id } }`)
+	graphqlError := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "internal error", graphqlError["message"])
+	assert.Equal(t, map[string]interface{}{"code": float64(500), "status": "Internal Server Error"}, graphqlError["extensions"])
+}
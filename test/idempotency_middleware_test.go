@@ -0,0 +1,148 @@
+package test
+
+import (
+	"Data-Category/app"
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/middleware"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupIdempotentHandler(calls *int) http.Handler {
+	im := middleware.NewIdempotencyMiddleware(repository.NewIdempotencyRepositoryInMemory(), app.NewIdempotencyConfig())
+
+	return exception.ErrorHandler(im.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		webResponse := web.WebResponse{
+			Code:   200,
+			Status: "OK",
+			Data:   *calls,
+		}
+		helper.WriteToResponseBody(w, webResponse)
+	})))
+}
+
+func sendIdempotentRequest(h http.Handler, key string, body string) *http.Response {
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories", strings.NewReader(body)).WithContext(adminContext())
+	request.Header.Add("Content-Type", "application/json")
+	if key != "" {
+		request.Header.Add(middleware.IdempotencyKeyHeader, key)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func TestIdempotencyReplaySameKey(t *testing.T) {
+	calls := 0
+	h := setupIdempotentHandler(&calls)
+
+	first := sendIdempotentRequest(h, "key-1", `{"name":"Gadget"}`)
+	second := sendIdempotentRequest(h, "key-1", `{"name":"Gadget"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 200, second.StatusCode)
+	assert.Equal(t, "true", second.Header.Get(middleware.IdempotentReplayedHeader))
+
+	firstBody, _ := io.ReadAll(first.Body)
+	secondBody, _ := io.ReadAll(second.Body)
+	assert.Equal(t, firstBody, secondBody)
+}
+
+func TestIdempotencyDifferentPayload(t *testing.T) {
+	calls := 0
+	h := setupIdempotentHandler(&calls)
+
+	sendIdempotentRequest(h, "key-1", `{"name":"Gadget"}`)
+	response := sendIdempotentRequest(h, "key-1", `{"name":"Laptop"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 422, response.StatusCode)
+
+	body, _ := io.ReadAll(response.Body)
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	assert.Equal(t, 422, int(responseBody["code"].(float64)))
+	assert.Equal(t, "Unprocessable Entity", responseBody["status"])
+}
+
+func TestIdempotencyDifferentAccept(t *testing.T) {
+	calls := 0
+	h := middleware.NewContentNegotiationMiddleware(helper.NewCodecRegistry()).Wrap(setupIdempotentHandler(&calls))
+	send := func(accept string) *http.Response {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories", strings.NewReader(`{"name":"Gadget"}`)).WithContext(adminContext())
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("Accept", accept)
+		request.Header.Add(middleware.IdempotencyKeyHeader, "key-1")
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, request)
+		return recorder.Result()
+	}
+
+	send("application/json")
+	assert.Equal(t, "true", send("application/json").Header.Get(middleware.IdempotentReplayedHeader))
+	response := send("application/xml")
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 422, response.StatusCode)
+	assert.Equal(t, "application/xml", response.Header.Get("Content-Type"))
+}
+
+func TestIdempotencyWithoutKey(t *testing.T) {
+	calls := 0
+	h := setupIdempotentHandler(&calls)
+
+	sendIdempotentRequest(h, "", `{"name":"Gadget"}`)
+	sendIdempotentRequest(h, "", `{"name":"Gadget"}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyRepositoryPurgesExpiredKeys(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	idempotencyRepository := repository.NewIdempotencyRepositoryInMemory()
+	idempotencyRepository.Now = func() time.Time { return now }
+	ctx := adminContext()
+
+	assert.True(t, idempotencyRepository.Save(ctx, domain.IdempotencyRecord{Key: "1:a", ExpiresAt: now.Add(time.Hour)}))
+	assert.True(t, idempotencyRepository.Save(ctx, domain.IdempotencyRecord{Key: "1:b", ExpiresAt: now.Add(3 * time.Hour)}))
+	assert.Equal(t, 2, idempotencyRepository.Len())
+
+	now = now.Add(2 * time.Hour)
+	assert.True(t, idempotencyRepository.Save(ctx, domain.IdempotencyRecord{Key: "1:c", ExpiresAt: now.Add(time.Hour)}))
+	assert.Equal(t, 2, idempotencyRepository.Len())
+	_, err := idempotencyRepository.FindByKey(ctx, "1:a")
+	assert.Error(t, err)
+}
+
+func TestIdempotencyKeyTTL(t *testing.T) {
+	calls := 0
+	im := middleware.NewIdempotencyMiddleware(repository.NewIdempotencyRepositoryInMemory(), middleware.IdempotencyConfig{TTL: time.Nanosecond})
+	h := exception.ErrorHandler(im.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		helper.WriteToResponseBody(w, web.WebResponse{Code: 200, Status: "OK"})
+	})))
+
+	sendIdempotentRequest(h, "key-1", `{"name":"Gadget"}`)
+	time.Sleep(time.Millisecond)
+	response := sendIdempotentRequest(h, "key-1", `{"name":"Gadget"}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, response.Header.Get(middleware.IdempotentReplayedHeader))
+	assert.Panics(t, func() {
+		middleware.NewIdempotencyMiddleware(repository.NewIdempotencyRepositoryInMemory(), middleware.IdempotencyConfig{})
+	})
+}
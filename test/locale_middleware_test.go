@@ -0,0 +1,60 @@
+package test
+
+import (
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resolveLocales(config middleware.LocaleConfig, target string, acceptLanguage string) ([]string, *http.Response) {
+	var locales []string
+	h := exception.ErrorHandler(middleware.NewLocaleMiddleware(config).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locales = helper.LocalesFromContext(r.Context())
+	})))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000"+target, nil)
+	if acceptLanguage != "" {
+		request.Header.Add("Accept-Language", acceptLanguage)
+	}
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	return locales, recorder.Result()
+}
+
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	locales, response := resolveLocales(middleware.LocaleConfig{}, "/api/categories", "en-GB;q=0.5, id, *;q=0.1")
+	assert.Equal(t, []string{"id", "en-GB", "en-001", "en"}, locales)
+	assert.Equal(t, "Accept-Language", response.Header.Get("Vary"))
+}
+
+func TestLocaleQueryOverridesAcceptLanguage(t *testing.T) {
+	locales, _ := resolveLocales(middleware.LocaleConfig{Fallback: []string{"id"}}, "/api/categories?lang=en-US", "id")
+	assert.Equal(t, []string{"en-US", "en", "id"}, locales)
+}
+
+func TestLocaleFallback(t *testing.T) {
+	locales, _ := resolveLocales(middleware.LocaleConfig{Fallback: []string{"en", "id"}}, "/api/categories", "")
+	assert.Equal(t, []string{"en", "id"}, locales)
+
+	locales, _ = resolveLocales(middleware.LocaleConfig{}, "/api/categories", "")
+	assert.Empty(t, locales)
+}
+
+func TestLocaleInvalidQuery(t *testing.T) {
+	_, response := resolveLocales(middleware.LocaleConfig{}, "/api/categories?lang=!!", "")
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestCanonicalLocale(t *testing.T) {
+	locale, ok := helper.CanonicalLocale("en_gb")
+	assert.True(t, ok)
+	assert.Equal(t, "en-GB", locale)
+
+	_, ok = helper.CanonicalLocale("not a locale")
+	assert.False(t, ok)
+}
@@ -0,0 +1,489 @@
+package test
+
+import (
+	"Data-Category/api"
+	"Data-Category/app"
+	"Data-Category/controller"
+	"Data-Category/exception"
+	"Data-Category/graph"
+	"Data-Category/helper"
+	"Data-Category/middleware"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"Data-Category/service"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type categoryOpenApiStub struct {
+	categoryEventsStub
+}
+
+func stubCategory(id int, name string) web.CategoryResponse {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return web.CategoryResponse{Id: id, Name: name, Slug: "gadget", Attributes: map[string]interface{}{"icon": "gadget"}, Aliases: []string{"Gizmo"}, CreatedAt: now, UpdatedAt: now, CreatedBy: "admin", UpdatedBy: "admin"}
+}
+
+func (cs *categoryOpenApiStub) Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	category := stubCategory(1, request.Name)
+	if request.Aliases != nil {
+		category.Aliases = request.Aliases
+	}
+	return category
+}
+
+func (cs *categoryOpenApiStub) FindAll(ctx context.Context, request web.CategoryFindAllRequest) []web.CategoryResponse {
+	return []web.CategoryResponse{stubCategory(1, "Gadget")}
+}
+
+func (cs *categoryOpenApiStub) FindById(ctx context.Context, categoryId int) web.CategoryResponse {
+	if categoryId != 1 {
+		panic(exception.NewNotFoundError("category is not found"))
+	}
+	category := stubCategory(1, "Gadget")
+	for _, locale := range helper.LocalesFromContext(ctx) {
+		if locale == "id" {
+			category.Name, category.Locale = "Gawai", locale
+			break
+		}
+	}
+	return category
+}
+
+func (cs *categoryOpenApiStub) FindBySlug(ctx context.Context, slug string) web.CategorySlugResponse {
+	if slug != "gadget" && slug != "gadgets" {
+		panic(exception.NewNotFoundError("category is not found"))
+	}
+	return web.CategorySlugResponse{Category: stubCategory(1, "Gadget"), Moved: slug != "gadget"}
+}
+
+func (cs *categoryOpenApiStub) UpdateById(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	return stubCategory(request.Id, request.Name)
+}
+
+func (cs *categoryOpenApiStub) DeleteById(ctx context.Context, categoryId int) {
+	cs.FindById(ctx, categoryId)
+}
+
+func (cs *categoryOpenApiStub) DeleteAll(ctx context.Context, request web.CategoryDeleteAllRequest) web.CategoryDeleteAllResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	if request.DryRun {
+		expiresAt := time.Now().Add(time.Minute)
+		return web.CategoryDeleteAllResponse{DryRun: true, Count: 1, ConfirmationToken: "token", ExpiresAt: &expiresAt}
+	}
+	if request.ConfirmationToken == "" {
+		panic(exception.NewBadRequestError("confirmation_token is required, request one with dry_run=true"))
+	}
+	if request.ConfirmationToken != "token" {
+		panic(exception.NewUnprocessableEntityError("confirmation_token is invalid"))
+	}
+	return web.CategoryDeleteAllResponse{Count: 1}
+}
+
+func (cs *categoryOpenApiStub) Search(ctx context.Context, request web.CategorySearchRequest) []web.CategorySearchResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	return []web.CategorySearchResponse{{Category: stubCategory(1, "Gadget"), Score: 0.5, Highlight: "<mark>Gadget</mark>"}}
+}
+
+func (cs *categoryOpenApiStub) Merge(ctx context.Context, request web.CategoryMergeRequest) web.CategoryResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	if request.SourceId == request.TargetId {
+		panic(exception.NewBadRequestError("a category cannot be merged into itself"))
+	}
+	return cs.FindById(ctx, request.TargetId)
+}
+
+func (cs *categoryOpenApiStub) Move(ctx context.Context, request web.CategoryMoveRequest) web.CategoryResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	category := cs.FindById(ctx, request.Id)
+	category.ParentId = request.ParentId
+	category.Position = request.Position
+	return category
+}
+
+func (cs *categoryOpenApiStub) FindChildren(ctx context.Context, categoryId int) []web.CategoryResponse {
+	cs.FindById(ctx, categoryId)
+	child := stubCategory(3, "Phone")
+	child.ParentId = &categoryId
+	return []web.CategoryResponse{child}
+}
+
+func (cs *categoryOpenApiStub) Reorder(ctx context.Context, request web.CategoryReorderRequest) []web.CategoryResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	children := []web.CategoryResponse{stubCategory(1, "Gadget")}
+	if request.ParentId != nil {
+		children = cs.FindChildren(ctx, *request.ParentId)
+	}
+	if len(request.CategoryIds) != 1 || request.CategoryIds[0] != children[0].Id {
+		panic(exception.NewUnprocessableEntityError("category_ids must list every child exactly once"))
+	}
+	return children
+}
+
+func (cs *categoryOpenApiStub) FindSubtree(ctx context.Context, categoryId int) []web.CategoryResponse {
+	return append([]web.CategoryResponse{cs.FindById(ctx, categoryId)}, cs.FindChildren(ctx, categoryId)...)
+}
+
+func (cs *categoryOpenApiStub) FindAliases(ctx context.Context, categoryId int) []web.CategoryAliasResponse {
+	cs.FindById(ctx, categoryId)
+	return []web.CategoryAliasResponse{{Id: 1, CategoryId: categoryId, Alias: "Gizmo", Slug: "gizmo", CreatedAt: time.Now(), CreatedBy: "admin"}}
+}
+
+func (cs *categoryOpenApiStub) CreateAlias(ctx context.Context, request web.CategoryAliasCreateRequest) web.CategoryAliasResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	if request.Alias == "Gadget" {
+		panic(exception.NewConflictError("category alias is already used as a name"))
+	}
+	return web.CategoryAliasResponse{Id: 1, CategoryId: request.CategoryId, Alias: request.Alias, Slug: helper.Slugify(request.Alias), CreatedAt: time.Now(), CreatedBy: "admin"}
+}
+
+func (cs *categoryOpenApiStub) DeleteAlias(ctx context.Context, categoryId int, aliasId int) {
+	if aliasId != 1 {
+		panic(exception.NewNotFoundError("category alias is not found"))
+	}
+}
+
+func (cs *categoryOpenApiStub) FindTranslations(ctx context.Context, categoryId int) []web.CategoryTranslationResponse {
+	cs.FindById(ctx, categoryId)
+	return []web.CategoryTranslationResponse{{CategoryId: categoryId, Locale: "id", Name: "Gawai", UpdatedAt: time.Now(), UpdatedBy: "admin"}}
+}
+
+func (cs *categoryOpenApiStub) SetTranslation(ctx context.Context, request web.CategoryTranslationRequest) web.CategoryTranslationResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	cs.FindById(ctx, request.CategoryId)
+	locale, _ := helper.CanonicalLocale(request.Locale)
+	return web.CategoryTranslationResponse{CategoryId: request.CategoryId, Locale: locale, Name: request.Name, UpdatedAt: time.Now(), UpdatedBy: "admin"}
+}
+
+func (cs *categoryOpenApiStub) DeleteTranslation(ctx context.Context, categoryId int, locale string) {
+	if locale != "id" {
+		panic(exception.NewNotFoundError("category translation is not found"))
+	}
+}
+
+func (cs *categoryOpenApiStub) Suggest(ctx context.Context, request web.CategorySuggestRequest) []web.CategorySuggestResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	return []web.CategorySuggestResponse{{Id: 1, Name: "Gadget", Slug: "gadget"}}
+}
+
+// setupStubRouter builds the full router around a stubbed category service,
+// so responses can be checked without a database.
+type itemOpenApiStub struct{}
+
+func stubItem(id int, name string, categoryIds []int) web.ItemResponse {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return web.ItemResponse{Id: id, Name: name, CategoryIds: categoryIds, CreatedAt: now, UpdatedAt: now, CreatedBy: "admin", UpdatedBy: "admin"}
+}
+
+func (is *itemOpenApiStub) Create(ctx context.Context, request web.ItemCreateRequest) web.ItemResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	return stubItem(1, request.Name, append([]int{}, request.CategoryIds...))
+}
+
+func (is *itemOpenApiStub) FindAll(ctx context.Context) []web.ItemResponse {
+	return []web.ItemResponse{stubItem(1, "Phone", []int{1})}
+}
+
+func (is *itemOpenApiStub) FindById(ctx context.Context, itemId int) web.ItemResponse {
+	if itemId != 1 {
+		panic(exception.NewNotFoundError("item is not found"))
+	}
+	return stubItem(1, "Phone", []int{1})
+}
+
+func (is *itemOpenApiStub) UpdateById(ctx context.Context, request web.ItemUpdateRequest) web.ItemResponse {
+	err := app.NewValidator().Struct(request)
+	if err != nil {
+		panic(err)
+	}
+	is.FindById(ctx, request.Id)
+	return stubItem(request.Id, request.Name, append([]int{}, request.CategoryIds...))
+}
+
+func (is *itemOpenApiStub) DeleteById(ctx context.Context, itemId int) {
+	is.FindById(ctx, itemId)
+}
+
+func (is *itemOpenApiStub) FindByCategory(ctx context.Context, request web.ItemFindByCategoryRequest) []web.ItemResponse {
+	if request.CategoryId != 1 {
+		panic(exception.NewNotFoundError("category is not found"))
+	}
+	return is.FindAll(ctx)
+}
+
+func (is *itemOpenApiStub) FindByCategoryIds(ctx context.Context, categoryIds []int) []web.ItemResponse {
+	return is.FindAll(ctx)
+}
+
+func setupStubRouter(categoryService service.CategoryService, config middleware.OpenApiConfig) http.Handler {
+	return app.NewRouter(
+		controller.NewCategoryController(categoryService),
+		controller.NewCacheController(nil),
+		controller.NewTenantController(&tenantServiceStub{}),
+		controller.NewWebhookController(nil),
+		controller.NewItemController(&itemOpenApiStub{}),
+		controller.NewGraphqlController(graph.NewSchema(categoryService), categoryService, &itemOpenApiStub{}),
+		controller.NewOpenApiController(),
+		middleware.NewCompressionMiddleware(app.NewCompressionConfig()),
+		middleware.NewContentNegotiationMiddleware(helper.NewCodecRegistry()),
+		middleware.NewIdempotencyMiddleware(repository.NewIdempotencyRepositoryInMemory(), app.NewIdempotencyConfig()),
+		middleware.NewRateLimitMiddleware(repository.NewRateLimitRepositoryInMemory(), app.NewRateLimitConfig()),
+		middleware.NewOpenApiMiddleware(api.NewDocument(), config),
+		middleware.NewLocaleMiddleware(middleware.LocaleConfig{}),
+	)
+}
+
+func serveStub(r http.Handler, method string, target string, body string) *http.Response {
+	request := httptest.NewRequest(method, "http://localhost:3000"+target, strings.NewReader(body)).WithContext(adminContext())
+	request.Header.Add("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func TestCategoryResponsesMatchOpenApiSpec(t *testing.T) {
+	changes := make(chan web.CategoryChangeResponse)
+	close(changes)
+	stub := &categoryOpenApiStub{categoryEventsStub{
+		changes: changes,
+		log: []web.CategoryChangeResponse{
+			{Sequence: 1, Operation: "created", CategoryId: 1, ChangedAt: time.Now(), ChangedBy: "admin"},
+			{Sequence: 2, Operation: "deleted", CategoryId: 1, ChangedAt: time.Now(), ChangedBy: "admin"},
+		},
+	}}
+	r := setupStubRouter(stub, middleware.OpenApiConfig{})
+	document := api.NewDocument()
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/api/categories", "", 200},
+		{http.MethodGet, "/api/categories?updated_since=x", "", 400},
+		{http.MethodGet, "/api/categories?attributes[icon]=gadget", "", 200},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget"}`, 200},
+		{http.MethodPost, "/api/categories", `{"name":""}`, 400},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget","parent_id":0}`, 400},
+		{http.MethodDelete, "/api/categories?dry_run=true", "", 200},
+		{http.MethodDelete, "/api/categories?dry_run=true&subtree_id=1&name=gad*", "", 200},
+		{http.MethodDelete, "/api/categories?confirmation_token=token", "", 200},
+		{http.MethodDelete, "/api/categories", "", 400},
+		{http.MethodDelete, "/api/categories?confirmation_token=forged", "", 422},
+		{http.MethodDelete, "/api/categories?dry_run=maybe", "", 400},
+		{http.MethodDelete, "/api/categories?dry_run=true&tenant_id=x", "", 400},
+		{http.MethodGet, "/api/categories/by-slug/gadget", "", 200},
+		{http.MethodGet, "/api/categories/by-slug/gadgets", "", 301},
+		{http.MethodGet, "/api/categories/by-slug/unknown", "", 404},
+		{http.MethodGet, "/api/categories/changes", "", 200},
+		{http.MethodGet, "/api/categories/changes?limit=x", "", 400},
+		{http.MethodGet, "/api/categories/search?q=gadget&limit=5", "", 200},
+		{http.MethodGet, "/api/categories/search", "", 400},
+		{http.MethodGet, "/api/categories/search?q=gadget&subtree_id=x", "", 400},
+		{http.MethodGet, "/api/categories/suggest?prefix=gad", "", 200},
+		{http.MethodGet, "/api/categories/suggest?prefix=gad&limit=51", "", 400},
+		{http.MethodGet, "/api/categories/events?last_event_id=0", "", 200},
+		{http.MethodGet, "/api/categories/1", "", 200},
+		{http.MethodGet, "/api/categories/2", "", 404},
+		{http.MethodPut, "/api/categories/1", `{"name":"Gadget"}`, 200},
+		{http.MethodPut, "/api/categories/1", `{"name":""}`, 400},
+		{http.MethodDelete, "/api/categories/1", "", 200},
+		{http.MethodDelete, "/api/categories/2", "", 404},
+		{http.MethodPost, "/api/categories/1/merge", `{"source_id":3}`, 200},
+		{http.MethodPost, "/api/categories/1/merge", `{"source_id":1}`, 400},
+		{http.MethodPost, "/api/categories/2/merge", `{"source_id":3}`, 404},
+		{http.MethodPost, "/api/categories/1/move", `{"parent_id":null,"position":2}`, 200},
+		{http.MethodPost, "/api/categories/1/move", `{"parent_id":3,"position":0}`, 200},
+		{http.MethodPost, "/api/categories/1/move", `{"position":-1}`, 400},
+		{http.MethodPost, "/api/categories/2/move", `{"position":0}`, 404},
+		{http.MethodGet, "/api/categories/1/children", "", 200},
+		{http.MethodGet, "/api/categories/2/children", "", 404},
+		{http.MethodPut, "/api/categories/1/children", `{"category_ids":[3]}`, 200},
+		{http.MethodPut, "/api/categories/1/children", `{"category_ids":[3,4]}`, 422},
+		{http.MethodPut, "/api/categories/1/children", `{"category_ids":[]}`, 400},
+		{http.MethodPut, "/api/categories/children", `{"category_ids":[1]}`, 200},
+		{http.MethodPut, "/api/categories/children", `{"category_ids":[3]}`, 422},
+		{http.MethodGet, "/api/categories/1/subtree", "", 200},
+		{http.MethodGet, "/api/categories/2/subtree", "", 404},
+		{http.MethodGet, "/api/categories/1?lang=id", "", 200},
+		{http.MethodGet, "/api/categories/1?lang=!!", "", 400},
+		{http.MethodGet, "/api/categories/1/translations", "", 200},
+		{http.MethodGet, "/api/categories/2/translations", "", 404},
+		{http.MethodPut, "/api/categories/1/translations/en_gb", `{"name":"Gadget"}`, 200},
+		{http.MethodPut, "/api/categories/1/translations/!!", `{"name":"Gadget"}`, 400},
+		{http.MethodPut, "/api/categories/1/translations/id", `{"name":""}`, 400},
+		{http.MethodDelete, "/api/categories/1/translations/id", "", 200},
+		{http.MethodDelete, "/api/categories/1/translations/fr", "", 404},
+		{http.MethodGet, "/api/categories/1/items", "", 200},
+		{http.MethodGet, "/api/categories/1/items?include_descendants=true", "", 200},
+		{http.MethodGet, "/api/categories/1/items?include_descendants=x", "", 400},
+		{http.MethodGet, "/api/categories/2/items", "", 404},
+		{http.MethodGet, "/api/items", "", 200},
+		{http.MethodPost, "/api/items", `{"name":"Phone","category_ids":[1]}`, 200},
+		{http.MethodPost, "/api/items", `{"name":"Phone"}`, 200},
+		{http.MethodPost, "/api/items", `{"name":"Phone","category_ids":[0]}`, 400},
+		{http.MethodPost, "/api/items", `{"name":""}`, 400},
+		{http.MethodGet, "/api/items/1", "", 200},
+		{http.MethodGet, "/api/items/2", "", 404},
+		{http.MethodPut, "/api/items/1", `{"name":"Phone","category_ids":[1,3]}`, 200},
+		{http.MethodPut, "/api/items/2", `{"name":"Phone"}`, 404},
+		{http.MethodDelete, "/api/items/1", "", 200},
+		{http.MethodDelete, "/api/items/2", "", 404},
+		{http.MethodGet, "/api/categories/1/aliases", "", 200},
+		{http.MethodGet, "/api/categories/2/aliases", "", 404},
+		{http.MethodPost, "/api/categories/1/aliases", `{"alias":"Gizmo"}`, 200},
+		{http.MethodPost, "/api/categories/1/aliases", `{"alias":"Gadget"}`, 409},
+		{http.MethodPost, "/api/categories/1/aliases", `{"alias":""}`, 400},
+		{http.MethodDelete, "/api/categories/1/aliases/1", "", 200},
+		{http.MethodDelete, "/api/categories/1/aliases/2", "", 404},
+	}
+
+	documented := map[string]bool{}
+	for template, pathItem := range document.Paths {
+		for method := range pathItem {
+			if strings.HasPrefix(template, "/api/categories") {
+				documented[strings.ToUpper(method)+" "+template] = false
+			}
+		}
+	}
+
+	for _, test := range tests {
+		response := serveStub(r, test.method, test.target, test.body)
+		assert.Equal(t, test.status, response.StatusCode, test.method+" "+test.target)
+
+		route, ok := document.FindRoute(test.method, strings.Split(test.target, "?")[0])
+		if !assert.True(t, ok, test.method+" "+test.target+" is not documented") {
+			continue
+		}
+		documented[test.method+" "+route.Template] = true
+
+		body, _ := io.ReadAll(response.Body)
+		err := document.ValidateResponse(route, response.StatusCode, response.Header.Get("Content-Type"), body)
+		assert.NoError(t, err, test.method+" "+test.target)
+	}
+
+	for operation, covered := range documented {
+		assert.True(t, covered, operation+" is not covered")
+	}
+}
+
+func TestOpenApiSpecServed(t *testing.T) {
+	r := setupStubRouter(&categoryOpenApiStub{}, middleware.OpenApiConfig{})
+
+	response := serveStub(r, http.MethodGet, "/api/openapi.json", "")
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, api.Spec, body)
+}
+
+func TestOpenApiRequestValidation(t *testing.T) {
+	r := setupStubRouter(&categoryOpenApiStub{}, middleware.OpenApiConfig{ValidateRequests: true})
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/api/categories", `{"name":"Gadget"}`, 200},
+		{http.MethodPost, "/api/categories", `{"name":5}`, 400},
+		{http.MethodPost, "/api/categories", `{"slug":"gadget"}`, 400},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget","slug":"Not A Slug"}`, 400},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget","attributes":{"icon":"gadget"},"attribute_schema":{"type":"object"}}`, 200},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget","attributes":"gadget"}`, 400},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget","aliases":["Gizmo"]}`, 200},
+		{http.MethodPost, "/api/categories", `{"name":"Gadget","aliases":[""]}`, 400},
+		{http.MethodPost, "/api/categories", ``, 400},
+		{http.MethodGet, "/api/categories/changes?limit=0", "", 400},
+		{http.MethodGet, "/api/categories/abc", "", 400},
+		{http.MethodGet, "/api/categories/1", "", 200},
+	}
+
+	for _, test := range tests {
+		response := serveStub(r, test.method, test.target, test.body)
+		assert.Equal(t, test.status, response.StatusCode, test.method+" "+test.target+" "+test.body)
+	}
+}
+
+func TestSchemaEnumComparesDeeply(t *testing.T) {
+	schema := &api.Schema{Enum: []interface{}{[]interface{}{"s", "m"}, map[string]interface{}{"size": "l"}}}
+	document := &api.Document{}
+
+	assert.NoError(t, document.Validate(schema, []interface{}{"s", "m"}))
+	assert.NoError(t, document.Validate(schema, map[string]interface{}{"size": "l"}))
+	assert.Error(t, document.Validate(schema, []interface{}{"s"}))
+	assert.Error(t, document.Validate(schema, "s"))
+}
+
+func TestOpenApiValidatesCodecBodies(t *testing.T) {
+	r := setupStubRouter(&categoryOpenApiStub{}, middleware.OpenApiConfig{ValidateRequests: true})
+
+	var msgpack strings.Builder
+	assert.NoError(t, helper.MsgpackCodec{}.Encode(&msgpack, map[string]interface{}{"name": "Gadget", "parent_id": 2}))
+
+	tests := []struct {
+		contentType string
+		body        string
+		status      int
+	}{
+		{"application/xml", "<category><name>Gadget</name><parent_id>2</parent_id><aliases><item>Gizmo</item></aliases></category>", 200},
+		{"application/xml", "<category><name>Gadget</name><parent_id>0</parent_id></category>", 400},
+		{"application/xml", "<category><name>Gadget</name><slug>Not A Slug</slug></category>", 400},
+		{"application/xml", "<category><name>Gadget", 400},
+		{"text/csv", "name,parent_id\nGadget,2\n", 200},
+		{"application/msgpack", msgpack.String(), 200},
+	}
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories", strings.NewReader(test.body)).WithContext(adminContext())
+		request.Header.Add("Content-Type", test.contentType)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+
+		assert.Equal(t, test.status, recorder.Code, test.contentType+" "+test.body)
+	}
+}
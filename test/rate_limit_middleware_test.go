@@ -0,0 +1,116 @@
+package test
+
+import (
+	"Data-Category/exception"
+	"Data-Category/middleware"
+	"Data-Category/model/domain"
+	"Data-Category/repository"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRateLimitMiddleware(now *time.Time) *middleware.RateLimitMiddleware {
+	rateLimitRepository := repository.NewRateLimitRepositoryInMemory()
+	rateLimitRepository.Now = func() time.Time { return *now }
+
+	return middleware.NewRateLimitMiddleware(rateLimitRepository, middleware.RateLimitConfig{
+		Read:  domain.RateLimitBudget{Limit: 2, Window: time.Minute},
+		Write: domain.RateLimitBudget{Limit: 1, Window: time.Minute},
+		Ip:    domain.RateLimitBudget{Limit: 3, Window: time.Minute},
+	})
+}
+
+func setupRateLimitedHandler(now *time.Time) http.Handler {
+	rm := setupRateLimitMiddleware(now)
+
+	return exception.ErrorHandler(rm.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+}
+
+func sendRateLimitedRequest(h http.Handler, method string, apiKey string) *http.Response {
+	request := httptest.NewRequest(method, "http://localhost:3000/api/categories", nil)
+	request.Header.Add("X-API-KEY", apiKey)
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	now := time.Now()
+	h := setupRateLimitedHandler(&now)
+
+	first := sendRateLimitedRequest(h, http.MethodGet, "RAHASIA")
+	assert.Equal(t, 200, first.StatusCode)
+	assert.Equal(t, "2", first.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header.Get("RateLimit-Remaining"))
+
+	sendRateLimitedRequest(h, http.MethodGet, "RAHASIA")
+	response := sendRateLimitedRequest(h, http.MethodGet, "RAHASIA")
+	assert.Equal(t, 429, response.StatusCode)
+	assert.Equal(t, "0", response.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", response.Header.Get("Retry-After"))
+
+	body, _ := io.ReadAll(response.Body)
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	assert.Equal(t, 429, int(responseBody["code"].(float64)))
+	assert.Equal(t, "Too Many Requests", responseBody["status"])
+}
+
+func TestRateLimitRefill(t *testing.T) {
+	now := time.Now()
+	h := setupRateLimitedHandler(&now)
+
+	assert.Equal(t, 200, sendRateLimitedRequest(h, http.MethodDelete, "RAHASIA").StatusCode)
+	assert.Equal(t, 429, sendRateLimitedRequest(h, http.MethodDelete, "RAHASIA").StatusCode)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, 200, sendRateLimitedRequest(h, http.MethodDelete, "RAHASIA").StatusCode)
+}
+
+func TestRateLimitSeparateBudgets(t *testing.T) {
+	now := time.Now()
+	h := setupRateLimitedHandler(&now)
+
+	assert.Equal(t, 200, sendRateLimitedRequest(h, http.MethodDelete, "RAHASIA").StatusCode)
+	assert.Equal(t, 429, sendRateLimitedRequest(h, http.MethodDelete, "RAHASIA").StatusCode)
+	assert.Equal(t, 200, sendRateLimitedRequest(h, http.MethodGet, "RAHASIA").StatusCode)
+	assert.Equal(t, 200, sendRateLimitedRequest(h, http.MethodDelete, "OTHER").StatusCode)
+}
+
+func TestRateLimitIpBeforeAuthentication(t *testing.T) {
+	now := time.Now()
+	rm := setupRateLimitMiddleware(&now)
+	h := rm.WrapIp(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	for _, apiKey := range []string{"GUESS-1", "GUESS-2", "GUESS-3"} {
+		assert.Equal(t, 401, sendRateLimitedRequest(h, http.MethodGet, apiKey).StatusCode)
+	}
+	response := sendRateLimitedRequest(h, http.MethodGet, "GUESS-4")
+	assert.Equal(t, 429, response.StatusCode)
+	assert.Equal(t, "3", response.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimitRejectsEmptyBudgets(t *testing.T) {
+	budget := domain.RateLimitBudget{Limit: 1, Window: time.Minute}
+	for _, config := range []middleware.RateLimitConfig{
+		{Read: domain.RateLimitBudget{Window: time.Minute}, Write: budget, Ip: budget},
+		{Read: budget, Write: domain.RateLimitBudget{Limit: 1}, Ip: budget},
+		{Read: budget, Write: budget},
+	} {
+		assert.Panics(t, func() {
+			middleware.NewRateLimitMiddleware(repository.NewRateLimitRepositoryInMemory(), config)
+		})
+	}
+}
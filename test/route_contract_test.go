@@ -0,0 +1,43 @@
+package test
+
+import (
+	"Data-Category/api"
+	"Data-Category/app"
+	"Data-Category/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesMatchOpenApiSpec(t *testing.T) {
+	r := setupStubRouter(&categoryOpenApiStub{}, middleware.OpenApiConfig{}).(*chi.Mux)
+
+	report, err := app.CheckRoutes(r, api.NewDocument())
+	assert.NoError(t, err)
+	assert.True(t, report.Ok(), report.String())
+}
+
+func TestRouteReportListsDrift(t *testing.T) {
+	document, err := api.ParseDocument([]byte(`{
+		"servers": [{"url": "http://localhost:3000/api"}],
+		"paths": {
+			"/items/{itemId}": {"get": {}, "delete": {}}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Route("/api/items", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Get("/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	report, err := app.CheckRoutes(r, document)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /api/items"}, report.Undocumented)
+	assert.Equal(t, []string{"DELETE /api/items/{itemId}"}, report.Missing)
+	assert.Equal(t, "1 route(s) not documented in APIspec.json:\n  GET /api/items\n"+
+		"1 operation(s) documented in APIspec.json but not routed:\n  DELETE /api/items/{itemId}\n", report.String())
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"Data-Category/app"
+	"Data-Category/helper"
+	"Data-Category/model/web"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "gadget", helper.Slugify("Gadget"))
+	assert.Equal(t, "home-garden", helper.Slugify("  Home / Garden  "))
+	assert.Equal(t, "creme-brulee-and-co", helper.Slugify("Crème Brûlée & Co"))
+	assert.Equal(t, "strasse-smorrebrod", helper.Slugify("Straße Smørrebrød"))
+	assert.Equal(t, "category", helper.Slugify("家電"))
+	assert.True(t, helper.IsSlug(helper.Slugify("Ponsel & Tablet, Aksesoris!")))
+}
+
+func TestSlugValidation(t *testing.T) {
+	validate := app.NewValidator()
+
+	assert.Nil(t, validate.Struct(web.CategoryCreateRequest{Name: "Gadget", Slug: "gadget-2"}))
+	assert.Nil(t, validate.Struct(web.CategoryCreateRequest{Name: "Gadget"}))
+	assert.NotNil(t, validate.Struct(web.CategoryCreateRequest{Name: "Gadget", Slug: "Gadget Baru"}))
+	assert.NotNil(t, validate.Struct(web.CategoryCreateRequest{Name: "Gadget", Slug: "gadget-"}))
+}
+
+func TestFold(t *testing.T) {
+	assert.Equal(t, "creme brulee co", helper.Fold("  Crème Brûlée & Co "))
+	assert.Equal(t, "strasse 2", helper.Fold("STRASSE-2"))
+	assert.Equal(t, "strasse", helper.Fold("Straße"))
+	assert.Equal(t, "家電", helper.Fold("家電"))
+}
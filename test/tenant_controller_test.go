@@ -0,0 +1,114 @@
+package test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func truncateTenant(db *sql.DB) {
+	truncateDataCategory(db)
+	db.Exec("DELETE FROM tenant WHERE id <> 1")
+}
+
+func sendRequest(r http.Handler, method string, url string, body string, apiKey string) (*http.Response, map[string]interface{}) {
+	var requestBody io.Reader
+	if body != "" {
+		requestBody = strings.NewReader(body)
+	}
+	request := httptest.NewRequest(method, url, requestBody)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("X-API-KEY", apiKey)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	responseBytes, _ := io.ReadAll(response.Body)
+	var responseBody map[string]interface{}
+	json.Unmarshal(responseBytes, &responseBody)
+	return response, responseBody
+}
+
+func createTenant(r http.Handler, name string) (int, string) {
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/tenants", `{"name":"`+name+`"}`, "RAHASIA")
+	tenant := responseBody["data"].(map[string]interface{})
+	return int(tenant["id"].(float64)), tenant["api_key"].(string)
+}
+
+func TestCreateTenantSuccess(t *testing.T) {
+	db := setupNewDB()
+	truncateTenant(db)
+	r := setupRouter(db)
+
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/tenants", `{"name":"Storefront"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+
+	tenant := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "Storefront", tenant["name"])
+	assert.Equal(t, false, tenant["disabled"])
+	assert.NotEmpty(t, tenant["api_key"])
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/tenants", "", "RAHASIA")
+	assert.Len(t, responseBody["data"].([]interface{}), 2)
+}
+
+func TestTenantEndpointsRequireAdmin(t *testing.T) {
+	db := setupNewDB()
+	truncateTenant(db)
+	r := setupRouter(db)
+
+	_, apiKey := createTenant(r, "Storefront")
+
+	response, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/tenants", "", apiKey)
+	assert.Equal(t, 403, response.StatusCode)
+	assert.Equal(t, "Forbidden", responseBody["status"])
+}
+
+func TestCategoriesIsolatedPerTenant(t *testing.T) {
+	db := setupNewDB()
+	truncateTenant(db)
+	r := setupRouter(db)
+
+	_, apiKey := createTenant(r, "Storefront")
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, apiKey)
+	categoryId := int(responseBody["data"].(map[string]interface{})["id"].(float64))
+
+	response, _ := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories/"+strconv.Itoa(categoryId), "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodDelete, "http://localhost:3000/api/categories/"+strconv.Itoa(categoryId), "", "RAHASIA")
+	assert.Equal(t, 404, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, _ = sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, apiKey)
+	assert.Equal(t, 409, response.StatusCode)
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", apiKey)
+	assert.Len(t, responseBody["data"].([]interface{}), 1)
+}
+
+func TestDisabledTenantUnauthorized(t *testing.T) {
+	db := setupNewDB()
+	truncateTenant(db)
+	r := setupRouter(db)
+
+	tenantId, apiKey := createTenant(r, "Storefront")
+
+	response, _ := sendRequest(r, http.MethodPost, "http://localhost:3000/api/tenants/"+strconv.Itoa(tenantId)+"/disable", "", "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, responseBody := sendRequest(r, http.MethodGet, "http://localhost:3000/api/categories", "", apiKey)
+	assert.Equal(t, 401, response.StatusCode)
+	assert.Equal(t, "Unauthorized", responseBody["status"])
+}
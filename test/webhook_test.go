@@ -0,0 +1,199 @@
+package test
+
+import (
+	"Data-Category/app"
+	"Data-Category/exception"
+	"Data-Category/helper"
+	"Data-Category/model/domain"
+	"Data-Category/model/web"
+	"Data-Category/repository"
+	"Data-Category/service"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const webhookSecret = "0123456789abcdef"
+
+type webhookReceiver struct {
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	wr.requests = append(wr.requests, r)
+	wr.bodies = append(wr.bodies, body)
+	w.WriteHeader(wr.status)
+}
+
+// setupWebhookConfig allows private targets, since the receivers in these
+// tests listen on the loopback address.
+func setupWebhookConfig(maxAttempts int) service.WebhookConfig {
+	return service.WebhookConfig{
+		PollInterval: time.Second,
+		BatchSize:    20,
+		MaxAttempts:  maxAttempts,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+		Timeout:      time.Second,
+
+		AllowPrivateTargets: true,
+	}
+}
+
+func setupWebhookWorker(db *sql.DB, maxAttempts int) *service.WebhookWorker {
+	return service.NewWebhookWorker(repository.NewWebhookRepository(helper.NewClock()), db, helper.NewClock(), setupWebhookConfig(maxAttempts))
+}
+
+func TestWebhookDeliverSignsPayload(t *testing.T) {
+	receiver := &webhookReceiver{status: 204}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	worker := setupWebhookWorker(nil, 3)
+	delivery := worker.Deliver(context.Background(), domain.WebhookOutbox{
+		Id:             7,
+		SubscriptionId: 1,
+		EventType:      domain.WebhookCategoryCreated,
+		Payload:        []byte(`{"event_type":"category.created"}`),
+		Url:            server.URL,
+		Secret:         webhookSecret,
+	})
+
+	assert.Equal(t, domain.WebhookDelivered, delivery.Outcome)
+	assert.Equal(t, 204, delivery.StatusCode)
+	assert.Equal(t, 1, delivery.Attempt)
+
+	request := receiver.requests[0]
+	assert.Equal(t, "7", request.Header.Get(service.WebhookIdHeader))
+	assert.Equal(t, domain.WebhookCategoryCreated, request.Header.Get(service.WebhookEventHeader))
+	signature := service.SignWebhookPayload(webhookSecret, request.Header.Get(service.WebhookTimestampHeader), receiver.bodies[0])
+	assert.Equal(t, signature, request.Header.Get(service.WebhookSignatureHeader))
+}
+
+func TestWebhookDeliverFailure(t *testing.T) {
+	receiver := &webhookReceiver{status: 500}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	worker := setupWebhookWorker(nil, 3)
+	delivery := worker.Deliver(context.Background(), domain.WebhookOutbox{Attempts: 1, Url: server.URL, Secret: webhookSecret})
+
+	assert.Equal(t, domain.WebhookFailed, delivery.Outcome)
+	assert.Equal(t, 500, delivery.StatusCode)
+	assert.Equal(t, 2, delivery.Attempt)
+	assert.NotEmpty(t, delivery.Error)
+}
+
+func TestWebhookDeliverRefusesPrivateTargets(t *testing.T) {
+	receiver := &webhookReceiver{status: 204}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	config := setupWebhookConfig(3)
+	config.AllowPrivateTargets = false
+	worker := service.NewWebhookWorker(repository.NewWebhookRepository(helper.NewClock()), nil, helper.NewClock(), config)
+	delivery := worker.Deliver(context.Background(), domain.WebhookOutbox{Url: server.URL, Secret: webhookSecret})
+
+	assert.Equal(t, domain.WebhookFailed, delivery.Outcome)
+	assert.Contains(t, delivery.Error, "not a public address")
+	assert.Empty(t, receiver.requests)
+}
+
+func TestWebhookCreateRejectsPrivateTargets(t *testing.T) {
+	config := setupWebhookConfig(3)
+	config.AllowPrivateTargets = false
+	webhookService := service.NewWebhookService(repository.NewWebhookRepository(helper.NewClock()), nil, app.NewValidator(), config)
+
+	for _, target := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+	} {
+		assert.PanicsWithValue(t, exception.NewBadRequestError("webhook url must not point to a loopback, private or link-local address"), func() {
+			webhookService.Create(context.Background(), web.WebhookCreateRequest{
+				Url:        target,
+				EventTypes: []string{domain.WebhookCategoryCreated},
+				Secret:     webhookSecret,
+			})
+		}, target)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	worker := setupWebhookWorker(nil, 3)
+
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		backoff := worker.Backoff(attempt + 1)
+		assert.GreaterOrEqual(t, backoff, max/2)
+		assert.LessOrEqual(t, backoff, max)
+	}
+	assert.LessOrEqual(t, worker.Backoff(100), time.Minute)
+}
+
+func TestWebhookDeliveredAfterCategoryCreated(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	receiver := &webhookReceiver{status: 200}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	response, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/webhooks",
+		`{"url":"`+server.URL+`","event_types":["category.created"],"secret":"`+webhookSecret+`"}`, "RAHASIA")
+	assert.Equal(t, 200, response.StatusCode)
+	webhook := responseBody["data"].(map[string]interface{})
+	assert.Nil(t, webhook["secret"])
+	webhookId := strconv.Itoa(int(webhook["id"].(float64)))
+
+	sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+
+	assert.Equal(t, 1, setupWebhookWorker(db, 3).RunOnce(context.Background()))
+	assert.Len(t, receiver.requests, 1)
+	assert.Contains(t, string(receiver.bodies[0]), `"name":"Gadget"`)
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/webhooks/"+webhookId+"/deliveries", "", "RAHASIA")
+	deliveries := responseBody["data"].([]interface{})
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, domain.WebhookDelivered, deliveries[0].(map[string]interface{})["outcome"])
+}
+
+func TestWebhookDeadLettered(t *testing.T) {
+	db := setupNewDB()
+	truncateDataCategory(db)
+	r := setupRouter(db)
+
+	receiver := &webhookReceiver{status: 503}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	_, responseBody := sendRequest(r, http.MethodPost, "http://localhost:3000/api/webhooks",
+		`{"url":"`+server.URL+`","event_types":["category.created"],"secret":"`+webhookSecret+`"}`, "RAHASIA")
+	webhookId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	sendRequest(r, http.MethodPost, "http://localhost:3000/api/categories", `{"name":"Gadget"}`, "RAHASIA")
+
+	worker := setupWebhookWorker(db, 1)
+	assert.Equal(t, 1, worker.RunOnce(context.Background()))
+	assert.Equal(t, 0, worker.RunOnce(context.Background()))
+
+	_, responseBody = sendRequest(r, http.MethodGet, "http://localhost:3000/api/webhooks/"+webhookId+"/deliveries", "", "RAHASIA")
+	delivery := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, domain.WebhookDead, delivery["outcome"])
+	assert.Equal(t, 503, int(delivery["status_code"].(float64)))
+}
@@ -4,36 +4,159 @@
 package main
 
 import (
+	"Data-Category/api"
 	"Data-Category/app"
 	"Data-Category/controller"
+	"Data-Category/event"
+	"Data-Category/graph"
+	"Data-Category/helper"
 	"Data-Category/middleware"
+	"Data-Category/pb"
 	"Data-Category/repository"
 	"Data-Category/service"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/wire"
 )
 
+var eventSet = wire.NewSet(
+	app.NewEventBusConfig,
+	event.NewLogSubscriber,
+	app.NewEventSubscribers,
+	app.NewEventBus,
+)
+
 var categorySet = wire.NewSet(
 	repository.NewCategoryRepository,
 	wire.Bind(new(repository.CategoryRepository), new(*repository.CategoryRepositoryImpl)),
+	repository.NewCategoryAliasRepository,
+	wire.Bind(new(repository.CategoryAliasRepository), new(*repository.CategoryAliasRepositoryImpl)),
+	repository.NewCategoryTranslationRepository,
+	wire.Bind(new(repository.CategoryTranslationRepository), new(*repository.CategoryTranslationRepositoryImpl)),
+	repository.NewCategoryChangeRepository,
+	wire.Bind(new(repository.CategoryChangeRepository), new(*repository.CategoryChangeRepositoryImpl)),
+	repository.NewWebhookRepository,
+	wire.Bind(new(repository.WebhookRepository), new(*repository.WebhookRepositoryImpl)),
+	service.NewCategoryChangeBroker,
+	app.NewCategorySuggestConfig,
+	service.NewCategorySuggestIndex,
+	app.NewCategoryDeleteConfig,
+	service.NewCategoryDeleteGuard,
 	service.NewCategoryService,
-	wire.Bind(new(service.CategoryService), new(*service.CategoryServiceImpl)),
+	app.NewCategoryCacheConfig,
+	app.NewCategoryServiceCached,
+	wire.Bind(new(service.CategoryService), new(*service.CategoryServiceCached)),
+	wire.Bind(new(service.CategoryToucher), new(*service.CategoryServiceImpl)),
+	wire.Bind(new(service.CategoryInvalidator), new(*service.CategoryServiceCached)),
 	controller.NewCategoryController,
 	wire.Bind(new(controller.CategoryController), new(*controller.CategoryControllerImpl)),
+	controller.NewCacheController,
+	wire.Bind(new(controller.CacheController), new(*controller.CacheControllerImpl)),
+)
+
+var itemSet = wire.NewSet(
+	repository.NewItemRepository,
+	wire.Bind(new(repository.ItemRepository), new(*repository.ItemRepositoryImpl)),
+	service.NewItemService,
+	wire.Bind(new(service.ItemService), new(*service.ItemServiceImpl)),
+	controller.NewItemController,
+	wire.Bind(new(controller.ItemController), new(*controller.ItemControllerImpl)),
+)
+
+var graphqlSet = wire.NewSet(
+	graph.NewSchema,
+	controller.NewGraphqlController,
+	wire.Bind(new(controller.GraphqlController), new(*controller.GraphqlControllerImpl)),
+)
+
+var tenantSet = wire.NewSet(
+	app.NewTenantConfig,
+	repository.NewTenantRepository,
+	wire.Bind(new(repository.TenantRepository), new(*repository.TenantRepositoryImpl)),
+	service.NewTenantService,
+	wire.Bind(new(service.TenantService), new(*service.TenantServiceImpl)),
+	controller.NewTenantController,
+	wire.Bind(new(controller.TenantController), new(*controller.TenantControllerImpl)),
+)
+
+var webhookSet = wire.NewSet(
+	service.NewWebhookService,
+	wire.Bind(new(service.WebhookService), new(*service.WebhookServiceImpl)),
+	controller.NewWebhookController,
+	wire.Bind(new(controller.WebhookController), new(*controller.WebhookControllerImpl)),
+	app.NewWebhookConfig,
+	service.NewWebhookWorker,
+)
+
+var grpcSet = wire.NewSet(
+	app.NewGrpcConfig,
+	controller.NewCategoryGrpcServer,
+	wire.Bind(new(pb.CategoryServiceServer), new(*controller.CategoryGrpcServer)),
+	middleware.NewAuthInterceptor,
+	app.NewGrpcServer,
+)
+
+var openApiSet = wire.NewSet(
+	api.NewDocument,
+	app.NewOpenApiConfig,
+	middleware.NewOpenApiMiddleware,
+	controller.NewOpenApiController,
+	wire.Bind(new(controller.OpenApiController), new(*controller.OpenApiControllerImpl)),
+)
+
+var contentNegotiationSet = wire.NewSet(
+	helper.NewCodecRegistry,
+	middleware.NewContentNegotiationMiddleware,
+)
+
+var compressionSet = wire.NewSet(
+	app.NewCompressionConfig,
+	middleware.NewCompressionMiddleware,
+)
+
+var idempotencySet = wire.NewSet(
+	app.NewIdempotencyConfig,
+	repository.NewIdempotencyRepository,
+	wire.Bind(new(repository.IdempotencyRepository), new(*repository.IdempotencyRepositoryImpl)),
+	middleware.NewIdempotencyMiddleware,
+)
+
+var localeSet = wire.NewSet(
+	app.NewLocaleConfig,
+	middleware.NewLocaleMiddleware,
+)
+
+var rateLimitSet = wire.NewSet(
+	app.NewRateLimitConfig,
+	repository.NewRateLimitRepositoryInMemory,
+	wire.Bind(new(repository.RateLimitRepository), new(*repository.RateLimitRepositoryInMemory)),
+	middleware.NewRateLimitMiddleware,
 )
 
-func InitializeServer() *http.Server {
+func InitializeApp() *App {
 	wire.Build(
 		app.NewDB,
-		validator.New,
+		app.NewValidator,
+		helper.NewClock,
+		eventSet,
 		categorySet,
+		itemSet,
+		graphqlSet,
+		tenantSet,
+		webhookSet,
+		grpcSet,
+		openApiSet,
+		contentNegotiationSet,
+		compressionSet,
+		idempotencySet,
+		rateLimitSet,
+		localeSet,
 		app.NewRouter,
 		wire.Bind(new(http.Handler), new(*chi.Mux)),
 		middleware.NewAuthMiddleware,
 		NewServer,
+		NewApp,
 	)
 	return nil
 }
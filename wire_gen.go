@@ -7,14 +7,17 @@
 package main
 
 import (
+	"Data-Category/api"
 	"Data-Category/app"
 	"Data-Category/controller"
+	"Data-Category/event"
+	"Data-Category/graph"
+	"Data-Category/helper"
 	"Data-Category/middleware"
+	"Data-Category/pb"
 	"Data-Category/repository"
 	"Data-Category/service"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/wire"
-	"net/http"
 )
 
 import (
@@ -23,18 +26,93 @@ import (
 
 // Injectors from wire.go:
 
-func InitializeServer() *http.Server {
-	categoryRepositoryImpl := repository.NewCategoryRepository()
+func InitializeApp() *App {
+	clock := helper.NewClock()
+	categoryRepositoryImpl := repository.NewCategoryRepository(clock)
+	categoryAliasRepositoryImpl := repository.NewCategoryAliasRepository(clock)
+	categoryTranslationRepositoryImpl := repository.NewCategoryTranslationRepository(clock)
+	itemRepositoryImpl := repository.NewItemRepository(clock)
+	categoryChangeRepositoryImpl := repository.NewCategoryChangeRepository(clock)
+	webhookRepositoryImpl := repository.NewWebhookRepository(clock)
+	categoryChangeBroker := service.NewCategoryChangeBroker()
+	eventBusConfig := app.NewEventBusConfig()
+	logSubscriber := event.NewLogSubscriber()
+	tenantRepositoryImpl := repository.NewTenantRepository()
 	db := app.NewDB()
-	validate := validator.New()
-	categoryServiceImpl := service.NewCategoryService(categoryRepositoryImpl, db, validate)
-	categoryControllerImpl := controller.NewCategoryController(categoryServiceImpl)
-	mux := app.NewRouter(categoryControllerImpl)
-	authMiddleware := middleware.NewAuthMiddleware(mux)
-	server := NewServer(authMiddleware)
-	return server
+	categorySuggestConfig := app.NewCategorySuggestConfig()
+	categorySuggestIndex := service.NewCategorySuggestIndex(categoryRepositoryImpl, categoryChangeRepositoryImpl, tenantRepositoryImpl, db, categorySuggestConfig)
+	v := app.NewEventSubscribers(eventBusConfig, logSubscriber, categorySuggestIndex)
+	eventBus := app.NewEventBus(eventBusConfig, v)
+	categoryDeleteConfig := app.NewCategoryDeleteConfig()
+	categoryDeleteGuard := service.NewCategoryDeleteGuard(tenantRepositoryImpl, clock, categoryDeleteConfig)
+	validate := app.NewValidator()
+	categoryServiceImpl := service.NewCategoryService(categoryRepositoryImpl, categoryAliasRepositoryImpl, categoryTranslationRepositoryImpl, itemRepositoryImpl, categoryChangeRepositoryImpl, webhookRepositoryImpl, categoryChangeBroker, eventBus, categorySuggestIndex, categoryDeleteGuard, db, validate)
+	categoryCacheConfig := app.NewCategoryCacheConfig()
+	categoryServiceCached := app.NewCategoryServiceCached(categoryServiceImpl, categoryCacheConfig)
+	categoryControllerImpl := controller.NewCategoryController(categoryServiceCached)
+	cacheControllerImpl := controller.NewCacheController(categoryServiceCached)
+	tenantConfig := app.NewTenantConfig()
+	tenantServiceImpl := service.NewTenantService(tenantRepositoryImpl, db, validate, tenantConfig)
+	tenantControllerImpl := controller.NewTenantController(tenantServiceImpl)
+	webhookConfig := app.NewWebhookConfig()
+	webhookServiceImpl := service.NewWebhookService(webhookRepositoryImpl, db, validate, webhookConfig)
+	webhookControllerImpl := controller.NewWebhookController(webhookServiceImpl)
+	itemServiceImpl := service.NewItemService(itemRepositoryImpl, categoryRepositoryImpl, categoryChangeRepositoryImpl, categoryServiceImpl, categoryServiceCached, db, validate)
+	itemControllerImpl := controller.NewItemController(itemServiceImpl)
+	schema := graph.NewSchema(categoryServiceCached)
+	graphqlControllerImpl := controller.NewGraphqlController(schema, categoryServiceCached, itemServiceImpl)
+	openApiControllerImpl := controller.NewOpenApiController()
+	compressionConfig := app.NewCompressionConfig()
+	compressionMiddleware := middleware.NewCompressionMiddleware(compressionConfig)
+	codecRegistry := helper.NewCodecRegistry()
+	contentNegotiationMiddleware := middleware.NewContentNegotiationMiddleware(codecRegistry)
+	idempotencyRepositoryImpl := repository.NewIdempotencyRepository(db)
+	idempotencyConfig := app.NewIdempotencyConfig()
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyRepositoryImpl, idempotencyConfig)
+	rateLimitRepositoryInMemory := repository.NewRateLimitRepositoryInMemory()
+	rateLimitConfig := app.NewRateLimitConfig()
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitRepositoryInMemory, rateLimitConfig)
+	document := api.NewDocument()
+	openApiConfig := app.NewOpenApiConfig()
+	openApiMiddleware := middleware.NewOpenApiMiddleware(document, openApiConfig)
+	localeConfig := app.NewLocaleConfig()
+	localeMiddleware := middleware.NewLocaleMiddleware(localeConfig)
+	mux := app.NewRouter(categoryControllerImpl, cacheControllerImpl, tenantControllerImpl, webhookControllerImpl, itemControllerImpl, graphqlControllerImpl, openApiControllerImpl, compressionMiddleware, contentNegotiationMiddleware, idempotencyMiddleware, rateLimitMiddleware, openApiMiddleware, localeMiddleware)
+	authMiddleware := middleware.NewAuthMiddleware(mux, tenantServiceImpl)
+	server := NewServer(authMiddleware, rateLimitMiddleware)
+	categoryGrpcServer := controller.NewCategoryGrpcServer(categoryServiceCached)
+	authInterceptor := middleware.NewAuthInterceptor(tenantServiceImpl)
+	grpcServer := app.NewGrpcServer(categoryGrpcServer, authInterceptor)
+	grpcConfig := app.NewGrpcConfig()
+	webhookWorker := service.NewWebhookWorker(webhookRepositoryImpl, db, clock, webhookConfig)
+	mainApp := NewApp(server, mux, grpcServer, grpcConfig, webhookWorker, categorySuggestIndex)
+	return mainApp
 }
 
 // wire.go:
 
-var categorySet = wire.NewSet(repository.NewCategoryRepository, wire.Bind(new(repository.CategoryRepository), new(*repository.CategoryRepositoryImpl)), service.NewCategoryService, wire.Bind(new(service.CategoryService), new(*service.CategoryServiceImpl)), controller.NewCategoryController, wire.Bind(new(controller.CategoryController), new(*controller.CategoryControllerImpl)))
+var eventSet = wire.NewSet(app.NewEventBusConfig, event.NewLogSubscriber, app.NewEventSubscribers, app.NewEventBus)
+
+var categorySet = wire.NewSet(repository.NewCategoryRepository, wire.Bind(new(repository.CategoryRepository), new(*repository.CategoryRepositoryImpl)), repository.NewCategoryAliasRepository, wire.Bind(new(repository.CategoryAliasRepository), new(*repository.CategoryAliasRepositoryImpl)), repository.NewCategoryTranslationRepository, wire.Bind(new(repository.CategoryTranslationRepository), new(*repository.CategoryTranslationRepositoryImpl)), repository.NewCategoryChangeRepository, wire.Bind(new(repository.CategoryChangeRepository), new(*repository.CategoryChangeRepositoryImpl)), repository.NewWebhookRepository, wire.Bind(new(repository.WebhookRepository), new(*repository.WebhookRepositoryImpl)), service.NewCategoryChangeBroker, app.NewCategorySuggestConfig, service.NewCategorySuggestIndex, app.NewCategoryDeleteConfig, service.NewCategoryDeleteGuard, service.NewCategoryService, app.NewCategoryCacheConfig, app.NewCategoryServiceCached, wire.Bind(new(service.CategoryService), new(*service.CategoryServiceCached)), wire.Bind(new(service.CategoryToucher), new(*service.CategoryServiceImpl)), wire.Bind(new(service.CategoryInvalidator), new(*service.CategoryServiceCached)), controller.NewCategoryController, wire.Bind(new(controller.CategoryController), new(*controller.CategoryControllerImpl)), controller.NewCacheController, wire.Bind(new(controller.CacheController), new(*controller.CacheControllerImpl)))
+
+var itemSet = wire.NewSet(repository.NewItemRepository, wire.Bind(new(repository.ItemRepository), new(*repository.ItemRepositoryImpl)), service.NewItemService, wire.Bind(new(service.ItemService), new(*service.ItemServiceImpl)), controller.NewItemController, wire.Bind(new(controller.ItemController), new(*controller.ItemControllerImpl)))
+
+var graphqlSet = wire.NewSet(graph.NewSchema, controller.NewGraphqlController, wire.Bind(new(controller.GraphqlController), new(*controller.GraphqlControllerImpl)))
+
+var tenantSet = wire.NewSet(app.NewTenantConfig, repository.NewTenantRepository, wire.Bind(new(repository.TenantRepository), new(*repository.TenantRepositoryImpl)), service.NewTenantService, wire.Bind(new(service.TenantService), new(*service.TenantServiceImpl)), controller.NewTenantController, wire.Bind(new(controller.TenantController), new(*controller.TenantControllerImpl)))
+
+var webhookSet = wire.NewSet(service.NewWebhookService, wire.Bind(new(service.WebhookService), new(*service.WebhookServiceImpl)), controller.NewWebhookController, wire.Bind(new(controller.WebhookController), new(*controller.WebhookControllerImpl)), app.NewWebhookConfig, service.NewWebhookWorker)
+
+var grpcSet = wire.NewSet(app.NewGrpcConfig, controller.NewCategoryGrpcServer, wire.Bind(new(pb.CategoryServiceServer), new(*controller.CategoryGrpcServer)), middleware.NewAuthInterceptor, app.NewGrpcServer)
+
+var openApiSet = wire.NewSet(api.NewDocument, app.NewOpenApiConfig, middleware.NewOpenApiMiddleware, controller.NewOpenApiController, wire.Bind(new(controller.OpenApiController), new(*controller.OpenApiControllerImpl)))
+
+var contentNegotiationSet = wire.NewSet(helper.NewCodecRegistry, middleware.NewContentNegotiationMiddleware)
+
+var compressionSet = wire.NewSet(app.NewCompressionConfig, middleware.NewCompressionMiddleware)
+
+var idempotencySet = wire.NewSet(app.NewIdempotencyConfig, repository.NewIdempotencyRepository, wire.Bind(new(repository.IdempotencyRepository), new(*repository.IdempotencyRepositoryImpl)), middleware.NewIdempotencyMiddleware)
+
+var localeSet = wire.NewSet(app.NewLocaleConfig, middleware.NewLocaleMiddleware)
+
+var rateLimitSet = wire.NewSet(app.NewRateLimitConfig, repository.NewRateLimitRepositoryInMemory, wire.Bind(new(repository.RateLimitRepository), new(*repository.RateLimitRepositoryInMemory)), middleware.NewRateLimitMiddleware)